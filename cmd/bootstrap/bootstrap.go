@@ -2,15 +2,18 @@ package bootstrap
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"log/slog"
 	"os"
 
+	"github.com/Ablebil/lathi-be/db/bundle"
 	"github.com/Ablebil/lathi-be/db/migration"
 	"github.com/Ablebil/lathi-be/db/seed"
 	"github.com/Ablebil/lathi-be/internal/config"
 	"github.com/Ablebil/lathi-be/internal/domain/contract"
+	"github.com/Ablebil/lathi-be/internal/domain/entity"
 	cronJob "github.com/Ablebil/lathi-be/internal/infra/cron"
 	"github.com/Ablebil/lathi-be/internal/infra/fiber"
 	"github.com/Ablebil/lathi-be/internal/infra/minio"
@@ -21,6 +24,8 @@ import (
 	"github.com/Ablebil/lathi-be/pkg/jwt"
 	"github.com/Ablebil/lathi-be/pkg/mail"
 	"github.com/Ablebil/lathi-be/pkg/validator"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
 
 	authHdl "github.com/Ablebil/lathi-be/internal/app/auth/handler"
 	authUc "github.com/Ablebil/lathi-be/internal/app/auth/usecase"
@@ -29,6 +34,7 @@ import (
 	userRepo "github.com/Ablebil/lathi-be/internal/app/user/repository"
 	userUc "github.com/Ablebil/lathi-be/internal/app/user/usecase"
 
+	"github.com/Ablebil/lathi-be/internal/app/story/graph"
 	storyHdl "github.com/Ablebil/lathi-be/internal/app/story/handler"
 	storyRepo "github.com/Ablebil/lathi-be/internal/app/story/repository"
 	storyUc "github.com/Ablebil/lathi-be/internal/app/story/usecase"
@@ -40,6 +46,13 @@ import (
 	lbHdl "github.com/Ablebil/lathi-be/internal/app/leaderboard/handler"
 	lbRepo "github.com/Ablebil/lathi-be/internal/app/leaderboard/repository"
 	lbUc "github.com/Ablebil/lathi-be/internal/app/leaderboard/usecase"
+
+	outboxRepo "github.com/Ablebil/lathi-be/internal/app/outbox/repository"
+	outboxUc "github.com/Ablebil/lathi-be/internal/app/outbox/usecase"
+
+	adminHdl "github.com/Ablebil/lathi-be/internal/app/admin/handler"
+	adminRepo "github.com/Ablebil/lathi-be/internal/app/admin/repository"
+	adminUc "github.com/Ablebil/lathi-be/internal/app/admin/usecase"
 )
 
 func Start() error {
@@ -48,6 +61,9 @@ func Start() error {
 		panic(err)
 	}
 
+	// commands connect to what they need themselves
+	handleArgs(env)
+
 	db, err := postgresql.New(env)
 	if err != nil {
 		panic(err)
@@ -60,8 +76,6 @@ func Start() error {
 
 	cache := redis.New(env)
 
-	handleArgs(env)
-
 	app := fiber.New(env)
 	v1 := app.Group("/api/v1")
 
@@ -70,6 +84,8 @@ func Start() error {
 	mail := mail.NewMail(env)
 	jwt := jwt.NewJWT(env)
 	mw := middleware.NewMiddleware(jwt, cache, env)
+	app.Use(mw.Localize)
+	tx := postgresql.NewTransactor(db)
 
 	// auth module
 	userRepository := userRepo.NewUserRepository(db)
@@ -83,9 +99,13 @@ func Start() error {
 
 	handleLeaderboardRebuild(leaderboardRepository)
 
+	// outbox module
+	outboxRepository := outboxRepo.NewOutboxRepository(db)
+	outboxUsecase := outboxUc.NewOutboxUsecase(outboxRepository, tx, leaderboardRepository)
+
 	// story module
 	storyRepository := storyRepo.NewStoryRepository(db)
-	storyUsecase := storyUc.NewStoryUsecase(storyRepository, userRepository, leaderboardRepository, storage, env)
+	storyUsecase := storyUc.NewStoryUsecase(storyRepository, userRepository, outboxRepository, tx, storage, env)
 	storyHdl.NewStoryHandler(v1, val, mw, storyUsecase)
 
 	// dictionary module
@@ -97,7 +117,12 @@ func Start() error {
 	userUsecase := userUc.NewUserUsecase(userRepository, storyRepository, dictionaryRepository, leaderboardRepository, storage, cache, env)
 	userHdl.NewUserHandler(v1, val, env, mw, userUsecase)
 
-	cron := cronJob.NewCronJob(userRepository, leaderboardRepository)
+	// admin module
+	adminRepository := adminRepo.NewAdminRepository(db)
+	adminUsecase := adminUc.NewAdminUsecase(adminRepository, leaderboardRepository, tx, env)
+	adminHdl.NewAdminHandler(v1, val, mw, adminUsecase)
+
+	cron := cronJob.NewCronJob(userRepository, leaderboardRepository, outboxRepository, outboxUsecase)
 	cron.Start()
 
 	return app.Listen(fmt.Sprintf("%s:%d", env.AppHost, env.AppPort))
@@ -143,6 +168,232 @@ func handleArgs(env *config.Env) {
 
 			seed.Seed(env, *seedDomain)
 			os.Exit(1)
+		case "story":
+			if err := handleStoryArgs(env, os.Args[2:]); err != nil {
+				slog.Error("story command failed", "error", err)
+				os.Exit(1)
+			}
+			os.Exit(0)
+		case "user":
+			if err := handleUserArgs(env, os.Args[2:]); err != nil {
+				slog.Error("user command failed", "error", err)
+				os.Exit(1)
+			}
+			os.Exit(0)
 		}
 	}
 }
+
+func handleStoryArgs(env *config.Env, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: story <import|export|validate|publish> ...")
+	}
+
+	importCmd := flag.NewFlagSet("story import", flag.ExitOnError)
+	exportCmd := flag.NewFlagSet("story export", flag.ExitOnError)
+	validateCmd := flag.NewFlagSet("story validate", flag.ExitOnError)
+	publishCmd := flag.NewFlagSet("story publish", flag.ExitOnError)
+
+	importPublish := importCmd.Bool("publish", false, "publish the chapter to players after importing it")
+
+	exportOut := exportCmd.String("out", "", "write the bundle to this file instead of stdout")
+	exportFormat := exportCmd.String("format", "", "bundle format, 'yaml' or 'json' (defaults to the -out extension, or yaml)")
+
+	// arguments are checked before connecting, a typo shouldn't need a database
+	switch args[0] {
+	case "import":
+		if err := importCmd.Parse(args[1:]); err != nil {
+			return err
+		}
+		if importCmd.NArg() != 1 {
+			return fmt.Errorf("usage: story import [-publish] <file>")
+		}
+
+		b, err := bundle.Load(importCmd.Arg(0))
+		if err != nil {
+			return err
+		}
+
+		db, err := postgresql.New(env)
+		if err != nil {
+			return err
+		}
+
+		res, err := bundle.Import(db, b)
+		if err != nil {
+			return err
+		}
+
+		slog.Info("chapter imported",
+			"chapter_id", res.ChapterID,
+			"created", res.SlidesCreated,
+			"updated", res.SlidesUpdated,
+			"deleted", res.SlidesDeleted,
+			"endings", res.EndingsSaved)
+
+		if !*importPublish {
+			return nil
+		}
+		return publishChapter(db, res.ChapterID)
+	case "export":
+		if err := exportCmd.Parse(args[1:]); err != nil {
+			return err
+		}
+		if exportCmd.NArg() != 1 {
+			return fmt.Errorf("usage: story export [-out file] [-format yaml|json] <chapter-id>")
+		}
+
+		chapterID, err := uuid.Parse(exportCmd.Arg(0))
+		if err != nil {
+			return fmt.Errorf("invalid chapter id: %w", err)
+		}
+
+		db, err := postgresql.New(env)
+		if err != nil {
+			return err
+		}
+
+		b, err := bundle.Export(db, chapterID)
+		if err != nil {
+			return err
+		}
+
+		format := *exportFormat
+		if format == "" {
+			format = bundle.FormatFromPath(*exportOut)
+		}
+
+		if *exportOut == "" {
+			return bundle.Encode(os.Stdout, b, format)
+		}
+
+		f, err := os.Create(*exportOut)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		if err := bundle.Encode(f, b, format); err != nil {
+			return err
+		}
+
+		slog.Info("chapter exported", "chapter_id", chapterID, "file", *exportOut)
+		return nil
+	case "validate":
+		if err := validateCmd.Parse(args[1:]); err != nil {
+			return err
+		}
+		if validateCmd.NArg() != 1 {
+			return fmt.Errorf("usage: story validate <file|chapter-id>")
+		}
+
+		// a chapter id validates the stored chapter, anything else is read as a bundle file
+		var issues []graph.Issue
+		var err error
+		if chapterID, parseErr := uuid.Parse(validateCmd.Arg(0)); parseErr == nil {
+			db, dbErr := postgresql.New(env)
+			if dbErr != nil {
+				return dbErr
+			}
+			issues, err = bundle.ValidateChapter(db, chapterID)
+		} else {
+			b, loadErr := bundle.Load(validateCmd.Arg(0))
+			if loadErr != nil {
+				return loadErr
+			}
+			issues, err = b.Validate()
+		}
+
+		for _, i := range issues {
+			fmt.Println(i)
+		}
+		if err != nil {
+			return err
+		}
+
+		slog.Info("story graph is valid", "warnings", len(issues))
+		return nil
+	case "publish":
+		if err := publishCmd.Parse(args[1:]); err != nil {
+			return err
+		}
+		if publishCmd.NArg() != 1 {
+			return fmt.Errorf("usage: story publish <chapter-id>")
+		}
+
+		chapterID, err := uuid.Parse(publishCmd.Arg(0))
+		if err != nil {
+			return fmt.Errorf("invalid chapter id: %w", err)
+		}
+
+		db, err := postgresql.New(env)
+		if err != nil {
+			return err
+		}
+		return publishChapter(db, chapterID)
+	default:
+		return fmt.Errorf("unknown story command %q", args[0])
+	}
+}
+
+func publishChapter(db *gorm.DB, chapterID uuid.UUID) error {
+	version, err := bundle.Publish(db, chapterID)
+	if errors.Is(err, bundle.ErrNothingToPublish) {
+		slog.Info("chapter already published as it is", "chapter_id", chapterID)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	slog.Info("chapter published", "chapter_id", chapterID, "version", version.Number)
+	return nil
+}
+
+func handleUserArgs(env *config.Env, args []string) error {
+	if len(args) == 0 || args[0] != "promote" {
+		return fmt.Errorf("usage: user promote -role <player|teacher|editor|admin> <email|username>")
+	}
+
+	promoteCmd := flag.NewFlagSet("user promote", flag.ExitOnError)
+	role := promoteCmd.String("role", "", "role to give the user: player, teacher, editor or admin")
+
+	if err := promoteCmd.Parse(args[1:]); err != nil {
+		return err
+	}
+	if promoteCmd.NArg() != 1 || *role == "" {
+		return fmt.Errorf("usage: user promote -role <player|teacher|editor|admin> <email|username>")
+	}
+	if !entity.Role(*role).IsValid() {
+		return fmt.Errorf("unknown role %q", *role)
+	}
+
+	db, err := postgresql.New(env)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	repo := userRepo.NewUserRepository(db)
+
+	user, err := repo.GetUserByEmail(ctx, promoteCmd.Arg(0))
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		if user, err = repo.GetUserByUsername(ctx, promoteCmd.Arg(0)); err != nil {
+			return err
+		}
+	}
+	if user == nil {
+		return fmt.Errorf("user %q not found", promoteCmd.Arg(0))
+	}
+
+	if err := repo.UpdateUserRole(ctx, user.ID, entity.Role(*role)); err != nil {
+		return err
+	}
+
+	// the role is read from the access token, it applies from the next login or refresh
+	slog.Info("user role updated", "user_id", user.ID, "username", user.Username, "from", user.Role, "to", *role)
+	return nil
+}
@@ -0,0 +1,468 @@
+package bundle
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Ablebil/lathi-be/internal/app/story/grading"
+	"github.com/Ablebil/lathi-be/internal/app/story/graph"
+	"github.com/Ablebil/lathi-be/internal/domain/entity"
+	"gopkg.in/yaml.v3"
+)
+
+// current chapter bundle format version
+const Version = 1
+
+const (
+	FormatYAML = "yaml"
+	FormatJSON = "json"
+)
+
+type Bundle struct {
+	Version      int          `json:"version" yaml:"version"`
+	Chapter      Chapter      `json:"chapter" yaml:"chapter"`
+	Vocabularies []Vocabulary `json:"vocabularies,omitempty" yaml:"vocabularies,omitempty"`
+	Endings      []Ending     `json:"endings,omitempty" yaml:"endings,omitempty"`
+	Slides       []Slide      `json:"slides" yaml:"slides"`
+}
+
+type Chapter struct {
+	ID string `json:"id,omitempty" yaml:"id,omitempty"`
+	// slug of the story the chapter belongs to, defaults to the main story.
+	// The story has to exist and a chapter can't move to another story.
+	Story         string `json:"story,omitempty" yaml:"story,omitempty"`
+	Title         string `json:"title" yaml:"title"`
+	Description   string `json:"description" yaml:"description"`
+	CoverImageURL string `json:"cover_image_url" yaml:"cover_image_url"`
+	OrderIndex    int    `json:"order_index" yaml:"order_index"` // position within the story
+
+	// key of the first slide, defaults to the first slide in the bundle
+	Start       string            `json:"start,omitempty" yaml:"start,omitempty"`
+	EntryPoints map[string]string `json:"entry_points,omitempty" yaml:"entry_points,omitempty"`
+
+	// who the player plays, defaults to Andi without sprites
+	Protagonist *Protagonist `json:"protagonist,omitempty" yaml:"protagonist,omitempty"`
+
+	// players can start the chapter from then on, unset releases it with the
+	// first publish
+	PublishAt *time.Time `json:"publish_at,omitempty" yaml:"publish_at,omitempty"`
+	// what a player needs before starting, defaults to completing the chapter
+	// before it. Chapters and endings of other chapters are referenced by id.
+	Unlock *entity.UnlockRules `json:"unlock,omitempty" yaml:"unlock,omitempty"`
+}
+
+// Protagonist sprites are shown by slides that put a {player} character on
+// screen with the sprite key as its image_url.
+type Protagonist struct {
+	Name    string            `json:"name" yaml:"name"`
+	Sprites map[string]string `json:"sprites,omitempty" yaml:"sprites,omitempty"`
+}
+
+// dictionary entry referenced by slides, keyed by its krama word
+type Vocabulary struct {
+	WordKrama string `json:"word_krama" yaml:"word_krama"`
+	WordNgoko string `json:"word_ngoko" yaml:"word_ngoko"`
+	WordIndo  string `json:"word_indo" yaml:"word_indo"`
+}
+
+// named ending attached to terminal slides, keyed within the chapter
+type Ending struct {
+	Key        string `json:"key" yaml:"key"`
+	Title      string `json:"title" yaml:"title"`
+	Kind       string `json:"kind" yaml:"kind"` // good, neutral or bad
+	BadgeCode  string `json:"badge_code,omitempty" yaml:"badge_code,omitempty"`
+	ScoreBonus int    `json:"score_bonus,omitempty" yaml:"score_bonus,omitempty"`
+}
+
+type Slide struct {
+	Key                string           `json:"key" yaml:"key"`
+	ID                 string           `json:"id,omitempty" yaml:"id,omitempty"`
+	Speaker            string           `json:"speaker" yaml:"speaker"`
+	BackgroundImageURL string           `json:"background_image_url" yaml:"background_image_url"`
+	Characters         []Character      `json:"characters,omitempty" yaml:"characters,omitempty"`
+	Content            string           `json:"content" yaml:"content"`
+	Subtitles          entity.Subtitles `json:"subtitles,omitempty" yaml:"subtitles,omitempty"` // content translated to id or en
+	Next               string           `json:"next,omitempty" yaml:"next,omitempty"`
+	Routes             []Route          `json:"routes,omitempty" yaml:"routes,omitempty"`
+	Choices            []Choice         `json:"choices,omitempty" yaml:"choices,omitempty"`
+	Vocab              []string         `json:"vocab,omitempty" yaml:"vocab,omitempty"`
+	Ending             string           `json:"ending,omitempty" yaml:"ending,omitempty"`           // ending key
+	Checkpoint         bool             `json:"checkpoint,omitempty" yaml:"checkpoint,omitempty"`   // a game over can resume from here
+	Quiz               *Quiz            `json:"quiz,omitempty" yaml:"quiz,omitempty"`               // turns the slide into a quiz slide
+	Translation        *Translation     `json:"translation,omitempty" yaml:"translation,omitempty"` // turns the slide into a translation slide
+}
+
+// quiz asking for a dictionary word in another form, words are krama keys
+type Quiz struct {
+	Word         string   `json:"word" yaml:"word"`
+	Ask          string   `json:"ask" yaml:"ask"`
+	Answer       string   `json:"answer" yaml:"answer"`
+	Distractors  []string `json:"distractors" yaml:"distractors"`
+	HeartPenalty *int     `json:"heart_penalty,omitempty" yaml:"heart_penalty,omitempty"` // defaults to 1
+}
+
+// Words returns the dictionary words the quiz uses.
+func (q *Quiz) Words() []string {
+	return append([]string{q.Word}, q.Distractors...)
+}
+
+func (q *Quiz) penalty() int {
+	if q.HeartPenalty == nil {
+		return 1
+	}
+	return *q.HeartPenalty
+}
+
+// free text exercise, the typed answer is graded against every accepted answer
+type Translation struct {
+	Prompt       string   `json:"prompt" yaml:"prompt"`
+	Accepted     []string `json:"accepted" yaml:"accepted"`
+	HeartPenalty *int     `json:"heart_penalty,omitempty" yaml:"heart_penalty,omitempty"` // defaults to 1
+}
+
+func (t *Translation) penalty() int {
+	if t.HeartPenalty == nil {
+		return 1
+	}
+	return *t.HeartPenalty
+}
+
+type Character struct {
+	Name     string `json:"name" yaml:"name"`
+	ImageURL string `json:"image_url" yaml:"image_url"`
+}
+
+type Choice struct {
+	Text       string           `json:"text" yaml:"text"`
+	Subtitles  entity.Subtitles `json:"subtitles,omitempty" yaml:"subtitles,omitempty"`
+	Next       string           `json:"next" yaml:"next"`
+	MoodImpact int              `json:"mood_impact,omitempty" yaml:"mood_impact,omitempty"`
+	Effects    []Effect         `json:"effects,omitempty" yaml:"effects,omitempty"`
+
+	ShowIf   *entity.Condition `json:"show_if,omitempty" yaml:"show_if,omitempty"`
+	EnableIf *entity.Condition `json:"enable_if,omitempty" yaml:"enable_if,omitempty"`
+}
+
+// conditional next slide, the first route whose condition holds wins over next
+type Route struct {
+	If   entity.Condition `json:"if" yaml:"if"`
+	Next string           `json:"next" yaml:"next"`
+}
+
+// variable change applied when the choice is picked, see entity.Effect
+type Effect struct {
+	Var   string `json:"var" yaml:"var"`
+	Op    string `json:"op" yaml:"op"`
+	Value any    `json:"value" yaml:"value"`
+	Scope string `json:"scope,omitempty" yaml:"scope,omitempty"`
+}
+
+func (e Effect) entity() entity.Effect {
+	return entity.Effect{Var: e.Var, Op: e.Op, Value: e.Value, Scope: e.Scope}
+}
+
+// FormatFromPath picks the encoding from the file extension, defaulting to yaml.
+func FormatFromPath(path string) string {
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		return FormatJSON
+	}
+	return FormatYAML
+}
+
+func Load(path string) (*Bundle, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return Decode(f, FormatFromPath(path))
+}
+
+func Decode(r io.Reader, format string) (*Bundle, error) {
+	var b Bundle
+	switch format {
+	case FormatJSON:
+		dec := json.NewDecoder(r)
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&b); err != nil {
+			return nil, fmt.Errorf("invalid json bundle: %w", err)
+		}
+	case FormatYAML:
+		dec := yaml.NewDecoder(r)
+		dec.KnownFields(true)
+		if err := dec.Decode(&b); err != nil {
+			return nil, fmt.Errorf("invalid yaml bundle: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported bundle format %q", format)
+	}
+
+	if b.Version != Version {
+		return nil, fmt.Errorf("unsupported bundle version %d (expected %d)", b.Version, Version)
+	}
+
+	return &b, nil
+}
+
+func Encode(w io.Writer, b *Bundle, format string) error {
+	switch format {
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		enc.SetEscapeHTML(false)
+		return enc.Encode(b)
+	case FormatYAML:
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(b); err != nil {
+			return err
+		}
+		return enc.Close()
+	default:
+		return fmt.Errorf("unsupported bundle format %q", format)
+	}
+}
+
+// Check verifies the bundle metadata and slide keys. References between slides
+// are checked by the story graph validator, see Graph.
+func (b *Bundle) Check() error {
+	if b.Chapter.Title == "" {
+		return fmt.Errorf("chapter title is required")
+	}
+	if b.Chapter.OrderIndex < 1 {
+		return fmt.Errorf("chapter order_index must be >= 1")
+	}
+	if len(b.Slides) == 0 {
+		return fmt.Errorf("bundle has no slides")
+	}
+
+	endings := make(map[string]bool, len(b.Endings))
+	for _, e := range b.Endings {
+		if e.Key == "" {
+			return fmt.Errorf("ending without key")
+		}
+		if len(e.Key) > 50 {
+			return fmt.Errorf("ending key %q is longer than 50 characters", e.Key)
+		}
+		if endings[e.Key] {
+			return fmt.Errorf("duplicate ending key %q", e.Key)
+		}
+		endings[e.Key] = true
+
+		if strings.TrimSpace(e.Title) == "" {
+			return fmt.Errorf("ending %q has no title", e.Key)
+		}
+		if !entity.EndingKind(e.Kind).IsValid() {
+			return fmt.Errorf("ending %q has unknown kind %q", e.Key, e.Kind)
+		}
+	}
+
+	if p := b.Chapter.Protagonist; p != nil {
+		if strings.TrimSpace(p.Name) == "" {
+			return fmt.Errorf("protagonist name is required")
+		}
+		if len([]rune(p.Name)) > 100 {
+			return fmt.Errorf("protagonist name is longer than 100 characters")
+		}
+		for key, img := range p.Sprites {
+			if strings.TrimSpace(key) == "" || strings.TrimSpace(img) == "" {
+				return fmt.Errorf("protagonist sprite without key or image")
+			}
+		}
+	}
+
+	if u := b.Chapter.Unlock; u != nil {
+		if u.MinVocab < 0 {
+			return fmt.Errorf("unlock min_vocab must be >= 0")
+		}
+		for _, id := range u.ChaptersCompleted {
+			if id.String() == b.Chapter.ID {
+				return fmt.Errorf("chapter can't require itself to unlock")
+			}
+		}
+	}
+
+	keys := make(map[string]bool, len(b.Slides))
+	for _, s := range b.Slides {
+		if s.Key == "" {
+			return fmt.Errorf("slide without key")
+		}
+		if len(s.Key) > 50 {
+			return fmt.Errorf("slide key %q is longer than 50 characters", s.Key)
+		}
+		if keys[s.Key] {
+			return fmt.Errorf("duplicate slide key %q", s.Key)
+		}
+		keys[s.Key] = true
+
+		if p := b.Chapter.Protagonist; p != nil && len(p.Sprites) > 0 {
+			for _, c := range s.Characters {
+				if _, ok := p.Sprites[c.ImageURL]; c.Name == entity.PlayerPlaceholder && !ok {
+					return fmt.Errorf("slide %q uses unknown protagonist sprite %q", s.Key, c.ImageURL)
+				}
+			}
+		}
+
+		if err := checkSubtitles(s.Subtitles); err != nil {
+			return fmt.Errorf("slide %q subtitles: %w", s.Key, err)
+		}
+
+		for i, c := range s.Choices {
+			if err := checkSubtitles(c.Subtitles); err != nil {
+				return fmt.Errorf("slide %q choice %d subtitles: %w", s.Key, i, err)
+			}
+			for _, e := range c.Effects {
+				if err := e.entity().Validate(); err != nil {
+					return fmt.Errorf("slide %q choice %d: %w", s.Key, i, err)
+				}
+			}
+			if err := c.ShowIf.Validate(); err != nil {
+				return fmt.Errorf("slide %q choice %d show_if: %w", s.Key, i, err)
+			}
+			if err := c.EnableIf.Validate(); err != nil {
+				return fmt.Errorf("slide %q choice %d enable_if: %w", s.Key, i, err)
+			}
+		}
+
+		for i, r := range s.Routes {
+			if err := r.If.Validate(); err != nil {
+				return fmt.Errorf("slide %q route %d: %w", s.Key, i, err)
+			}
+		}
+
+		if s.Quiz != nil {
+			if err := checkQuiz(s.Quiz); err != nil {
+				return fmt.Errorf("slide %q quiz: %w", s.Key, err)
+			}
+			if len(s.Choices) > 0 {
+				return fmt.Errorf("slide %q is a quiz and can't have choices", s.Key)
+			}
+		}
+
+		if s.Translation != nil {
+			if err := checkTranslation(s.Translation); err != nil {
+				return fmt.Errorf("slide %q translation: %w", s.Key, err)
+			}
+			if len(s.Choices) > 0 || s.Quiz != nil {
+				return fmt.Errorf("slide %q is a translation and can't have choices or a quiz", s.Key)
+			}
+		}
+	}
+
+	for name := range b.Chapter.EntryPoints {
+		if strings.TrimSpace(name) == "" {
+			return fmt.Errorf("entry point without name")
+		}
+	}
+
+	return nil
+}
+
+func checkSubtitles(subs entity.Subtitles) error {
+	for lang, text := range subs {
+		if !entity.IsSubtitleLanguage(lang) {
+			return fmt.Errorf("unknown language %q", lang)
+		}
+		if strings.TrimSpace(text) == "" {
+			return fmt.Errorf("%s subtitle is empty", lang)
+		}
+	}
+	return nil
+}
+
+func checkQuiz(q *Quiz) error {
+	if strings.TrimSpace(q.Word) == "" {
+		return fmt.Errorf("word is required")
+	}
+	if len(q.Distractors) == 0 {
+		return fmt.Errorf("at least one distractor is required")
+	}
+	for _, d := range q.Distractors {
+		if strings.EqualFold(d, q.Word) {
+			return fmt.Errorf("word %q is also a distractor", d)
+		}
+	}
+
+	quiz := entity.Quiz{
+		Ask:          entity.WordForm(q.Ask),
+		Answer:       entity.WordForm(q.Answer),
+		HeartPenalty: q.penalty(),
+	}
+	return quiz.Validate()
+}
+
+func checkTranslation(t *Translation) error {
+	if strings.TrimSpace(t.Prompt) == "" {
+		return fmt.Errorf("prompt is required")
+	}
+	if len(t.Accepted) == 0 {
+		return fmt.Errorf("at least one accepted answer is required")
+	}
+	for i, a := range t.Accepted {
+		if grading.Normalize(a) == "" {
+			return fmt.Errorf("accepted answer %d is empty", i)
+		}
+	}
+	if t.penalty() < 0 {
+		return fmt.Errorf("heart_penalty must be >= 0")
+	}
+	return nil
+}
+
+// Graph converts the bundle into a story graph keyed by slide key.
+func (b *Bundle) Graph() *graph.Graph {
+	g := &graph.Graph{
+		Start:   b.StartKey(),
+		Entries: b.Chapter.EntryPoints,
+	}
+	for _, e := range b.Endings {
+		g.Endings = append(g.Endings, e.Key)
+	}
+	for _, s := range b.Slides {
+		n := graph.Node{
+			ID:      s.Key,
+			Content: s.Content,
+			Next:    s.Next,
+			Vocab:   s.Vocab,
+			Ending:  s.Ending,
+		}
+		for _, r := range s.Routes {
+			n.Routes = append(n.Routes, r.Next)
+		}
+		for _, c := range s.Choices {
+			n.Choices = append(n.Choices, graph.Edge{
+				Text:        c.Text,
+				Next:        c.Next,
+				Conditional: c.ShowIf != nil || c.EnableIf != nil,
+			})
+		}
+		g.Nodes = append(g.Nodes, n)
+	}
+
+	return g
+}
+
+// StartKey returns the key of the slide new sessions start from.
+func (b *Bundle) StartKey() string {
+	if b.Chapter.Start != "" {
+		return b.Chapter.Start
+	}
+	if len(b.Slides) > 0 {
+		return b.Slides[0].Key
+	}
+	return ""
+}
+
+// Validate runs the story graph validator over the bundle.
+func (b *Bundle) Validate() ([]graph.Issue, error) {
+	if err := b.Check(); err != nil {
+		return nil, err
+	}
+	return graph.Check(b.Graph())
+}
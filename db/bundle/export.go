@@ -0,0 +1,300 @@
+package bundle
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Ablebil/lathi-be/internal/app/story/graph"
+	"github.com/Ablebil/lathi-be/internal/domain/entity"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Export builds a bundle from a stored chapter. Slides that were created before
+// keys existed are exported with their id as key so the bundle can be imported
+// back without recreating them.
+func Export(db *gorm.DB, chapterID uuid.UUID) (*Bundle, error) {
+	chapter, err := loadChapter(db, chapterID)
+	if err != nil {
+		return nil, err
+	}
+
+	var story entity.Story
+	if err := db.Where("id = ?", chapter.StoryID).First(&story).Error; err != nil {
+		return nil, fmt.Errorf("chapter story: %w", err)
+	}
+
+	b := &Bundle{
+		Version: Version,
+		Chapter: Chapter{
+			ID:            chapter.ID.String(),
+			Story:         story.Slug,
+			Title:         chapter.Title,
+			Description:   chapter.Description,
+			CoverImageURL: chapter.CoverImageURL,
+			OrderIndex:    chapter.OrderIndex,
+			PublishAt:     chapter.PublishAt,
+		},
+	}
+
+	unlock, err := chapter.GetUnlockRules()
+	if err != nil {
+		return nil, fmt.Errorf("chapter unlock rules: %w", err)
+	}
+	if !unlock.IsEmpty() {
+		b.Chapter.Unlock = unlock
+	}
+
+	protagonist, err := chapter.Protagonist()
+	if err != nil {
+		return nil, fmt.Errorf("chapter protagonist: %w", err)
+	}
+	if protagonist.Name != entity.DefaultProtagonist || len(protagonist.Sprites) > 0 {
+		b.Chapter.Protagonist = &Protagonist{Name: protagonist.Name, Sprites: protagonist.Sprites}
+	}
+
+	endingKeys := make(map[uuid.UUID]string, len(chapter.Endings))
+	for _, e := range chapter.Endings {
+		endingKeys[e.ID] = e.Key
+		b.Endings = append(b.Endings, Ending{
+			Key:        e.Key,
+			Title:      e.Title,
+			Kind:       string(e.Kind),
+			BadgeCode:  e.BadgeCode,
+			ScoreBonus: e.ScoreBonus,
+		})
+	}
+
+	keys := make(map[uuid.UUID]string, len(chapter.Slides))
+	for _, s := range chapter.Slides {
+		keys[s.ID] = slideKey(s)
+	}
+
+	if chapter.StartSlideID != nil {
+		start, ok := keys[*chapter.StartSlideID]
+		if !ok {
+			return nil, fmt.Errorf("chapter start slide %s is not part of the chapter", chapter.StartSlideID)
+		}
+		b.Chapter.Start = start
+	}
+
+	entries, err := chapter.GetEntryPoints()
+	if err != nil {
+		return nil, fmt.Errorf("chapter has invalid entry points: %w", err)
+	}
+	for name, id := range entries {
+		key, ok := keys[id]
+		if !ok {
+			return nil, fmt.Errorf("entry point %q points to slide %s outside the chapter", name, id)
+		}
+		if b.Chapter.EntryPoints == nil {
+			b.Chapter.EntryPoints = make(map[string]string, len(entries))
+		}
+		b.Chapter.EntryPoints[name] = key
+	}
+
+	quizWords, err := loadQuizWords(db, chapter.Slides)
+	if err != nil {
+		return nil, err
+	}
+
+	seenVocab := make(map[uuid.UUID]bool)
+	addVocab := func(v entity.Dictionary) {
+		if !seenVocab[v.ID] {
+			seenVocab[v.ID] = true
+			b.Vocabularies = append(b.Vocabularies, Vocabulary{
+				WordKrama: v.WordKrama,
+				WordNgoko: v.WordNgoko,
+				WordIndo:  v.WordIndo,
+			})
+		}
+	}
+
+	for _, s := range chapter.Slides {
+		chars, err := s.GetCharacters()
+		if err != nil {
+			return nil, fmt.Errorf("slide %s has invalid characters: %w", s.ID, err)
+		}
+
+		choices, err := s.GetChoices()
+		if err != nil {
+			return nil, fmt.Errorf("slide %s has invalid choices: %w", s.ID, err)
+		}
+
+		subs, err := s.GetSubtitles()
+		if err != nil {
+			return nil, fmt.Errorf("slide %s has invalid subtitles: %w", s.ID, err)
+		}
+
+		slide := Slide{
+			Key:                keys[s.ID],
+			ID:                 s.ID.String(),
+			Speaker:            s.SpeakerName,
+			BackgroundImageURL: s.BackgroundImageURL,
+			Content:            s.Content,
+			Checkpoint:         s.IsCheckpoint,
+		}
+
+		if len(subs) > 0 {
+			slide.Subtitles = subs
+		}
+
+		for _, c := range chars {
+			slide.Characters = append(slide.Characters, Character{Name: c.Name, ImageURL: c.ImageURL})
+		}
+
+		if s.NextSlideID != nil {
+			next, ok := keys[*s.NextSlideID]
+			if !ok {
+				return nil, fmt.Errorf("slide %s points to slide %s outside the chapter", s.ID, s.NextSlideID)
+			}
+			slide.Next = next
+		}
+
+		if s.EndingID != nil {
+			ending, ok := endingKeys[*s.EndingID]
+			if !ok {
+				return nil, fmt.Errorf("slide %s has ending %s outside the chapter", s.ID, s.EndingID)
+			}
+			slide.Ending = ending
+		}
+
+		routes, err := s.GetRoutes()
+		if err != nil {
+			return nil, fmt.Errorf("slide %s has invalid routes: %w", s.ID, err)
+		}
+		for i, r := range routes {
+			next, ok := keys[r.NextSlideID]
+			if !ok {
+				return nil, fmt.Errorf("slide %s route %d points to slide %s outside the chapter", s.ID, i, r.NextSlideID)
+			}
+			slide.Routes = append(slide.Routes, Route{If: r.If, Next: next})
+		}
+
+		for i, c := range choices {
+			next, ok := keys[c.NextSlideID]
+			if !ok {
+				return nil, fmt.Errorf("slide %s choice %d points to slide %s outside the chapter", s.ID, i, c.NextSlideID)
+			}
+			choice := Choice{Text: c.Text, Subtitles: c.Subtitles, Next: next, MoodImpact: c.MoodImpact, ShowIf: c.ShowIf, EnableIf: c.EnableIf}
+			for _, e := range c.Effects {
+				choice.Effects = append(choice.Effects, Effect{Var: e.Var, Op: e.Op, Value: e.Value, Scope: e.Scope})
+			}
+			slide.Choices = append(slide.Choices, choice)
+		}
+
+		for _, v := range s.Vocabularies {
+			slide.Vocab = append(slide.Vocab, v.WordKrama)
+			addVocab(v)
+		}
+
+		quiz, err := s.GetQuiz()
+		if err != nil {
+			return nil, fmt.Errorf("slide %s has invalid quiz: %w", s.ID, err)
+		}
+		if quiz != nil {
+			penalty := quiz.HeartPenalty
+			slide.Quiz = &Quiz{Ask: string(quiz.Ask), Answer: string(quiz.Answer), HeartPenalty: &penalty}
+			for i, id := range append([]uuid.UUID{quiz.WordID}, quiz.DistractorIDs...) {
+				word, ok := quizWords[id]
+				if !ok {
+					return nil, fmt.Errorf("slide %s quiz uses unknown word %s", s.ID, id)
+				}
+				addVocab(word)
+				if i == 0 {
+					slide.Quiz.Word = word.WordKrama
+				} else {
+					slide.Quiz.Distractors = append(slide.Quiz.Distractors, word.WordKrama)
+				}
+			}
+		}
+
+		translation, err := s.GetTranslation()
+		if err != nil {
+			return nil, fmt.Errorf("slide %s has invalid translation: %w", s.ID, err)
+		}
+		if translation != nil {
+			penalty := translation.HeartPenalty
+			slide.Translation = &Translation{Prompt: translation.Prompt, Accepted: translation.Accepted, HeartPenalty: &penalty}
+		}
+
+		b.Slides = append(b.Slides, slide)
+	}
+
+	return b, nil
+}
+
+// ValidateChapter runs the story graph validator over a stored chapter.
+func ValidateChapter(db *gorm.DB, chapterID uuid.UUID) ([]graph.Issue, error) {
+	chapter, err := loadChapter(db, chapterID)
+	if err != nil {
+		return nil, err
+	}
+
+	g, err := graph.FromChapter(chapter)
+	if err != nil {
+		return nil, err
+	}
+
+	return graph.Check(g)
+}
+
+func loadChapter(db *gorm.DB, chapterID uuid.UUID) (*entity.Chapter, error) {
+	var chapter entity.Chapter
+	err := db.
+		Preload("Slides", func(db *gorm.DB) *gorm.DB {
+			return db.Order("id ASC")
+		}).
+		Preload("Slides.Vocabularies", func(db *gorm.DB) *gorm.DB {
+			return db.Order("word_krama ASC")
+		}).
+		Preload("Endings", func(db *gorm.DB) *gorm.DB {
+			return db.Order("key ASC")
+		}).
+		Where("id = ?", chapterID).
+		First(&chapter).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("chapter %s not found", chapterID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &chapter, nil
+}
+
+func loadQuizWords(db *gorm.DB, slides []entity.Slide) (map[uuid.UUID]entity.Dictionary, error) {
+	var ids []uuid.UUID
+	for _, s := range slides {
+		quiz, err := s.GetQuiz()
+		if err != nil {
+			return nil, fmt.Errorf("slide %s has invalid quiz: %w", s.ID, err)
+		}
+		if quiz != nil {
+			ids = append(ids, quiz.WordID)
+			ids = append(ids, quiz.DistractorIDs...)
+		}
+	}
+
+	words := make(map[uuid.UUID]entity.Dictionary, len(ids))
+	if len(ids) == 0 {
+		return words, nil
+	}
+
+	var dicts []entity.Dictionary
+	if err := db.Where("id IN ?", ids).Find(&dicts).Error; err != nil {
+		return nil, err
+	}
+	for _, d := range dicts {
+		words[d.ID] = d
+	}
+	return words, nil
+}
+
+func slideKey(s entity.Slide) string {
+	if s.Key != "" {
+		return s.Key
+	}
+	return s.ID.String()
+}
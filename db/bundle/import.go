@@ -0,0 +1,536 @@
+package bundle
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/Ablebil/lathi-be/internal/app/story/graph"
+	"github.com/Ablebil/lathi-be/internal/domain/entity"
+	"github.com/Ablebil/lathi-be/internal/domain/types"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type ImportResult struct {
+	ChapterID     uuid.UUID
+	SlidesCreated int
+	SlidesUpdated int
+	SlidesDeleted int
+	EndingsSaved  int
+}
+
+// Import upserts the bundle into the database. Chapters are matched by id (or
+// order_index when no id is given) and slides by id or key, so importing the
+// same bundle twice leaves the database unchanged. The import updates the
+// chapter draft, players get it once it is published.
+func Import(db *gorm.DB, b *Bundle) (*ImportResult, error) {
+	issues, err := b.Validate()
+	for _, i := range issues {
+		if i.Severity == graph.SeverityWarning {
+			slog.Warn("story graph warning", "chapter", b.Chapter.Title, "slide", i.Slide, "code", i.Code, "message", i.Message)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	res := &ImportResult{}
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := upsertVocabularies(tx, b.Vocabularies); err != nil {
+			return err
+		}
+
+		vocabIDs, err := resolveVocabularies(tx, b.Slides)
+		if err != nil {
+			return err
+		}
+
+		chapter, err := upsertChapter(tx, b.Chapter)
+		if err != nil {
+			return err
+		}
+		res.ChapterID = chapter.ID
+
+		endingIDs, err := upsertEndings(tx, chapter.ID, b.Endings)
+		if err != nil {
+			return err
+		}
+		res.EndingsSaved = len(endingIDs)
+
+		var existing []entity.Slide
+		if err := tx.Where("chapter_id = ?", chapter.ID).Find(&existing).Error; err != nil {
+			return err
+		}
+
+		byID := make(map[uuid.UUID]*entity.Slide, len(existing))
+		byKey := make(map[string]*entity.Slide, len(existing))
+		for i := range existing {
+			byID[existing[i].ID] = &existing[i]
+			if existing[i].Key != "" {
+				byKey[existing[i].Key] = &existing[i]
+			}
+		}
+
+		// first pass: create or update slide bodies so every key has a real id
+		realIDs := make(map[string]uuid.UUID, len(b.Slides))
+		for _, d := range b.Slides {
+			slide, err := matchSlide(d, byID, byKey)
+			if err != nil {
+				return err
+			}
+
+			isNew := slide == nil
+			if isNew {
+				slide = &entity.Slide{ChapterID: chapter.ID}
+				if d.ID != "" {
+					slide.ID, _ = uuid.Parse(d.ID)
+				}
+			}
+
+			slide.Key = d.Key
+			slide.IsCheckpoint = d.Checkpoint
+			slide.Type = entity.SlideStory
+			slide.Quiz = types.JSONB("{}")
+			slide.Translation = types.JSONB("{}")
+			if d.Quiz != nil {
+				slide.Type = entity.SlideQuiz
+				slide.Quiz = makeQuiz(d.Quiz, vocabIDs)
+			}
+			if d.Translation != nil {
+				slide.Type = entity.SlideTranslate
+				slide.Translation = makeTranslation(d.Translation)
+			}
+			slide.SpeakerName = d.Speaker
+			slide.Content = d.Content
+			slide.Subtitles = makeSubtitles(d.Subtitles)
+			slide.BackgroundImageURL = d.BackgroundImageURL
+			slide.Characters = makeCharacters(d)
+
+			if isNew {
+				if err := tx.Omit("Vocabularies").Create(slide).Error; err != nil {
+					return err
+				}
+				res.SlidesCreated++
+			} else {
+				if err := tx.Model(slide).Select("key", "type", "is_checkpoint", "speaker_name", "content", "subtitles", "background_image_url", "characters", "quiz", "translation").Updates(slide).Error; err != nil {
+					return err
+				}
+				res.SlidesUpdated++
+			}
+
+			realIDs[d.Key] = slide.ID
+
+			vocabs := make([]entity.Dictionary, 0, len(d.Vocab))
+			for _, v := range d.Vocab {
+				vocabs = append(vocabs, entity.Dictionary{ID: vocabIDs[v]})
+			}
+			if err := tx.Model(slide).Association("Vocabularies").Replace(vocabs); err != nil {
+				return err
+			}
+		}
+
+		// second pass: link slides now that all ids are known
+		for _, d := range b.Slides {
+			updates := map[string]interface{}{
+				"next_slide_id": nil,
+				"choices":       types.JSONB("[]"),
+				"routes":        types.JSONB("[]"),
+				"ending_id":     nil,
+			}
+
+			if d.Next != "" {
+				updates["next_slide_id"] = realIDs[d.Next]
+			}
+
+			if d.Ending != "" {
+				updates["ending_id"] = endingIDs[d.Ending]
+			}
+
+			if len(d.Choices) > 0 {
+				updates["choices"] = makeChoices(d.Choices, realIDs)
+			}
+
+			if len(d.Routes) > 0 {
+				updates["routes"] = makeRoutes(d.Routes, realIDs)
+			}
+
+			if err := tx.Model(&entity.Slide{}).Where("id = ?", realIDs[d.Key]).Updates(updates).Error; err != nil {
+				return err
+			}
+		}
+
+		entries := make(map[string]uuid.UUID, len(b.Chapter.EntryPoints))
+		for name, key := range b.Chapter.EntryPoints {
+			entries[name] = realIDs[key]
+		}
+		entriesJSON, _ := json.Marshal(entries)
+
+		startID := realIDs[b.StartKey()]
+		if err := tx.Model(chapter).Updates(map[string]interface{}{
+			"start_slide_id": startID,
+			"entry_points":   types.JSONB(entriesJSON),
+		}).Error; err != nil {
+			return err
+		}
+
+		// drop slides that are no longer part of the bundle
+		keep := make([]uuid.UUID, 0, len(realIDs))
+		for _, id := range realIDs {
+			keep = append(keep, id)
+		}
+
+		result := tx.Where("chapter_id = ? AND id NOT IN ?", chapter.ID, keep).Delete(&entity.Slide{})
+		if result.Error != nil {
+			return result.Error
+		}
+		res.SlidesDeleted = int(result.RowsAffected)
+
+		// endings dropped from the bundle take the players' records with them,
+		// unless a published version still leads to them
+		keepEndings := make([]uuid.UUID, 0, len(endingIDs))
+		for _, id := range endingIDs {
+			keepEndings = append(keepEndings, id)
+		}
+		staleEndings := tx.Where("chapter_id = ?", chapter.ID).
+			Where(`NOT EXISTS (
+				SELECT 1 FROM chapter_versions cv
+				WHERE cv.chapter_id = chapter_endings.chapter_id
+					AND cv.content->'endings' @> jsonb_build_array(jsonb_build_object('id', chapter_endings.id::text))
+			)`)
+		if len(keepEndings) > 0 {
+			staleEndings = staleEndings.Where("id NOT IN ?", keepEndings)
+		}
+		var stale []uuid.UUID
+		if err := staleEndings.Model(&entity.ChapterEnding{}).Pluck("id", &stale).Error; err != nil {
+			return err
+		}
+		if len(stale) > 0 {
+			if err := tx.Where("ending_id IN ?", stale).Delete(&entity.UserEnding{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("id IN ?", stale).Delete(&entity.ChapterEnding{}).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func upsertVocabularies(tx *gorm.DB, vocabs []Vocabulary) error {
+	for _, v := range vocabs {
+		var dict entity.Dictionary
+		err := tx.Where("word_krama = ?", v.WordKrama).First(&dict).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			dict = entity.Dictionary{WordKrama: v.WordKrama, WordNgoko: v.WordNgoko, WordIndo: v.WordIndo}
+			if err := tx.Create(&dict).Error; err != nil {
+				return err
+			}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Model(&dict).Updates(map[string]interface{}{
+			"word_ngoko": v.WordNgoko,
+			"word_indo":  v.WordIndo,
+		}).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func resolveVocabularies(tx *gorm.DB, slides []Slide) (map[string]uuid.UUID, error) {
+	var words []string
+	seen := make(map[string]bool)
+	for _, s := range slides {
+		used := s.Vocab
+		if s.Quiz != nil {
+			used = append(used[:len(used):len(used)], s.Quiz.Words()...)
+		}
+		for _, v := range used {
+			if !seen[v] {
+				seen[v] = true
+				words = append(words, v)
+			}
+		}
+	}
+
+	ids := make(map[string]uuid.UUID, len(words))
+	if len(words) == 0 {
+		return ids, nil
+	}
+
+	var dicts []entity.Dictionary
+	if err := tx.Where("word_krama IN ?", words).Find(&dicts).Error; err != nil {
+		return nil, err
+	}
+	for _, d := range dicts {
+		ids[d.WordKrama] = d.ID
+	}
+
+	for _, w := range words {
+		if _, ok := ids[w]; !ok {
+			return nil, fmt.Errorf("vocabulary %q not found in dictionary", w)
+		}
+	}
+
+	return ids, nil
+}
+
+func upsertChapter(tx *gorm.DB, c Chapter) (*entity.Chapter, error) {
+	slug := c.Story
+	if slug == "" {
+		slug = entity.MainStorySlug
+	}
+	var story entity.Story
+	if err := tx.Where("slug = ?", slug).First(&story).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("story %q not found", slug)
+		}
+		return nil, err
+	}
+
+	var chapter entity.Chapter
+	query := tx.Where("story_id = ? AND order_index = ?", story.ID, c.OrderIndex)
+	if c.ID != "" {
+		id, err := uuid.Parse(c.ID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid chapter id %q", c.ID)
+		}
+		chapter.ID = id
+		query = tx.Where("id = ?", id)
+	}
+
+	err := query.First(&chapter).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	// progress is kept per story, moving a chapter would break it
+	if err == nil && chapter.StoryID != story.ID {
+		return nil, fmt.Errorf("chapter %s belongs to another story", chapter.ID)
+	}
+	chapter.StoryID = story.ID
+
+	chapter.Title = c.Title
+	chapter.Description = c.Description
+	chapter.CoverImageURL = c.CoverImageURL
+	chapter.OrderIndex = c.OrderIndex
+	chapter.ProtagonistName = entity.DefaultProtagonist
+	chapter.ProtagonistSprites = types.JSONB("{}")
+	if c.Protagonist != nil {
+		chapter.ProtagonistName = c.Protagonist.Name
+		if len(c.Protagonist.Sprites) > 0 {
+			sprites, _ := json.Marshal(c.Protagonist.Sprites)
+			chapter.ProtagonistSprites = types.JSONB(sprites)
+		}
+	}
+
+	chapter.PublishAt = nil
+	if c.PublishAt != nil {
+		at := c.PublishAt.UTC()
+		chapter.PublishAt = &at
+	}
+	chapter.Unlock = types.JSONB("{}")
+	if !c.Unlock.IsEmpty() {
+		if err := checkUnlock(tx, chapter.ID, c.Unlock); err != nil {
+			return nil, err
+		}
+		unlock, _ := json.Marshal(c.Unlock)
+		chapter.Unlock = types.JSONB(unlock)
+	}
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		if err := tx.Create(&chapter).Error; err != nil {
+			return nil, err
+		}
+		return &chapter, nil
+	}
+
+	if err := tx.Model(&chapter).Select("title", "description", "cover_image_url", "order_index", "protagonist_name", "protagonist_sprites", "publish_at", "unlock").Updates(&chapter).Error; err != nil {
+		return nil, err
+	}
+
+	return &chapter, nil
+}
+
+// checkUnlock makes sure the unlock rules refer to other chapters and to
+// endings of other chapters that exist. chapterID is nil for a new chapter.
+func checkUnlock(tx *gorm.DB, chapterID uuid.UUID, u *entity.UnlockRules) error {
+	for _, id := range u.ChaptersCompleted {
+		if id == chapterID {
+			return fmt.Errorf("chapter can't require itself to unlock")
+		}
+		var count int64
+		if err := tx.Model(&entity.Chapter{}).Where("id = ?", id).Count(&count).Error; err != nil {
+			return err
+		}
+		if count == 0 {
+			return fmt.Errorf("unlock rules refer to unknown chapter %s", id)
+		}
+	}
+
+	for _, id := range u.EndingsReached {
+		var count int64
+		if err := tx.Model(&entity.ChapterEnding{}).Where("id = ? AND chapter_id <> ?", id, chapterID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count == 0 {
+			return fmt.Errorf("unlock rules refer to unknown ending %s", id)
+		}
+	}
+	return nil
+}
+
+// upsertEndings saves the chapter endings matched by key and returns their ids
+// keyed by ending key. Badge codes must refer to existing badges.
+func upsertEndings(tx *gorm.DB, chapterID uuid.UUID, endings []Ending) (map[string]uuid.UUID, error) {
+	ids := make(map[string]uuid.UUID, len(endings))
+	for _, e := range endings {
+		if e.BadgeCode != "" {
+			var count int64
+			if err := tx.Model(&entity.Badge{}).Where("code = ?", e.BadgeCode).Count(&count).Error; err != nil {
+				return nil, err
+			}
+			if count == 0 {
+				return nil, fmt.Errorf("ending %q refers to unknown badge %q", e.Key, e.BadgeCode)
+			}
+		}
+
+		var ending entity.ChapterEnding
+		err := tx.Where("chapter_id = ? AND key = ?", chapterID, e.Key).First(&ending).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+
+		ending.ChapterID = chapterID
+		ending.Key = e.Key
+		ending.Title = e.Title
+		ending.Kind = entity.EndingKind(e.Kind)
+		ending.BadgeCode = e.BadgeCode
+		ending.ScoreBonus = e.ScoreBonus
+
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			if err := tx.Create(&ending).Error; err != nil {
+				return nil, err
+			}
+		} else if err := tx.Model(&ending).Select("title", "kind", "badge_code", "score_bonus").Updates(&ending).Error; err != nil {
+			return nil, err
+		}
+
+		ids[e.Key] = ending.ID
+	}
+	return ids, nil
+}
+
+func matchSlide(d Slide, byID map[uuid.UUID]*entity.Slide, byKey map[string]*entity.Slide) (*entity.Slide, error) {
+	if d.ID != "" {
+		id, err := uuid.Parse(d.ID)
+		if err != nil {
+			return nil, fmt.Errorf("slide %q has invalid id %q", d.Key, d.ID)
+		}
+		if s, ok := byID[id]; ok {
+			return s, nil
+		}
+	}
+
+	if s, ok := byKey[d.Key]; ok {
+		return s, nil
+	}
+
+	return nil, nil
+}
+
+func makeCharacters(d Slide) types.JSONB {
+	if len(d.Characters) == 0 {
+		return types.JSONB("[]")
+	}
+
+	chars := make([]entity.Character, len(d.Characters))
+	for i, c := range d.Characters {
+		chars[i] = entity.Character{
+			Name:     c.Name,
+			ImageURL: c.ImageURL,
+			IsActive: c.Name == d.Speaker,
+		}
+	}
+
+	b, _ := json.Marshal(chars)
+	return types.JSONB(b)
+}
+
+func makeSubtitles(subs entity.Subtitles) types.JSONB {
+	if len(subs) == 0 {
+		return types.JSONB("{}")
+	}
+
+	b, _ := json.Marshal(subs)
+	return types.JSONB(b)
+}
+
+func makeChoices(opts []Choice, realIDs map[string]uuid.UUID) types.JSONB {
+	res := make([]entity.Choice, len(opts))
+	for i, o := range opts {
+		res[i] = entity.Choice{
+			Text:        o.Text,
+			Subtitles:   o.Subtitles,
+			NextSlideID: realIDs[o.Next],
+			MoodImpact:  o.MoodImpact,
+			ShowIf:      o.ShowIf,
+			EnableIf:    o.EnableIf,
+		}
+		for _, e := range o.Effects {
+			res[i].Effects = append(res[i].Effects, e.entity())
+		}
+	}
+
+	b, _ := json.Marshal(res)
+	return types.JSONB(b)
+}
+
+func makeQuiz(q *Quiz, vocabIDs map[string]uuid.UUID) types.JSONB {
+	quiz := entity.Quiz{
+		WordID:       vocabIDs[q.Word],
+		Ask:          entity.WordForm(q.Ask),
+		Answer:       entity.WordForm(q.Answer),
+		HeartPenalty: q.penalty(),
+	}
+	for _, d := range q.Distractors {
+		quiz.DistractorIDs = append(quiz.DistractorIDs, vocabIDs[d])
+	}
+
+	b, _ := json.Marshal(quiz)
+	return types.JSONB(b)
+}
+
+func makeTranslation(t *Translation) types.JSONB {
+	b, _ := json.Marshal(entity.Translation{
+		Prompt:       t.Prompt,
+		Accepted:     t.Accepted,
+		HeartPenalty: t.penalty(),
+	})
+	return types.JSONB(b)
+}
+
+func makeRoutes(routes []Route, realIDs map[string]uuid.UUID) types.JSONB {
+	res := make([]entity.Route, len(routes))
+	for i, r := range routes {
+		res[i] = entity.Route{
+			If:          r.If,
+			NextSlideID: realIDs[r.Next],
+		}
+	}
+
+	b, _ := json.Marshal(res)
+	return types.JSONB(b)
+}
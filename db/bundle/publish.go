@@ -0,0 +1,74 @@
+package bundle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	adminRepo "github.com/Ablebil/lathi-be/internal/app/admin/repository"
+	"github.com/Ablebil/lathi-be/internal/app/story/graph"
+	"github.com/Ablebil/lathi-be/internal/domain/entity"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrNothingToPublish is returned by Publish when the chapter is the same as
+// its published version.
+var ErrNothingToPublish = errors.New("chapter is the same as its published version")
+
+// Publish snapshots the stored chapter as its next version and makes it the
+// one new runs start on, like the admin publish endpoint. Runs in progress
+// stay on their version until the slot is restarted.
+func Publish(db *gorm.DB, chapterID uuid.UUID) (*entity.ChapterVersion, error) {
+	var version *entity.ChapterVersion
+	err := db.Transaction(func(tx *gorm.DB) error {
+		// publishes of the same chapter are numbered one after another
+		var locked entity.Chapter
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", chapterID).First(&locked).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("chapter %s not found", chapterID)
+		}
+		if err != nil {
+			return err
+		}
+
+		chapter, err := loadChapter(tx, chapterID)
+		if err != nil {
+			return err
+		}
+		if len(chapter.Slides) == 0 {
+			return fmt.Errorf("chapter %s has no slides", chapterID)
+		}
+
+		g, err := graph.FromChapter(chapter)
+		if err != nil {
+			return err
+		}
+		if _, err := graph.Check(g); err != nil {
+			return err
+		}
+
+		if chapter.PublishedVersionID != nil {
+			var published entity.ChapterVersion
+			if err := tx.Where("id = ?", *chapter.PublishedVersionID).First(&published).Error; err != nil {
+				return err
+			}
+			same, err := published.SameAs(chapter)
+			if err != nil {
+				return err
+			}
+			if same {
+				return ErrNothingToPublish
+			}
+		}
+
+		version, err = adminRepo.NewAdminRepository(tx).PublishChapter(context.Background(), chapter)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return version, nil
+}
@@ -6,6 +6,9 @@ import (
 	"github.com/Ablebil/lathi-be/internal/config"
 	"github.com/Ablebil/lathi-be/internal/domain/entity"
 	"github.com/Ablebil/lathi-be/internal/infra/postgresql"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 func Migrate(env *config.Env, action string) {
@@ -19,17 +22,86 @@ func Migrate(env *config.Env, action string) {
 		&entity.User{},
 		&entity.Dictionary{},
 		&entity.UserVocabulary{},
+		&entity.UserWordResult{},
+		&entity.Story{},
+		&entity.UserStoryProgress{},
 		&entity.Chapter{},
+		&entity.ChapterEnding{},
 		&entity.Slide{},
+		&entity.ChapterVersion{},
 		&entity.UserStorySession{},
+		&entity.UserStoryAction{},
+		&entity.StoryEvent{},
+		&entity.StoryAttempt{},
+		&entity.UserStoryVariables{},
+		&entity.UserEnding{},
+		&entity.UserChapterCompletion{},
 		&entity.Badge{},
 		&entity.UserBadge{},
+		&entity.OutboxEvent{},
 	}
 
 	switch action {
 	case "up":
+		if err := backfillChapterStories(db); err != nil {
+			slog.Error("failed to move chapters into the main story", "error", err)
+			return
+		}
+
+		if err := renameChaptersCompleted(db); err != nil {
+			slog.Error("failed to rename the completed chapter count", "error", err)
+			return
+		}
+
 		if err := db.AutoMigrate(models...); err != nil {
 			slog.Error("migration failed", "error", err)
+			return
+		}
+
+		if err := restrictUserEndings(db); err != nil {
+			slog.Error("failed to restrict ending deletes", "error", err)
+		}
+
+		if err := createMainStory(db); err != nil {
+			slog.Error("failed to create the main story", "error", err)
+		}
+
+		if err := backfillStoryProgress(db); err != nil {
+			slog.Error("failed to backfill story progress", "error", err)
+		}
+
+		if err := backfillChapterCompletions(db); err != nil {
+			slog.Error("failed to backfill chapter completions", "error", err)
+		} else if err := syncChaptersCompleted(db); err != nil {
+			slog.Error("failed to count completed chapters", "error", err)
+		}
+
+		if err := backfillChapterStart(db); err != nil {
+			slog.Error("failed to backfill chapter start slides", "error", err)
+		}
+
+		// sessions used to be unique per chapter, save slots need the index to include the slot
+		if db.Migrator().HasIndex(&entity.UserStorySession{}, "idx_user_chapter") {
+			if err := db.Migrator().DropIndex(&entity.UserStorySession{}, "idx_user_chapter"); err != nil {
+				slog.Error("failed to drop legacy session index", "error", err)
+			}
+		}
+
+		if err := backfillChapterVersions(db); err != nil {
+			slog.Error("failed to backfill chapter versions", "error", err)
+		}
+
+		if err := backfillAttempts(db); err != nil {
+			slog.Error("failed to backfill story attempts", "error", err)
+		}
+
+		// history used to be a jsonb array on the session, move it into events once
+		if db.Migrator().HasColumn(&entity.UserStorySession{}, "history_log") {
+			if err := backfillStoryEvents(db); err != nil {
+				slog.Error("failed to backfill story events", "error", err)
+			} else if err := db.Migrator().DropColumn(&entity.UserStorySession{}, "history_log"); err != nil {
+				slog.Error("failed to drop session history log", "error", err)
+			}
 		}
 	case "down":
 		if err := db.Migrator().DropTable(models...); err != nil {
@@ -39,3 +111,220 @@ func Migrate(env *config.Env, action string) {
 
 	slog.Info("migration done")
 }
+
+// reached endings used to go with their ending on delete, AutoMigrate does
+// not change an existing constraint so it is rebuilt once
+func restrictUserEndings(db *gorm.DB) error {
+	var cascade bool
+	err := db.Raw(`
+		SELECT EXISTS (
+			SELECT 1 FROM pg_constraint
+			WHERE conrelid = 'user_endings'::regclass
+				AND confrelid = 'chapter_endings'::regclass
+				AND confdeltype = 'c'
+		)`).Scan(&cascade).Error
+	if err != nil || !cascade {
+		return err
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Migrator().DropConstraint(&entity.UserEnding{}, "Ending"); err != nil {
+			return err
+		}
+		return tx.Migrator().CreateConstraint(&entity.UserEnding{}, "Ending")
+	})
+}
+
+// the user's chapter count used to be called last_chapter_completed, back
+// when it was the order index of the furthest chapter
+func renameChaptersCompleted(db *gorm.DB) error {
+	m := db.Migrator()
+	if !m.HasTable(&entity.User{}) || !m.HasColumn(&entity.User{}, "last_chapter_completed") {
+		return nil
+	}
+	return m.RenameColumn(&entity.User{}, "last_chapter_completed", "chapters_completed")
+}
+
+// chapters created before stories existed all belong to the main story, the
+// column has to be filled before it can be required
+func backfillChapterStories(db *gorm.DB) error {
+	m := db.Migrator()
+	if !m.HasTable(&entity.Chapter{}) || m.HasColumn(&entity.Chapter{}, "story_id") {
+		return nil
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.AutoMigrate(&entity.Story{}); err != nil {
+			return err
+		}
+		if err := createMainStory(tx); err != nil {
+			return err
+		}
+		if err := tx.Exec("ALTER TABLE chapters ADD COLUMN story_id char(36)").Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("UPDATE chapters SET story_id = (SELECT id FROM stories WHERE slug = ?)", entity.MainStorySlug).Error; err != nil {
+			return err
+		}
+		return tx.Exec("ALTER TABLE chapters ALTER COLUMN story_id SET NOT NULL").Error
+	})
+}
+
+// the main story always exists, chapters and progress default to it. The
+// seeder fills in its title and cover.
+func createMainStory(db *gorm.DB) error {
+	return db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "slug"}},
+		DoNothing: true,
+	}).Create(&entity.Story{
+		Slug:       entity.MainStorySlug,
+		Title:      "Lathi",
+		OrderIndex: 1,
+	}).Error
+}
+
+// progress from before stories existed was progress in the main story
+func backfillStoryProgress(db *gorm.DB) error {
+	return db.Exec(`
+		INSERT INTO user_story_progresses (user_id, story_id, title, updated_at)
+		SELECT u.id, s.id, u.current_title, u.updated_at
+		FROM users u JOIN stories s ON s.slug = ?
+		WHERE u.chapters_completed > 0
+			AND NOT EXISTS (SELECT 1 FROM user_story_progresses p WHERE p.user_id = u.id)`, entity.MainStorySlug).Error
+}
+
+// progress used to be the last chapter completed in each story, and before
+// stories the user's count in the main one, with chapters unlocking one after
+// another every chapter up to it was completed
+func backfillChapterCompletions(db *gorm.DB) error {
+	if db.Migrator().HasColumn(&entity.UserStoryProgress{}, "last_chapter_completed") {
+		err := db.Exec(`
+			INSERT INTO user_chapter_completions (user_id, chapter_id, completed_at)
+			SELECT p.user_id, c.id, p.updated_at
+			FROM user_story_progresses p
+			JOIN chapters c ON c.story_id = p.story_id AND c.order_index <= p.last_chapter_completed
+			WHERE NOT EXISTS (SELECT 1 FROM user_chapter_completions ucc WHERE ucc.user_id = p.user_id)
+			ON CONFLICT DO NOTHING`).Error
+		if err != nil {
+			return err
+		}
+		if err := db.Migrator().DropColumn(&entity.UserStoryProgress{}, "last_chapter_completed"); err != nil {
+			return err
+		}
+	}
+
+	return db.Exec(`
+		INSERT INTO user_chapter_completions (user_id, chapter_id, completed_at)
+		SELECT u.id, c.id, u.updated_at
+		FROM users u
+		JOIN stories s ON s.slug = ?
+		JOIN chapters c ON c.story_id = s.id AND c.order_index <= u.chapters_completed
+		WHERE NOT EXISTS (SELECT 1 FROM user_chapter_completions ucc WHERE ucc.user_id = u.id)
+		ON CONFLICT DO NOTHING`, entity.MainStorySlug).Error
+}
+
+// the user's chapter count is the published chapters they completed
+func syncChaptersCompleted(db *gorm.DB) error {
+	return db.Exec(`
+		UPDATE users SET chapters_completed = (
+			SELECT COUNT(*) FROM user_chapter_completions ucc
+			JOIN chapters c ON c.id = ucc.chapter_id AND c.published_version_id IS NOT NULL
+			WHERE ucc.user_id = users.id
+		)`).Error
+}
+
+// chapters created before start slides existed start at the slide no other
+// slide points to, falling back to the oldest slide
+func backfillChapterStart(db *gorm.DB) error {
+	return db.Exec(`
+		UPDATE chapters c SET start_slide_id = (
+			SELECT s.id FROM slides s
+			WHERE s.chapter_id = c.id
+			ORDER BY EXISTS (
+				SELECT 1 FROM slides o
+				WHERE o.chapter_id = c.id AND o.id <> s.id AND (
+					o.next_slide_id = s.id OR
+					o.choices @> jsonb_build_array(jsonb_build_object('next_slide_id', s.id::text))
+				)
+			), s.id
+			LIMIT 1
+		)
+		WHERE c.start_slide_id IS NULL`).Error
+}
+
+// chapters played before versioning are published as they stand, so players
+// keep seeing them, and the runs already going are pinned to that version
+func backfillChapterVersions(db *gorm.DB) error {
+	var ids []uuid.UUID
+	err := db.Model(&entity.Chapter{}).
+		Where("published_version_id IS NULL").
+		Where("EXISTS (SELECT 1 FROM slides s WHERE s.chapter_id = chapters.id)").
+		Where("NOT EXISTS (SELECT 1 FROM chapter_versions cv WHERE cv.chapter_id = chapters.id)").
+		Pluck("id", &ids).Error
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		err := db.Transaction(func(tx *gorm.DB) error {
+			var chapter entity.Chapter
+			err := tx.
+				Preload("Slides", func(db *gorm.DB) *gorm.DB {
+					return db.Order("id ASC")
+				}).
+				Preload("Slides.Vocabularies", func(db *gorm.DB) *gorm.DB {
+					return db.Order("word_krama ASC")
+				}).
+				Preload("Endings", func(db *gorm.DB) *gorm.DB {
+					return db.Order("key ASC")
+				}).
+				Where("id = ?", id).
+				First(&chapter).Error
+			if err != nil {
+				return err
+			}
+
+			version, err := entity.NewChapterVersion(&chapter, 1)
+			if err != nil {
+				return err
+			}
+			if err := tx.Omit(clause.Associations).Create(version).Error; err != nil {
+				return err
+			}
+			return tx.Model(&entity.Chapter{}).Where("id = ?", id).Update("published_version_id", version.ID).Error
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return db.Exec(`
+		UPDATE user_story_sessions s SET chapter_version_id = c.published_version_id
+		FROM chapters c
+		WHERE c.id = s.chapter_id AND s.chapter_version_id IS NULL AND c.published_version_id IS NOT NULL`).Error
+}
+
+// sessions played before attempts were recorded get one attempt each, built
+// from what the session still knows. Choices of those runs are lost.
+func backfillAttempts(db *gorm.DB) error {
+	return db.Exec(`
+		INSERT INTO story_attempts (id, user_id, chapter_id, slot, origin, outcome, hearts_lost, choices, ending_id, started_at, ended_at)
+		SELECT gen_random_uuid()::text, s.user_id, s.chapter_id, s.slot, 'start',
+			CASE WHEN s.is_completed THEN 'completed' WHEN s.is_game_over THEN 'game_over' ELSE 'in_progress' END,
+			GREATEST(3 - s.current_hearts, 0), '[]'::jsonb, s.ending_id, s.created_at,
+			CASE WHEN s.is_completed OR s.is_game_over THEN s.updated_at END
+		FROM user_story_sessions s
+		WHERE NOT EXISTS (
+			SELECT 1 FROM story_attempts a
+			WHERE a.user_id = s.user_id AND a.chapter_id = s.chapter_id AND a.slot = s.slot
+		)`).Error
+}
+
+func backfillStoryEvents(db *gorm.DB) error {
+	return db.Exec(`
+		INSERT INTO story_events (id, session_id, sequence, speaker, text, is_user, created_at)
+		SELECT gen_random_uuid()::text, s.id, e.ord, COALESCE(e.value->>'speaker', ''), COALESCE(e.value->>'text', ''),
+			COALESCE((e.value->>'is_user')::boolean, false), COALESCE((e.value->>'timestamp')::timestamptz, s.updated_at)
+		FROM user_story_sessions s, jsonb_array_elements(s.history_log) WITH ORDINALITY AS e(value, ord)
+		WHERE NOT EXISTS (SELECT 1 FROM story_events se WHERE se.session_id = s.id)`).Error
+}
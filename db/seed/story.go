@@ -1,13 +1,13 @@
 package seed
 
 import (
-	"encoding/json"
+	"errors"
 	"log/slog"
 
+	"github.com/Ablebil/lathi-be/db/bundle"
 	"github.com/Ablebil/lathi-be/internal/domain/entity"
-	"github.com/Ablebil/lathi-be/internal/domain/types"
-	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type StorySeeder struct{}
@@ -32,6 +32,7 @@ type slideData struct {
 	NextSlideKey string
 	Choices      []choiceSeedData
 	VocabKeys    []string
+	Checkpoint   bool
 }
 
 func (s *StorySeeder) Run(db *gorm.DB) error {
@@ -85,6 +86,7 @@ func (s *StorySeeder) Run(db *gorm.DB) error {
 		{WordKrama: "setunggal", WordNgoko: "siji", WordIndo: "satu"},
 		{WordKrama: "kersa", WordNgoko: "gelem", WordIndo: "mau/bersedia"},
 		{WordKrama: "remen", WordNgoko: "seneng", WordIndo: "suka/senang"},
+		{WordKrama: "mboten", WordNgoko: "ora", WordIndo: "tidak (alus)"},
 
 		// ch 4
 		{WordKrama: "ngarsanipun", WordNgoko: "ngarepe", WordIndo: "di hadapan"},
@@ -105,7 +107,6 @@ func (s *StorySeeder) Run(db *gorm.DB) error {
 		{WordKrama: "ajrih", WordNgoko: "wedi", WordIndo: "takut/segan"},
 	}
 
-	vocabMap := make(map[string]uuid.UUID)
 	for _, v := range vocabs {
 		var dict entity.Dictionary
 		err := db.Where("word_krama = ?", v.WordKrama).First(&dict).Error
@@ -114,31 +115,45 @@ func (s *StorySeeder) Run(db *gorm.DB) error {
 				if err := db.Create(&v).Error; err != nil {
 					return err
 				}
-				dict = v
 			} else {
 				return err
 			}
 		}
-		vocabMap[v.WordKrama] = dict.ID
+	}
+
+	// the migration creates the main story, the seeder gives it its cover
+	mainStory := entity.Story{
+		Slug:          entity.MainStorySlug,
+		Title:         "Lathi",
+		Description:   "Andi budhal nang Tulungagung lan sinau basa krama saben lakune.",
+		CoverImageURL: "stories/main_cover.webp",
+		OrderIndex:    1,
+	}
+	if err := db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "slug"}},
+		DoUpdates: clause.AssignmentColumns([]string{"title", "description", "cover_image_url"}),
+	}).Create(&mainStory).Error; err != nil {
+		slog.Error("failed to seed main story", "error", err)
+		return err
 	}
 
 	// execute chapter seeders
-	if err := seedChapter1(db, vocabMap); err != nil {
+	if err := seedChapter1(db); err != nil {
 		slog.Error("failed to seed chapter 1", "error", err)
 		return err
 	}
 
-	if err := seedChapter2(db, vocabMap); err != nil {
+	if err := seedChapter2(db); err != nil {
 		slog.Error("failed to seed chapter 2", "error", err)
 		return err
 	}
 
-	if err := seedChapter3(db, vocabMap); err != nil {
+	if err := seedChapter3(db); err != nil {
 		slog.Error("failed to seed chapter 3", "error", err)
 		return err
 	}
 
-	if err := seedChapter4(db, vocabMap); err != nil {
+	if err := seedChapter4(db); err != nil {
 		slog.Error("failed to seed chapter 4", "error", err)
 		return err
 	}
@@ -147,23 +162,71 @@ func (s *StorySeeder) Run(db *gorm.DB) error {
 	return nil
 }
 
-func makeChoicesWithRealIDs(opts []choiceSeedData, realIDs map[string]uuid.UUID) types.JSONB {
-	type choiceJSON struct {
-		Text        string    `json:"text"`
-		NextSlideID uuid.UUID `json:"next_slide_id"`
-		MoodImpact  int       `json:"mood_impact"`
+// every seeded chapter is played as Andi
+var andiProtagonist = bundle.Protagonist{
+	Name: "Andi",
+	Sprites: map[string]string{
+		"happy":   "chars/andi_happy.webp",
+		"neutral": "chars/andi_neutral.webp",
+		"nervous": "chars/andi_nervous.webp",
+		"shocked": "chars/andi_shocked.webp",
+	},
+}
+
+// importChapter turns hand-written slide data into a chapter bundle and
+// imports it, so re-running the seeder updates slides instead of duplicating them
+func importChapter(db *gorm.DB, chapter bundle.Chapter, slidesData []slideData) error {
+	if chapter.Protagonist == nil {
+		chapter.Protagonist = &andiProtagonist
 	}
 
-	res := make([]choiceJSON, len(opts))
-	for i, o := range opts {
-		nextID := realIDs[o.NextSlideKey]
-		res[i] = choiceJSON{
-			Text:        o.Text,
-			NextSlideID: nextID,
-			MoodImpact:  o.MoodImpact,
+	b := &bundle.Bundle{
+		Version: bundle.Version,
+		Chapter: chapter,
+	}
+
+	for _, d := range slidesData {
+		slide := bundle.Slide{
+			Key:                d.Key,
+			Speaker:            d.Speaker,
+			BackgroundImageURL: d.BgImg,
+			Content:            d.Content,
+			Next:               d.NextSlideKey,
+			Checkpoint:         d.Checkpoint,
+			Vocab:              d.VocabKeys,
+		}
+
+		for _, c := range d.Characters {
+			slide.Characters = append(slide.Characters, bundle.Character{Name: c.Name, ImageURL: c.Img})
+		}
+
+		for _, c := range d.Choices {
+			slide.Choices = append(slide.Choices, bundle.Choice{Text: c.Text, Next: c.NextSlideKey, MoodImpact: c.MoodImpact})
 		}
+
+		b.Slides = append(b.Slides, slide)
+	}
+
+	res, err := bundle.Import(db, b)
+	if err != nil {
+		return err
 	}
 
-	b, _ := json.Marshal(res)
-	return types.JSONB(b)
+	slog.Info("chapter imported",
+		"order_index", chapter.OrderIndex,
+		"created", res.SlidesCreated,
+		"updated", res.SlidesUpdated,
+		"deleted", res.SlidesDeleted)
+
+	// seeded chapters go live right away, re-running the seeder only publishes changes
+	version, err := bundle.Publish(db, res.ChapterID)
+	if errors.Is(err, bundle.ErrNothingToPublish) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	slog.Info("chapter published", "order_index", chapter.OrderIndex, "version", version.Number)
+	return nil
 }
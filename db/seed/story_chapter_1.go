@@ -1,34 +1,20 @@
 package seed
 
 import (
-	"encoding/json"
 	"log/slog"
 
-	"github.com/Ablebil/lathi-be/internal/domain/entity"
-	"github.com/Ablebil/lathi-be/internal/domain/types"
-	"github.com/google/uuid"
+	"github.com/Ablebil/lathi-be/db/bundle"
 	"gorm.io/gorm"
 )
 
-func seedChapter1(db *gorm.DB, vocabMap map[string]uuid.UUID) error {
+func seedChapter1(db *gorm.DB) error {
 	slog.Info("seeding chapter 1...")
 
-	var chapter entity.Chapter
-	err := db.Where("order_index = ?", 1).First(&chapter).Error
-	if err != nil {
-		if err == gorm.ErrRecordNotFound {
-			chapter = entity.Chapter{
-				Title:         "Ana Kabar Kaget!",
-				Description:   "Andi kudu nekat budhal nang Tulungagung demi mjuangne tresnane.",
-				CoverImageURL: "chapters/ch1_cover.webp",
-				OrderIndex:    1,
-			}
-			if err := db.Create(&chapter).Error; err != nil {
-				return err
-			}
-		} else {
-			return err
-		}
+	chapter := bundle.Chapter{
+		Title:         "Ana Kabar Kaget!",
+		Description:   "Andi kudu nekat budhal nang Tulungagung demi mjuangne tresnane.",
+		CoverImageURL: "chapters/ch1_cover.webp",
+		OrderIndex:    1,
 	}
 
 	andi := func(exp string) charData { return charData{Name: "Andi", Img: "chars/andi_" + exp + ".webp"} }
@@ -120,64 +106,5 @@ func seedChapter1(db *gorm.DB, vocabMap map[string]uuid.UUID) error {
 		{Key: "44", Speaker: "Narator", BgImg: "bg/warmindo.webp", Content: "Punapa Andi {badhe} kasil sinau tata krama saking Pakdhe Joyo? Entosi cariyos salajengipun.", VocabKeys: []string{"badhe"}},
 	}
 
-	realIDs := make(map[string]uuid.UUID)
-
-	for _, d := range slidesData {
-		slide := entity.Slide{
-			ChapterID:          chapter.ID,
-			SpeakerName:        d.Speaker,
-			Content:            d.Content,
-			BackgroundImageURL: d.BgImg,
-		}
-
-		var charJson []map[string]interface{}
-		for _, c := range d.Characters {
-			isActive := (c.Name == d.Speaker)
-			charJson = append(charJson, map[string]interface{}{
-				"name":      c.Name,
-				"image_url": c.Img,
-				"is_active": isActive,
-			})
-		}
-		if len(charJson) > 0 {
-			bytes, _ := json.Marshal(charJson)
-			slide.Characters = types.JSONB(bytes)
-		} else {
-			slide.Characters = types.JSONB("[]")
-		}
-
-		for _, vKey := range d.VocabKeys {
-			if vID, ok := vocabMap[vKey]; ok {
-				slide.Vocabularies = append(slide.Vocabularies, entity.Dictionary{ID: vID})
-			}
-		}
-
-		if err := db.Create(&slide).Error; err != nil {
-			return err
-		}
-
-		realIDs[d.Key] = slide.ID
-	}
-
-	for _, d := range slidesData {
-		updates := map[string]interface{}{}
-
-		if d.NextSlideKey != "" {
-			if nextRealID, ok := realIDs[d.NextSlideKey]; ok {
-				updates["next_slide_id"] = nextRealID
-			}
-		}
-
-		if len(d.Choices) > 0 {
-			updates["choices"] = makeChoicesWithRealIDs(d.Choices, realIDs)
-		}
-
-		if len(updates) > 0 {
-			if err := db.Model(&entity.Slide{}).Where("id = ?", realIDs[d.Key]).Updates(updates).Error; err != nil {
-				return err
-			}
-		}
-	}
-
-	return nil
+	return importChapter(db, chapter, slidesData)
 }
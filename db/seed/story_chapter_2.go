@@ -1,34 +1,20 @@
 package seed
 
 import (
-	"encoding/json"
 	"log/slog"
 
-	"github.com/Ablebil/lathi-be/internal/domain/entity"
-	"github.com/Ablebil/lathi-be/internal/domain/types"
-	"github.com/google/uuid"
+	"github.com/Ablebil/lathi-be/db/bundle"
 	"gorm.io/gorm"
 )
 
-func seedChapter2(db *gorm.DB, vocabMap map[string]uuid.UUID) error {
+func seedChapter2(db *gorm.DB) error {
 	slog.Info("seeding chapter 2...")
 
-	var chapter entity.Chapter
-	err := db.Where("order_index = ?", 2).First(&chapter).Error
-	if err != nil {
-		if err == gorm.ErrRecordNotFound {
-			chapter = entity.Chapter{
-				Title:         "Sinau Dadi Priyayi",
-				Description:   "Andi meguru tata krama nang Pakdhe Joyo ben ora ngisin-ngisini.",
-				CoverImageURL: "chapters/ch2_cover.webp",
-				OrderIndex:    2,
-			}
-			if err := db.Create(&chapter).Error; err != nil {
-				return err
-			}
-		} else {
-			return err
-		}
+	chapter := bundle.Chapter{
+		Title:         "Sinau Dadi Priyayi",
+		Description:   "Andi meguru tata krama nang Pakdhe Joyo ben ora ngisin-ngisini.",
+		CoverImageURL: "chapters/ch2_cover.webp",
+		OrderIndex:    2,
 	}
 
 	andi := func(exp string) charData { return charData{Name: "Andi", Img: "chars/andi_" + exp + ".webp"} }
@@ -198,64 +184,5 @@ func seedChapter2(db *gorm.DB, vocabMap map[string]uuid.UUID) error {
 		{Key: "41", Speaker: "Narator", BgImg: "bg/teras_joglo.webp", Content: "Punapa Andi badhe kasil ngluluhaken manahipun Bu Tejo kanthi basa kramanipun? Entosi cariyos salajengipun."},
 	}
 
-	realIDs := make(map[string]uuid.UUID)
-
-	for _, d := range slidesData {
-		slide := entity.Slide{
-			ChapterID:          chapter.ID,
-			SpeakerName:        d.Speaker,
-			Content:            d.Content,
-			BackgroundImageURL: d.BgImg,
-		}
-
-		var charJson []map[string]interface{}
-		for _, c := range d.Characters {
-			isActive := (c.Name == d.Speaker)
-			charJson = append(charJson, map[string]interface{}{
-				"name":      c.Name,
-				"image_url": c.Img,
-				"is_active": isActive,
-			})
-		}
-		if len(charJson) > 0 {
-			bytes, _ := json.Marshal(charJson)
-			slide.Characters = types.JSONB(bytes)
-		} else {
-			slide.Characters = types.JSONB("[]")
-		}
-
-		for _, vKey := range d.VocabKeys {
-			if vID, ok := vocabMap[vKey]; ok {
-				slide.Vocabularies = append(slide.Vocabularies, entity.Dictionary{ID: vID})
-			}
-		}
-
-		if err := db.Create(&slide).Error; err != nil {
-			return err
-		}
-
-		realIDs[d.Key] = slide.ID
-	}
-
-	for _, d := range slidesData {
-		updates := map[string]interface{}{}
-
-		if d.NextSlideKey != "" {
-			if nextRealID, ok := realIDs[d.NextSlideKey]; ok {
-				updates["next_slide_id"] = nextRealID
-			}
-		}
-
-		if len(d.Choices) > 0 {
-			updates["choices"] = makeChoicesWithRealIDs(d.Choices, realIDs)
-		}
-
-		if len(updates) > 0 {
-			if err := db.Model(&entity.Slide{}).Where("id = ?", realIDs[d.Key]).Updates(updates).Error; err != nil {
-				return err
-			}
-		}
-	}
-
-	return nil
+	return importChapter(db, chapter, slidesData)
 }
@@ -1,34 +1,20 @@
 package seed
 
 import (
-	"encoding/json"
 	"log/slog"
 
-	"github.com/Ablebil/lathi-be/internal/domain/entity"
-	"github.com/Ablebil/lathi-be/internal/domain/types"
-	"github.com/google/uuid"
+	"github.com/Ablebil/lathi-be/db/bundle"
 	"gorm.io/gorm"
 )
 
-func seedChapter3(db *gorm.DB, vocabMap map[string]uuid.UUID) error {
+func seedChapter3(db *gorm.DB) error {
 	slog.Info("seeding chapter 3...")
 
-	var chapter entity.Chapter
-	err := db.Where("order_index = ?", 3).First(&chapter).Error
-	if err != nil {
-		if err == gorm.ErrRecordNotFound {
-			chapter = entity.Chapter{
-				Title:         "Golek Gawan Sowan",
-				Description:   "Andi kudu pinter milih batik lan nawar rego ngadepi Bu Tejo sing galak.",
-				CoverImageURL: "chapters/ch3_cover.webp",
-				OrderIndex:    3,
-			}
-			if err := db.Create(&chapter).Error; err != nil {
-				return err
-			}
-		} else {
-			return err
-		}
+	chapter := bundle.Chapter{
+		Title:         "Golek Gawan Sowan",
+		Description:   "Andi kudu pinter milih batik lan nawar rego ngadepi Bu Tejo sing galak.",
+		CoverImageURL: "chapters/ch3_cover.webp",
+		OrderIndex:    3,
 	}
 
 	andi := func(exp string) charData { return charData{Name: "Andi", Img: "chars/andi_" + exp + ".webp"} }
@@ -190,7 +176,7 @@ func seedChapter3(db *gorm.DB, vocabMap map[string]uuid.UUID) error {
 			Choices: []choiceSeedData{
 				{Text: "Bapak senengane opo?", NextSlideKey: "43a", MoodImpact: 0},
 				{Text: "{Kersa}nipun Bapak {menika} kados pundi?", NextSlideKey: "43b", MoodImpact: 1},
-				{Text: "Bapak {remenipun} napa?", NextSlideKey: "43c", MoodImpact: 0},
+				{Text: "Bapak {remen}ipun napa?", NextSlideKey: "43c", MoodImpact: 0},
 			},
 			VocabKeys: []string{"kula", "kersa", "menika", "remen"},
 		},
@@ -198,7 +184,7 @@ func seedChapter3(db *gorm.DB, vocabMap map[string]uuid.UUID) error {
 		// branch choice 7
 		{Key: "43a", Speaker: "Bu Tejo", BgImg: "bg/toko_batik.webp", Characters: []charData{andi("neutral"), butejo("neutral")}, Content: "Duh, basamu lho Mas. Isih pating pecotot. '{Kersa}nipun' ngono lho.", NextSlideKey: "44", VocabKeys: []string{"kersa"}},
 		{Key: "43b", Speaker: "Bu Tejo", BgImg: "bg/toko_batik.webp", Characters: []charData{andi("happy"), butejo("happy")}, Content: "Nah, pinter. Priyayi iku seneng wong sing 'Genah'. Nek ditakoni, jawabe sing mantep. Aja plin-plan.", NextSlideKey: "44"},
-		{Key: "43c", Speaker: "Bu Tejo", BgImg: "bg/toko_batik.webp", Characters: []charData{andi("neutral"), butejo("neutral")}, Content: "'{Remenipun}' wis bener, tapi kurang alus {sakedhik} nek kangge Priyayi sepuh.", NextSlideKey: "44", VocabKeys: []string{"remen", "sakedhik"}},
+		{Key: "43c", Speaker: "Bu Tejo", BgImg: "bg/toko_batik.webp", Characters: []charData{andi("neutral"), butejo("neutral")}, Content: "'{Remen}ipun' wis bener, tapi kurang alus {sakedhik} nek kangge Priyayi sepuh.", NextSlideKey: "44", VocabKeys: []string{"remen", "sakedhik"}},
 
 		// merge path
 		{Key: "44", Speaker: "Bu Tejo", BgImg: "bg/toko_batik.webp", Characters: []charData{andi("happy"), butejo("teaching")}, Content: "Lan siji maneh... Aja sok sugih. Priyayi jaman semono luwih ngregani 'Unggah-ungguh' timbang unggah-unggahan bondo.", NextSlideKey: "45"},
@@ -229,64 +215,5 @@ func seedChapter3(db *gorm.DB, vocabMap map[string]uuid.UUID) error {
 		{Key: "52", Speaker: "Narator", BgImg: "bg/toko_batik.webp", Content: "Nanging samenika, Andi sampun langkung siyap. Wancinipun budhal dhateng medan perang sejatine: Tulungagung."},
 	}
 
-	realIDs := make(map[string]uuid.UUID)
-
-	for _, d := range slidesData {
-		slide := entity.Slide{
-			ChapterID:          chapter.ID,
-			SpeakerName:        d.Speaker,
-			Content:            d.Content,
-			BackgroundImageURL: d.BgImg,
-		}
-
-		var charJson []map[string]interface{}
-		for _, c := range d.Characters {
-			isActive := (c.Name == d.Speaker)
-			charJson = append(charJson, map[string]interface{}{
-				"name":      c.Name,
-				"image_url": c.Img,
-				"is_active": isActive,
-			})
-		}
-		if len(charJson) > 0 {
-			bytes, _ := json.Marshal(charJson)
-			slide.Characters = types.JSONB(bytes)
-		} else {
-			slide.Characters = types.JSONB("[]")
-		}
-
-		for _, vKey := range d.VocabKeys {
-			if vID, ok := vocabMap[vKey]; ok {
-				slide.Vocabularies = append(slide.Vocabularies, entity.Dictionary{ID: vID})
-			}
-		}
-
-		if err := db.Create(&slide).Error; err != nil {
-			return err
-		}
-
-		realIDs[d.Key] = slide.ID
-	}
-
-	for _, d := range slidesData {
-		updates := map[string]interface{}{}
-
-		if d.NextSlideKey != "" {
-			if nextRealID, ok := realIDs[d.NextSlideKey]; ok {
-				updates["next_slide_id"] = nextRealID
-			}
-		}
-
-		if len(d.Choices) > 0 {
-			updates["choices"] = makeChoicesWithRealIDs(d.Choices, realIDs)
-		}
-
-		if len(updates) > 0 {
-			if err := db.Model(&entity.Slide{}).Where("id = ?", realIDs[d.Key]).Updates(updates).Error; err != nil {
-				return err
-			}
-		}
-	}
-
-	return nil
+	return importChapter(db, chapter, slidesData)
 }
@@ -1,34 +1,20 @@
 package seed
 
 import (
-	"encoding/json"
 	"log/slog"
 
-	"github.com/Ablebil/lathi-be/internal/domain/entity"
-	"github.com/Ablebil/lathi-be/internal/domain/types"
-	"github.com/google/uuid"
+	"github.com/Ablebil/lathi-be/db/bundle"
 	"gorm.io/gorm"
 )
 
-func seedChapter4(db *gorm.DB, vocabMap map[string]uuid.UUID) error {
+func seedChapter4(db *gorm.DB) error {
 	slog.Info("seeding chapter 4...")
 
-	var chapter entity.Chapter
-	err := db.Where("order_index = ?", 4).First(&chapter).Error
-	if err != nil {
-		if err == gorm.ErrRecordNotFound {
-			chapter = entity.Chapter{
-				Title:         "Ngadepi Juragan Cengkeh",
-				Description:   "Ujian pungkasan. Andi bakal entuk restu apa malah kena penthung tongkate Pak Broto?",
-				CoverImageURL: "chapters/ch4_cover.webp",
-				OrderIndex:    4,
-			}
-			if err := db.Create(&chapter).Error; err != nil {
-				return err
-			}
-		} else {
-			return err
-		}
+	chapter := bundle.Chapter{
+		Title:         "Ngadepi Juragan Cengkeh",
+		Description:   "Ujian pungkasan. Andi bakal entuk restu apa malah kena penthung tongkate Pak Broto?",
+		CoverImageURL: "chapters/ch4_cover.webp",
+		OrderIndex:    4,
 	}
 
 	andi := func(exp string) charData { return charData{Name: "Andi", Img: "chars/andi_batik_" + exp + ".webp"} }
@@ -129,7 +115,7 @@ func seedChapter4(db *gorm.DB, vocabMap map[string]uuid.UUID) error {
 		{Key: "22c", Speaker: "Pak Broto", BgImg: "bg/ruang_tamu_pak_broto.webp", Characters: []charData{andi("happy"), pakbroto("neutral")}, Content: "Hmm... Jawabanmu apik. Wong lanang pancen kudu wani tanggung jawab lan usaha.", NextSlideKey: "23"},
 
 		// merge path
-		{Key: "23", Speaker: "Andi", BgImg: "bg/ruang_tamu_pak_broto.webp", Characters: []charData{andi("happy"), pakbroto("neutral")}, Content: "Oh inggih, Bapak. Menika {wonten} {sakedhik} tandha tresna {saking} Surabaya. (Nyerahke bungkusan Batik).", NextSlideKey: "24", VocabKeys: []string{"wonten", "sakedhik", "saking"}},
+		{Key: "23", Speaker: "Andi", BgImg: "bg/ruang_tamu_pak_broto.webp", Characters: []charData{andi("happy"), pakbroto("neutral")}, Content: "Oh inggih, Bapak. Menika {wonten} {sakedhik} tandha tresna {saking} Surabaya. (Nyerahke bungkusan Batik).", NextSlideKey: "24", Checkpoint: true, VocabKeys: []string{"wonten", "sakedhik", "saking"}},
 		{Key: "24", Speaker: "Pak Broto", BgImg: "bg/ruang_tamu_pak_broto.webp", Characters: []charData{andi("neutral"), pakbroto("neutral")}, Content: "(Nampani tanpa ngomong, mbukak bungkus pelan-pelan).", NextSlideKey: "25"},
 		{Key: "25", Speaker: "Narator", BgImg: "bg/ruang_tamu_pak_broto.webp", Content: "Swasana hening malih. Namung swara kertas krekek-krekek ingkang kepireng. Andi nahan ambegan.", NextSlideKey: "26"},
 		{Key: "26", Speaker: "Pak Broto", BgImg: "bg/ruang_tamu_pak_broto.webp", Characters: []charData{andi("nervous"), pakbroto("neutral")}, Content: "(Ndelok motif batike) Wahyu Tumurun... Sogan.", NextSlideKey: "27"},
@@ -156,7 +142,7 @@ func seedChapter4(db *gorm.DB, vocabMap map[string]uuid.UUID) error {
 		{Key: "29_2", Speaker: "Sekar", BgImg: "bg/ruang_tamu_pak_broto.webp", Characters: []charData{andi("happy"), sekar("happy"), pakbroto("neutral")}, Content: "(Saka mburi lawang, mesem seneng) Inggih Pak.", NextSlideKey: "30"},
 
 		// merge path
-		{Key: "30", Speaker: "Narator", BgImg: "bg/ruang_tamu_pak_broto.webp", Content: "Sekar medal mbeta kopi. Ambunipun sedhep, nanging uabipun taksih kemebul panas.", NextSlideKey: "31"},
+		{Key: "30", Speaker: "Narator", BgImg: "bg/ruang_tamu_pak_broto.webp", Content: "Sekar medal mbeta kopi. Ambunipun sedhep, nanging uabipun taksih kemebul panas.", NextSlideKey: "31", Checkpoint: true},
 		{Key: "31", Speaker: "Pak Broto", BgImg: "bg/ruang_tamu_pak_broto.webp", Characters: []charData{andi("neutral"), pakbroto("neutral")}, Content: "Diunjuk kopine.", NextSlideKey: "32"},
 		{Key: "32", Speaker: "Andi", BgImg: "bg/ruang_tamu_pak_broto.webp", Characters: []charData{andi("neutral"), pakbroto("neutral")}, Content: "Inggih Pak. (Andi nyekel gelas, panas!)", NextSlideKey: "33"},
 
@@ -216,7 +202,7 @@ func seedChapter4(db *gorm.DB, vocabMap map[string]uuid.UUID) error {
 			Content: "(Ngadeg, raine sumringah) (_Alhamdulillah! Sukses rek!_)",
 			Choices: []choiceSeedData{
 				{Text: "Suwun Pak, aku balik sek.", NextSlideKey: "49a", MoodImpact: -1},
-				{Text: "Matur nuwun Pak, {kula} {nyuwun} {pamit}.", NextSlideKey: "49b", MoodImpact: 1},
+				{Text: "Matur nuwun Pak, {kula} {nyuwun} pamit.", NextSlideKey: "49b", MoodImpact: 1},
 				{Text: "Nggih Pak, dadah.", NextSlideKey: "49c", MoodImpact: -1},
 			},
 			VocabKeys: []string{"kula", "nyuwun"},
@@ -235,64 +221,5 @@ func seedChapter4(db *gorm.DB, vocabMap map[string]uuid.UUID) error {
 		{Key: "54", Speaker: "Narator", BgImg: "bg/wedding_venue.webp", Content: "TAMAT."},
 	}
 
-	realIDs := make(map[string]uuid.UUID)
-
-	for _, d := range slidesData {
-		slide := entity.Slide{
-			ChapterID:          chapter.ID,
-			SpeakerName:        d.Speaker,
-			Content:            d.Content,
-			BackgroundImageURL: d.BgImg,
-		}
-
-		var charJson []map[string]interface{}
-		for _, c := range d.Characters {
-			isActive := (c.Name == d.Speaker)
-			charJson = append(charJson, map[string]interface{}{
-				"name":      c.Name,
-				"image_url": c.Img,
-				"is_active": isActive,
-			})
-		}
-		if len(charJson) > 0 {
-			bytes, _ := json.Marshal(charJson)
-			slide.Characters = types.JSONB(bytes)
-		} else {
-			slide.Characters = types.JSONB("[]")
-		}
-
-		for _, vKey := range d.VocabKeys {
-			if vID, ok := vocabMap[vKey]; ok {
-				slide.Vocabularies = append(slide.Vocabularies, entity.Dictionary{ID: vID})
-			}
-		}
-
-		if err := db.Create(&slide).Error; err != nil {
-			return err
-		}
-
-		realIDs[d.Key] = slide.ID
-	}
-
-	for _, d := range slidesData {
-		updates := map[string]interface{}{}
-
-		if d.NextSlideKey != "" {
-			if nextRealID, ok := realIDs[d.NextSlideKey]; ok {
-				updates["next_slide_id"] = nextRealID
-			}
-		}
-
-		if len(d.Choices) > 0 {
-			updates["choices"] = makeChoicesWithRealIDs(d.Choices, realIDs)
-		}
-
-		if len(updates) > 0 {
-			if err := db.Model(&entity.Slide{}).Where("id = ?", realIDs[d.Key]).Updates(updates).Error; err != nil {
-				return err
-			}
-		}
-	}
-
-	return nil
+	return importChapter(db, chapter, slidesData)
 }
@@ -45,71 +45,94 @@ func (s *UserSeeder) Run(db *gorm.DB) error {
 
 	users := []entity.User{
 		{
-			ID:                   uuid.New(),
-			Username:             "valen",
-			Email:                "valen@lathi.id",
-			Password:             password,
-			AvatarURL:            avatarURL,
-			IsVerified:           true,
-			CurrentTitle:         entity.Priyayi,
-			LastChapterCompleted: 4,
-			TotalWordsCollected:  58,
+			ID:                  uuid.New(),
+			Username:            "valen",
+			Email:               "valen@lathi.id",
+			Password:            password,
+			AvatarURL:           avatarURL,
+			IsVerified:          true,
+			CurrentTitle:        entity.Priyayi,
+			ChaptersCompleted:   4,
+			TotalWordsCollected: 58,
 		},
 		{
-			ID:                   uuid.New(),
-			Username:             "soma",
-			Email:                "soma@lathi.id",
-			Password:             password,
-			AvatarURL:            avatarURL,
-			IsVerified:           true,
-			CurrentTitle:         entity.Abdi,
-			LastChapterCompleted: 2,
-			TotalWordsCollected:  25,
+			ID:                  uuid.New(),
+			Username:            "soma",
+			Email:               "soma@lathi.id",
+			Password:            password,
+			AvatarURL:           avatarURL,
+			IsVerified:          true,
+			CurrentTitle:        entity.Abdi,
+			ChaptersCompleted:   2,
+			TotalWordsCollected: 25,
 		},
 		{
-			ID:                   uuid.New(),
-			Username:             "laras",
-			Email:                "laras@lathi.id",
-			Password:             password,
-			AvatarURL:            avatarURL,
-			IsVerified:           true,
-			CurrentTitle:         entity.Cantrik,
-			LastChapterCompleted: 1,
-			TotalWordsCollected:  15,
+			ID:                  uuid.New(),
+			Username:            "laras",
+			Email:               "laras@lathi.id",
+			Password:            password,
+			AvatarURL:           avatarURL,
+			IsVerified:          true,
+			CurrentTitle:        entity.Cantrik,
+			ChaptersCompleted:   1,
+			TotalWordsCollected: 15,
 		},
 		{
-			ID:                   uuid.New(),
-			Username:             "budi",
-			Email:                "budi@lathi.id",
-			Password:             password,
-			AvatarURL:            avatarURL,
-			IsVerified:           true,
-			CurrentTitle:         entity.Cantrik,
-			LastChapterCompleted: 0,
-			TotalWordsCollected:  7,
+			ID:                  uuid.New(),
+			Username:            "budi",
+			Email:               "budi@lathi.id",
+			Password:            password,
+			AvatarURL:           avatarURL,
+			IsVerified:          true,
+			CurrentTitle:        entity.Cantrik,
+			ChaptersCompleted:   0,
+			TotalWordsCollected: 7,
 		},
 		{
-			ID:                   uuid.New(),
-			Username:             "sari",
-			Email:                "sari@lathi.id",
-			Password:             password,
-			AvatarURL:            avatarURL,
-			IsVerified:           true,
-			CurrentTitle:         entity.Cantrik,
-			LastChapterCompleted: 1,
-			TotalWordsCollected:  12,
+			ID:                  uuid.New(),
+			Username:            "sari",
+			Email:               "sari@lathi.id",
+			Password:            password,
+			AvatarURL:           avatarURL,
+			IsVerified:          true,
+			CurrentTitle:        entity.Cantrik,
+			ChaptersCompleted:   1,
+			TotalWordsCollected: 12,
 		},
 	}
 
 	for _, u := range users {
 		if err := db.Clauses(clause.OnConflict{
 			Columns:   []clause.Column{{Name: "email"}},
-			DoUpdates: clause.AssignmentColumns([]string{"username", "password", "is_verified", "current_title", "last_chapter_completed", "total_words_collected"}),
+			DoUpdates: clause.AssignmentColumns([]string{"username", "password", "is_verified", "current_title", "chapters_completed", "total_words_collected"}),
 		}).Create(&u).Error; err != nil {
 			slog.Error("failed to seed user", "email", u.Email, "error", err)
 			return err
 		}
 
+		// seeded progress is all in the main story
+		if err := db.Exec(`
+			INSERT INTO user_story_progresses (user_id, story_id, title, updated_at)
+			SELECT u.id, s.id, u.current_title, u.updated_at
+			FROM users u JOIN stories s ON s.slug = ?
+			WHERE u.email = ? AND u.chapters_completed > 0
+			ON CONFLICT (user_id, story_id) DO UPDATE SET title = EXCLUDED.title`, entity.MainStorySlug, u.Email).Error; err != nil {
+			slog.Error("failed to seed story progress", "email", u.Email, "error", err)
+			return err
+		}
+
+		if err := db.Exec(`
+			INSERT INTO user_chapter_completions (user_id, chapter_id, completed_at)
+			SELECT u.id, c.id, u.updated_at
+			FROM users u
+			JOIN stories s ON s.slug = ?
+			JOIN chapters c ON c.story_id = s.id AND c.order_index <= u.chapters_completed
+			WHERE u.email = ?
+			ON CONFLICT DO NOTHING`, entity.MainStorySlug, u.Email).Error; err != nil {
+			slog.Error("failed to seed chapter completions", "email", u.Email, "error", err)
+			return err
+		}
+
 		var userBadges []entity.UserBadge
 		addBadge := func(code string) {
 			if id, ok := badgeMap[code]; ok {
@@ -0,0 +1,482 @@
+package handler
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/Ablebil/lathi-be/internal/domain/contract"
+	"github.com/Ablebil/lathi-be/internal/domain/dto"
+	"github.com/Ablebil/lathi-be/internal/domain/entity"
+	"github.com/Ablebil/lathi-be/internal/middleware"
+	"github.com/Ablebil/lathi-be/pkg/i18n"
+	"github.com/Ablebil/lathi-be/pkg/response"
+	"github.com/Ablebil/lathi-be/pkg/validator"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type adminHandler struct {
+	val validator.ValidatorItf
+	uc  contract.AdminUsecaseItf
+}
+
+func NewAdminHandler(router fiber.Router, validator validator.ValidatorItf, mw middleware.MiddlewareItf, adminUc contract.AdminUsecaseItf) {
+	handler := adminHandler{
+		val: validator,
+		uc:  adminUc,
+	}
+
+	read := mw.RequirePermission(entity.PermContentRead)
+	write := mw.RequirePermission(entity.PermContentWrite)
+	manage := mw.RequirePermission(entity.PermContentManage)
+	publish := mw.RequirePermission(entity.PermContentPublish)
+
+	adminRouter := router.Group("/admin", mw.Authenticate)
+	adminRouter.Get("/stories", read, mw.RateLimit(60, 1*time.Minute, "admin_read"), handler.listStories)
+	adminRouter.Post("/stories", manage, mw.RateLimit(30, 1*time.Minute, "admin_write"), handler.createStory)
+	adminRouter.Put("/stories/:id", write, mw.RateLimit(30, 1*time.Minute, "admin_write"), handler.updateStory)
+	adminRouter.Get("/chapters", read, mw.RateLimit(60, 1*time.Minute, "admin_read"), handler.listChapters)
+	adminRouter.Post("/chapters", manage, mw.RateLimit(30, 1*time.Minute, "admin_write"), handler.createChapter)
+	adminRouter.Put("/chapters/order", manage, mw.RateLimit(30, 1*time.Minute, "admin_write"), handler.reorderChapters)
+	adminRouter.Get("/chapters/:id", read, mw.RateLimit(60, 1*time.Minute, "admin_read"), handler.getChapter)
+	adminRouter.Put("/chapters/:id", write, mw.RateLimit(30, 1*time.Minute, "admin_write"), handler.updateChapter)
+	adminRouter.Delete("/chapters/:id", manage, mw.RateLimit(30, 1*time.Minute, "admin_write"), handler.deleteChapter)
+	adminRouter.Post("/chapters/:id/publish", publish, mw.RateLimit(30, 1*time.Minute, "admin_write"), handler.publishChapter)
+	adminRouter.Get("/chapters/:id/versions", read, mw.RateLimit(60, 1*time.Minute, "admin_read"), handler.listChapterVersions)
+	adminRouter.Post("/chapters/:id/rollback", publish, mw.RateLimit(30, 1*time.Minute, "admin_write"), handler.rollbackChapter)
+	adminRouter.Post("/chapters/:id/slides", write, mw.RateLimit(30, 1*time.Minute, "admin_write"), handler.createSlide)
+	adminRouter.Get("/slides/:id", read, mw.RateLimit(60, 1*time.Minute, "admin_read"), handler.getSlide)
+	adminRouter.Put("/slides/:id", write, mw.RateLimit(30, 1*time.Minute, "admin_write"), handler.updateSlide)
+	adminRouter.Delete("/slides/:id", write, mw.RateLimit(30, 1*time.Minute, "admin_write"), handler.deleteSlide)
+	adminRouter.Post("/slides/:id/choices", write, mw.RateLimit(30, 1*time.Minute, "admin_write"), handler.addChoice)
+	adminRouter.Put("/slides/:id/choices/:index", write, mw.RateLimit(30, 1*time.Minute, "admin_write"), handler.updateChoice)
+	adminRouter.Delete("/slides/:id/choices/:index", write, mw.RateLimit(30, 1*time.Minute, "admin_write"), handler.deleteChoice)
+	adminRouter.Get("/dictionary", read, mw.RateLimit(60, 1*time.Minute, "admin_read"), handler.listWords)
+	adminRouter.Post("/dictionary", write, mw.RateLimit(30, 1*time.Minute, "admin_write"), handler.createWord)
+	adminRouter.Put("/dictionary/:id", write, mw.RateLimit(30, 1*time.Minute, "admin_write"), handler.updateWord)
+	adminRouter.Delete("/dictionary/:id", write, mw.RateLimit(30, 1*time.Minute, "admin_write"), handler.deleteWord)
+}
+
+func (h *adminHandler) listStories(ctx *fiber.Ctx) error {
+	resp, apiErr := h.uc.ListStories(ctx.Context())
+	if apiErr != nil {
+		return response.Error(ctx, apiErr, nil)
+	}
+
+	return response.Success(ctx, fiber.StatusOK, i18n.AdminStoriesLoaded, resp)
+}
+
+func (h *adminHandler) createStory(ctx *fiber.Ctx) error {
+	req := new(dto.AdminStoryRequest)
+	if err := ctx.BodyParser(&req); err != nil {
+		return response.Error(ctx, response.ErrBadRequest(i18n.CommonInvalidBody), err)
+	}
+
+	if err := h.val.ValidateStruct(req); err != nil {
+		return response.Error(ctx, response.NewValidationError(err), err)
+	}
+
+	resp, apiErr := h.uc.CreateStory(ctx.Context(), req)
+	if apiErr != nil {
+		return response.Error(ctx, apiErr, nil)
+	}
+
+	return response.Success(ctx, fiber.StatusCreated, i18n.AdminStoryCreated, resp)
+}
+
+func (h *adminHandler) updateStory(ctx *fiber.Ctx) error {
+	storyID, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return response.Error(ctx, response.NewParamValidationError("id", "uuid"), err)
+	}
+
+	req := new(dto.AdminStoryRequest)
+	if err := ctx.BodyParser(&req); err != nil {
+		return response.Error(ctx, response.ErrBadRequest(i18n.CommonInvalidBody), err)
+	}
+
+	if err := h.val.ValidateStruct(req); err != nil {
+		return response.Error(ctx, response.NewValidationError(err), err)
+	}
+
+	resp, apiErr := h.uc.UpdateStory(ctx.Context(), storyID, req)
+	if apiErr != nil {
+		return response.Error(ctx, apiErr, nil)
+	}
+
+	return response.Success(ctx, fiber.StatusOK, i18n.AdminStoryUpdated, resp)
+}
+
+func (h *adminHandler) listChapters(ctx *fiber.Ctx) error {
+	resp, apiErr := h.uc.ListChapters(ctx.Context())
+	if apiErr != nil {
+		return response.Error(ctx, apiErr, nil)
+	}
+
+	return response.Success(ctx, fiber.StatusOK, i18n.AdminChaptersLoaded, resp)
+}
+
+func (h *adminHandler) getChapter(ctx *fiber.Ctx) error {
+	chapterID, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return response.Error(ctx, response.NewParamValidationError("id", "uuid"), err)
+	}
+
+	resp, apiErr := h.uc.GetChapter(ctx.Context(), chapterID)
+	if apiErr != nil {
+		return response.Error(ctx, apiErr, nil)
+	}
+
+	return response.Success(ctx, fiber.StatusOK, i18n.AdminChapterLoaded, resp)
+}
+
+func (h *adminHandler) createChapter(ctx *fiber.Ctx) error {
+	req := new(dto.AdminChapterRequest)
+	if err := ctx.BodyParser(&req); err != nil {
+		return response.Error(ctx, response.ErrBadRequest(i18n.CommonInvalidBody), err)
+	}
+
+	if err := h.val.ValidateStruct(req); err != nil {
+		return response.Error(ctx, response.NewValidationError(err), err)
+	}
+
+	resp, apiErr := h.uc.CreateChapter(ctx.Context(), req)
+	if apiErr != nil {
+		return response.Error(ctx, apiErr, nil)
+	}
+
+	return response.Success(ctx, fiber.StatusCreated, i18n.AdminChapterCreated, resp)
+}
+
+func (h *adminHandler) updateChapter(ctx *fiber.Ctx) error {
+	chapterID, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return response.Error(ctx, response.NewParamValidationError("id", "uuid"), err)
+	}
+
+	req := new(dto.AdminChapterRequest)
+	if err := ctx.BodyParser(&req); err != nil {
+		return response.Error(ctx, response.ErrBadRequest(i18n.CommonInvalidBody), err)
+	}
+
+	if err := h.val.ValidateStruct(req); err != nil {
+		return response.Error(ctx, response.NewValidationError(err), err)
+	}
+
+	resp, apiErr := h.uc.UpdateChapter(ctx.Context(), chapterID, req)
+	if apiErr != nil {
+		return response.Error(ctx, apiErr, nil)
+	}
+
+	return response.Success(ctx, fiber.StatusOK, i18n.AdminChapterUpdated, resp)
+}
+
+func (h *adminHandler) deleteChapter(ctx *fiber.Ctx) error {
+	chapterID, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return response.Error(ctx, response.NewParamValidationError("id", "uuid"), err)
+	}
+
+	if apiErr := h.uc.DeleteChapter(ctx.Context(), chapterID); apiErr != nil {
+		return response.Error(ctx, apiErr, nil)
+	}
+
+	return response.Success(ctx, fiber.StatusOK, i18n.AdminChapterDeleted, nil)
+}
+
+func (h *adminHandler) reorderChapters(ctx *fiber.Ctx) error {
+	req := new(dto.AdminChapterOrderRequest)
+	if err := ctx.BodyParser(&req); err != nil {
+		return response.Error(ctx, response.ErrBadRequest(i18n.CommonInvalidBody), err)
+	}
+
+	if err := h.val.ValidateStruct(req); err != nil {
+		return response.Error(ctx, response.NewValidationError(err), err)
+	}
+
+	resp, apiErr := h.uc.ReorderChapters(ctx.Context(), req)
+	if apiErr != nil {
+		return response.Error(ctx, apiErr, nil)
+	}
+
+	return response.Success(ctx, fiber.StatusOK, i18n.AdminChaptersReordered, resp)
+}
+
+func (h *adminHandler) publishChapter(ctx *fiber.Ctx) error {
+	chapterID, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return response.Error(ctx, response.NewParamValidationError("id", "uuid"), err)
+	}
+
+	resp, apiErr := h.uc.PublishChapter(ctx.Context(), chapterID)
+	if apiErr != nil {
+		return response.Error(ctx, apiErr, nil)
+	}
+
+	return response.Success(ctx, fiber.StatusCreated, i18n.AdminChapterPublished, resp)
+}
+
+func (h *adminHandler) listChapterVersions(ctx *fiber.Ctx) error {
+	chapterID, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return response.Error(ctx, response.NewParamValidationError("id", "uuid"), err)
+	}
+
+	resp, apiErr := h.uc.ListChapterVersions(ctx.Context(), chapterID)
+	if apiErr != nil {
+		return response.Error(ctx, apiErr, nil)
+	}
+
+	return response.Success(ctx, fiber.StatusOK, i18n.AdminVersionsLoaded, resp)
+}
+
+func (h *adminHandler) rollbackChapter(ctx *fiber.Ctx) error {
+	chapterID, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return response.Error(ctx, response.NewParamValidationError("id", "uuid"), err)
+	}
+
+	req := new(dto.AdminRollbackRequest)
+	if err := ctx.BodyParser(&req); err != nil {
+		return response.Error(ctx, response.ErrBadRequest(i18n.CommonInvalidBody), err)
+	}
+
+	if err := h.val.ValidateStruct(req); err != nil {
+		return response.Error(ctx, response.NewValidationError(err), err)
+	}
+
+	resp, apiErr := h.uc.RollbackChapter(ctx.Context(), chapterID, req)
+	if apiErr != nil {
+		return response.Error(ctx, apiErr, nil)
+	}
+
+	return response.Success(ctx, fiber.StatusOK, i18n.AdminChapterRolledBack, resp)
+}
+
+func (h *adminHandler) getSlide(ctx *fiber.Ctx) error {
+	slideID, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return response.Error(ctx, response.NewParamValidationError("id", "uuid"), err)
+	}
+
+	resp, apiErr := h.uc.GetSlide(ctx.Context(), slideID)
+	if apiErr != nil {
+		return response.Error(ctx, apiErr, nil)
+	}
+
+	return response.Success(ctx, fiber.StatusOK, i18n.AdminSlideLoaded, resp)
+}
+
+func (h *adminHandler) createSlide(ctx *fiber.Ctx) error {
+	chapterID, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return response.Error(ctx, response.NewParamValidationError("id", "uuid"), err)
+	}
+
+	req := new(dto.AdminSlideRequest)
+	if err := ctx.BodyParser(&req); err != nil {
+		return response.Error(ctx, response.ErrBadRequest(i18n.CommonInvalidBody), err)
+	}
+
+	if err := h.val.ValidateStruct(req); err != nil {
+		return response.Error(ctx, response.NewValidationError(err), err)
+	}
+
+	resp, apiErr := h.uc.CreateSlide(ctx.Context(), chapterID, req)
+	if apiErr != nil {
+		return response.Error(ctx, apiErr, nil)
+	}
+
+	return response.Success(ctx, fiber.StatusCreated, i18n.AdminSlideCreated, resp)
+}
+
+func (h *adminHandler) updateSlide(ctx *fiber.Ctx) error {
+	slideID, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return response.Error(ctx, response.NewParamValidationError("id", "uuid"), err)
+	}
+
+	req := new(dto.AdminSlideRequest)
+	if err := ctx.BodyParser(&req); err != nil {
+		return response.Error(ctx, response.ErrBadRequest(i18n.CommonInvalidBody), err)
+	}
+
+	if err := h.val.ValidateStruct(req); err != nil {
+		return response.Error(ctx, response.NewValidationError(err), err)
+	}
+
+	resp, apiErr := h.uc.UpdateSlide(ctx.Context(), slideID, req)
+	if apiErr != nil {
+		return response.Error(ctx, apiErr, nil)
+	}
+
+	return response.Success(ctx, fiber.StatusOK, i18n.AdminSlideUpdated, resp)
+}
+
+func (h *adminHandler) deleteSlide(ctx *fiber.Ctx) error {
+	slideID, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return response.Error(ctx, response.NewParamValidationError("id", "uuid"), err)
+	}
+
+	if apiErr := h.uc.DeleteSlide(ctx.Context(), slideID); apiErr != nil {
+		return response.Error(ctx, apiErr, nil)
+	}
+
+	return response.Success(ctx, fiber.StatusOK, i18n.AdminSlideDeleted, nil)
+}
+
+func (h *adminHandler) addChoice(ctx *fiber.Ctx) error {
+	slideID, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return response.Error(ctx, response.NewParamValidationError("id", "uuid"), err)
+	}
+
+	req := new(dto.AdminChoice)
+	if err := ctx.BodyParser(&req); err != nil {
+		return response.Error(ctx, response.ErrBadRequest(i18n.CommonInvalidBody), err)
+	}
+
+	if err := h.val.ValidateStruct(req); err != nil {
+		return response.Error(ctx, response.NewValidationError(err), err)
+	}
+
+	resp, apiErr := h.uc.AddChoice(ctx.Context(), slideID, req)
+	if apiErr != nil {
+		return response.Error(ctx, apiErr, nil)
+	}
+
+	return response.Success(ctx, fiber.StatusCreated, i18n.AdminChoiceSaved, resp)
+}
+
+func (h *adminHandler) updateChoice(ctx *fiber.Ctx) error {
+	slideID, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return response.Error(ctx, response.NewParamValidationError("id", "uuid"), err)
+	}
+
+	index, err := strconv.Atoi(ctx.Params("index"))
+	if err != nil {
+		return response.Error(ctx, response.NewParamValidationError("index", "number"), err)
+	}
+
+	req := new(dto.AdminChoice)
+	if err := ctx.BodyParser(&req); err != nil {
+		return response.Error(ctx, response.ErrBadRequest(i18n.CommonInvalidBody), err)
+	}
+
+	if err := h.val.ValidateStruct(req); err != nil {
+		return response.Error(ctx, response.NewValidationError(err), err)
+	}
+
+	resp, apiErr := h.uc.UpdateChoice(ctx.Context(), slideID, index, req)
+	if apiErr != nil {
+		return response.Error(ctx, apiErr, nil)
+	}
+
+	return response.Success(ctx, fiber.StatusOK, i18n.AdminChoiceSaved, resp)
+}
+
+func (h *adminHandler) deleteChoice(ctx *fiber.Ctx) error {
+	slideID, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return response.Error(ctx, response.NewParamValidationError("id", "uuid"), err)
+	}
+
+	index, err := strconv.Atoi(ctx.Params("index"))
+	if err != nil {
+		return response.Error(ctx, response.NewParamValidationError("index", "number"), err)
+	}
+
+	resp, apiErr := h.uc.DeleteChoice(ctx.Context(), slideID, index)
+	if apiErr != nil {
+		return response.Error(ctx, apiErr, nil)
+	}
+
+	return response.Success(ctx, fiber.StatusOK, i18n.AdminChoiceDeleted, resp)
+}
+
+func (h *adminHandler) listWords(ctx *fiber.Ctx) error {
+	allowedParams := map[string]bool{
+		"search": true,
+		"page":   true,
+		"limit":  true,
+	}
+
+	queryParams := ctx.Queries()
+	for k, v := range queryParams {
+		if !allowedParams[k] {
+			return response.Error(ctx, response.ErrBadRequest(i18n.CommonUnknownQueryParam, k), nil)
+		}
+
+		if v == "" {
+			return response.Error(ctx, response.ErrBadRequest(i18n.CommonEmptyQueryParam, k), nil)
+		}
+	}
+
+	req := new(dto.AdminWordListRequest)
+	if err := ctx.QueryParser(req); err != nil {
+		return response.Error(ctx, response.ErrBadRequest(i18n.CommonInvalidQuery), err)
+	}
+
+	resp, apiErr := h.uc.ListWords(ctx.Context(), req)
+	if apiErr != nil {
+		return response.Error(ctx, apiErr, nil)
+	}
+
+	return response.Success(ctx, fiber.StatusOK, i18n.AdminWordsLoaded, resp)
+}
+
+func (h *adminHandler) createWord(ctx *fiber.Ctx) error {
+	req := new(dto.AdminWordRequest)
+	if err := ctx.BodyParser(&req); err != nil {
+		return response.Error(ctx, response.ErrBadRequest(i18n.CommonInvalidBody), err)
+	}
+
+	if err := h.val.ValidateStruct(req); err != nil {
+		return response.Error(ctx, response.NewValidationError(err), err)
+	}
+
+	resp, apiErr := h.uc.CreateWord(ctx.Context(), req)
+	if apiErr != nil {
+		return response.Error(ctx, apiErr, nil)
+	}
+
+	return response.Success(ctx, fiber.StatusCreated, i18n.AdminWordCreated, resp)
+}
+
+func (h *adminHandler) updateWord(ctx *fiber.Ctx) error {
+	wordID, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return response.Error(ctx, response.NewParamValidationError("id", "uuid"), err)
+	}
+
+	req := new(dto.AdminWordRequest)
+	if err := ctx.BodyParser(&req); err != nil {
+		return response.Error(ctx, response.ErrBadRequest(i18n.CommonInvalidBody), err)
+	}
+
+	if err := h.val.ValidateStruct(req); err != nil {
+		return response.Error(ctx, response.NewValidationError(err), err)
+	}
+
+	resp, apiErr := h.uc.UpdateWord(ctx.Context(), wordID, req)
+	if apiErr != nil {
+		return response.Error(ctx, apiErr, nil)
+	}
+
+	return response.Success(ctx, fiber.StatusOK, i18n.AdminWordUpdated, resp)
+}
+
+func (h *adminHandler) deleteWord(ctx *fiber.Ctx) error {
+	wordID, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return response.Error(ctx, response.NewParamValidationError("id", "uuid"), err)
+	}
+
+	if apiErr := h.uc.DeleteWord(ctx.Context(), wordID); apiErr != nil {
+		return response.Error(ctx, apiErr, nil)
+	}
+
+	return response.Success(ctx, fiber.StatusOK, i18n.AdminWordDeleted, nil)
+}
@@ -0,0 +1,661 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/Ablebil/lathi-be/internal/domain/contract"
+	"github.com/Ablebil/lathi-be/internal/domain/entity"
+	"github.com/Ablebil/lathi-be/internal/infra/postgresql"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type adminRepository struct {
+	db *gorm.DB
+}
+
+func NewAdminRepository(db *gorm.DB) contract.AdminRepositoryItf {
+	return &adminRepository{
+		db: db,
+	}
+}
+
+// ListChapters lists every chapter, story by story.
+func (r *adminRepository) ListChapters(ctx context.Context) ([]entity.Chapter, error) {
+	var chapters []entity.Chapter
+	err := postgresql.Conn(ctx, r.db).
+		Joins("JOIN stories s ON s.id = chapters.story_id").
+		Order("s.order_index ASC, chapters.order_index ASC").
+		Find(&chapters).Error
+	if err != nil {
+		return nil, err
+	}
+	return chapters, nil
+}
+
+func (r *adminRepository) CountSlidesByChapter(ctx context.Context) (map[uuid.UUID]int, error) {
+	var rows []struct {
+		ChapterID uuid.UUID
+		Count     int
+	}
+	err := postgresql.Conn(ctx, r.db).Model(&entity.Slide{}).
+		Select("chapter_id, COUNT(*) AS count").
+		Group("chapter_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[uuid.UUID]int, len(rows))
+	for _, row := range rows {
+		counts[row.ChapterID] = row.Count
+	}
+	return counts, nil
+}
+
+// GetChapter loads the chapter with everything the story graph validator
+// needs: slides, their vocabularies and the chapter endings.
+func (r *adminRepository) GetChapter(ctx context.Context, id uuid.UUID) (*entity.Chapter, error) {
+	var chapter entity.Chapter
+	err := postgresql.Conn(ctx, r.db).
+		Preload("Slides", func(db *gorm.DB) *gorm.DB {
+			return db.Order("id ASC")
+		}).
+		Preload("Slides.Vocabularies", func(db *gorm.DB) *gorm.DB {
+			return db.Order("word_krama ASC")
+		}).
+		Preload("Endings", func(db *gorm.DB) *gorm.DB {
+			return db.Order("key ASC")
+		}).
+		Where("id = ?", id).
+		First(&chapter).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &chapter, nil
+}
+
+// LockChapter locks the chapter row until the surrounding transaction ends, so
+// edits to the same chapter are validated one after another.
+func (r *adminRepository) LockChapter(ctx context.Context, id uuid.UUID) (*entity.Chapter, error) {
+	var chapter entity.Chapter
+	err := postgresql.Conn(ctx, r.db).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", id).
+		First(&chapter).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &chapter, nil
+}
+
+// LockChapters locks every chapter of the story, used when their order changes.
+func (r *adminRepository) LockChapters(ctx context.Context, storyID uuid.UUID) ([]entity.Chapter, error) {
+	var chapters []entity.Chapter
+	err := postgresql.Conn(ctx, r.db).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("story_id = ?", storyID).
+		Order("order_index ASC").
+		Find(&chapters).Error
+	if err != nil {
+		return nil, err
+	}
+	return chapters, nil
+}
+
+func (r *adminRepository) CreateChapter(ctx context.Context, chapter *entity.Chapter) error {
+	return postgresql.Conn(ctx, r.db).Omit("Slides", "Endings").Create(chapter).Error
+}
+
+func (r *adminRepository) UpdateChapter(ctx context.Context, chapter *entity.Chapter) error {
+	return postgresql.Conn(ctx, r.db).Model(chapter).
+		Select("title", "description", "cover_image_url", "start_slide_id", "entry_points", "protagonist_name", "protagonist_sprites", "publish_at", "unlock").
+		Updates(chapter).Error
+}
+
+func (r *adminRepository) SetStartSlide(ctx context.Context, chapterID, slideID uuid.UUID) error {
+	return postgresql.Conn(ctx, r.db).Model(&entity.Chapter{}).
+		Where("id = ?", chapterID).
+		Update("start_slide_id", slideID).Error
+}
+
+// DeleteChapter removes the chapter with its slides, endings and everything
+// played in it, the endings players reached included.
+func (r *adminRepository) DeleteChapter(ctx context.Context, id uuid.UUID) error {
+	db := postgresql.Conn(ctx, r.db)
+	err := db.Where("ending_id IN (?)", db.Model(&entity.ChapterEnding{}).Select("id").Where("chapter_id = ?", id)).
+		Delete(&entity.UserEnding{}).Error
+	if err != nil {
+		return err
+	}
+	return db.Where("id = ?", id).Delete(&entity.Chapter{}).Error
+}
+
+// DropUnlockChapter removes the chapter from the chapters_completed rule of
+// every other chapter.
+func (r *adminRepository) DropUnlockChapter(ctx context.Context, id uuid.UUID) error {
+	return postgresql.Conn(ctx, r.db).Model(&entity.Chapter{}).
+		Where("unlock->'chapters_completed' @> jsonb_build_array(?::text)", id.String()).
+		Update("unlock", gorm.Expr("jsonb_set(unlock, '{chapters_completed}', (unlock->'chapters_completed') - ?::text)", id.String())).Error
+}
+
+// SyncCompletedChapters recounts every user's completed chapters after some
+// completions were dropped, reporting whether any count changed.
+func (r *adminRepository) SyncCompletedChapters(ctx context.Context) (bool, error) {
+	result := postgresql.Conn(ctx, r.db).Exec(`
+		UPDATE users SET chapters_completed = done.total
+		FROM (
+			SELECT u.id, COUNT(c.id) AS total
+			FROM users u
+			LEFT JOIN user_chapter_completions ucc ON ucc.user_id = u.id
+			LEFT JOIN chapters c ON c.id = ucc.chapter_id AND c.published_version_id IS NOT NULL
+			GROUP BY u.id
+		) done
+		WHERE users.id = done.id AND users.chapters_completed <> done.total`)
+	return result.RowsAffected > 0, result.Error
+}
+
+// SyncBonusScores sums every user's bonus again from the endings they still
+// have, reporting whether any bonus changed.
+func (r *adminRepository) SyncBonusScores(ctx context.Context) (bool, error) {
+	result := postgresql.Conn(ctx, r.db).Exec(`
+		UPDATE users SET bonus_score = earned.total
+		FROM (
+			SELECT u.id, COALESCE(SUM(ce.score_bonus), 0) AS total
+			FROM users u
+			LEFT JOIN user_endings ue ON ue.user_id = u.id
+			LEFT JOIN chapter_endings ce ON ce.id = ue.ending_id
+			GROUP BY u.id
+		) earned
+		WHERE users.id = earned.id AND users.bonus_score <> earned.total`)
+	return result.RowsAffected > 0, result.Error
+}
+
+// CountPublishedChaptersByStory counts the published chapters of each story,
+// the ones titles are earned over.
+func (r *adminRepository) CountPublishedChaptersByStory(ctx context.Context) (map[uuid.UUID]int, error) {
+	var rows []struct {
+		StoryID uuid.UUID
+		Total   int
+	}
+	err := postgresql.Conn(ctx, r.db).Model(&entity.Chapter{}).
+		Where("published_version_id IS NOT NULL").
+		Select("story_id, COUNT(*) AS total").
+		Group("story_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[uuid.UUID]int, len(rows))
+	for _, row := range rows {
+		counts[row.StoryID] = row.Total
+	}
+	return counts, nil
+}
+
+// ListStoryProgress lists the progress of every user in the story with the
+// number of its published chapters they completed.
+func (r *adminRepository) ListStoryProgress(ctx context.Context, storyID uuid.UUID) ([]entity.UserStoryProgress, map[uuid.UUID]int, error) {
+	var rows []struct {
+		UserID    uuid.UUID
+		StoryID   uuid.UUID
+		Title     entity.Title
+		Completed int
+	}
+	err := postgresql.Conn(ctx, r.db).Table("user_story_progresses AS usp").
+		Select("usp.user_id, usp.story_id, usp.title, COUNT(c.id) AS completed").
+		Joins("LEFT JOIN user_chapter_completions ucc ON ucc.user_id = usp.user_id").
+		Joins("LEFT JOIN chapters c ON c.id = ucc.chapter_id AND c.story_id = usp.story_id AND c.published_version_id IS NOT NULL").
+		Where("usp.story_id = ?", storyID).
+		Group("usp.user_id, usp.story_id, usp.title").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, nil, err
+	}
+
+	progress := make([]entity.UserStoryProgress, 0, len(rows))
+	completed := make(map[uuid.UUID]int, len(rows))
+	for _, row := range rows {
+		progress = append(progress, entity.UserStoryProgress{UserID: row.UserID, StoryID: row.StoryID, Title: row.Title})
+		completed[row.UserID] = row.Completed
+	}
+	return progress, completed, nil
+}
+
+// ListUserTitles lists every user with their title and completed chapter
+// count, nothing else.
+func (r *adminRepository) ListUserTitles(ctx context.Context) ([]entity.User, error) {
+	var users []entity.User
+	err := postgresql.Conn(ctx, r.db).
+		Select("id", "current_title", "chapters_completed").
+		Find(&users).Error
+	if err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// UpdateStoryTitles sets the title of each user in the story.
+func (r *adminRepository) UpdateStoryTitles(ctx context.Context, storyID uuid.UUID, titles map[uuid.UUID]entity.Title) error {
+	for title, userIDs := range groupByTitle(titles) {
+		err := postgresql.Conn(ctx, r.db).Model(&entity.UserStoryProgress{}).
+			Where("story_id = ? AND user_id IN ?", storyID, userIDs).
+			Update("title", title).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UpdateUserTitles sets the title each user earned over every story.
+func (r *adminRepository) UpdateUserTitles(ctx context.Context, titles map[uuid.UUID]entity.Title) error {
+	for title, userIDs := range groupByTitle(titles) {
+		err := postgresql.Conn(ctx, r.db).Model(&entity.User{}).
+			Where("id IN ?", userIDs).
+			Update("current_title", title).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func groupByTitle(titles map[uuid.UUID]entity.Title) map[entity.Title][]uuid.UUID {
+	groups := make(map[entity.Title][]uuid.UUID)
+	for userID, title := range titles {
+		groups[title] = append(groups[title], userID)
+	}
+	return groups
+}
+
+// CountChaptersByIDs counts how many of the chapters exist.
+func (r *adminRepository) CountChaptersByIDs(ctx context.Context, ids []uuid.UUID) (int64, error) {
+	var count int64
+	err := postgresql.Conn(ctx, r.db).Model(&entity.Chapter{}).
+		Where("id IN ?", ids).
+		Count(&count).Error
+	return count, err
+}
+
+// CountEndingsByIDs counts how many of the endings exist outside the chapter.
+func (r *adminRepository) CountEndingsByIDs(ctx context.Context, ids []uuid.UUID, exceptChapterID uuid.UUID) (int64, error) {
+	var count int64
+	err := postgresql.Conn(ctx, r.db).Model(&entity.ChapterEnding{}).
+		Where("id IN ? AND chapter_id <> ?", ids, exceptChapterID).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *adminRepository) SetChapterOrder(ctx context.Context, order map[uuid.UUID]int) error {
+	db := postgresql.Conn(ctx, r.db)
+	for id, index := range order {
+		if err := db.Model(&entity.Chapter{}).Where("id = ?", id).Update("order_index", index).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *adminRepository) ListStories(ctx context.Context) ([]entity.Story, error) {
+	var stories []entity.Story
+	if err := postgresql.Conn(ctx, r.db).Order("order_index ASC").Find(&stories).Error; err != nil {
+		return nil, err
+	}
+	return stories, nil
+}
+
+// CountChaptersByStory counts the chapters of each story, drafts included.
+func (r *adminRepository) CountChaptersByStory(ctx context.Context) (map[uuid.UUID]int, error) {
+	var rows []struct {
+		StoryID uuid.UUID
+		Total   int
+	}
+	err := postgresql.Conn(ctx, r.db).Model(&entity.Chapter{}).
+		Select("story_id, COUNT(*) AS total").
+		Group("story_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[uuid.UUID]int, len(rows))
+	for _, row := range rows {
+		counts[row.StoryID] = row.Total
+	}
+	return counts, nil
+}
+
+func (r *adminRepository) GetStoryBySlug(ctx context.Context, slug string) (*entity.Story, error) {
+	var story entity.Story
+	err := postgresql.Conn(ctx, r.db).Where("slug = ?", slug).First(&story).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &story, nil
+}
+
+// GetChapterStory loads the story the chapter belongs to, nil when the chapter
+// doesn't exist.
+func (r *adminRepository) GetChapterStory(ctx context.Context, chapterID uuid.UUID) (*entity.Story, error) {
+	var story entity.Story
+	err := postgresql.Conn(ctx, r.db).
+		Joins("JOIN chapters c ON c.story_id = stories.id").
+		Where("c.id = ?", chapterID).
+		First(&story).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &story, nil
+}
+
+// LockStory locks the story row until the surrounding transaction ends, so
+// chapters are added to and ordered in the story one change at a time.
+func (r *adminRepository) LockStory(ctx context.Context, id uuid.UUID) (*entity.Story, error) {
+	var story entity.Story
+	err := postgresql.Conn(ctx, r.db).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", id).
+		First(&story).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &story, nil
+}
+
+func (r *adminRepository) CreateStory(ctx context.Context, story *entity.Story) error {
+	return postgresql.Conn(ctx, r.db).Omit("Chapters").Create(story).Error
+}
+
+func (r *adminRepository) UpdateStory(ctx context.Context, story *entity.Story) error {
+	return postgresql.Conn(ctx, r.db).Model(story).
+		Select("slug", "title", "description", "cover_image_url", "order_index", "badge_code").
+		Updates(story).Error
+}
+
+func (r *adminRepository) BadgeExists(ctx context.Context, code string) (bool, error) {
+	var count int64
+	err := postgresql.Conn(ctx, r.db).Model(&entity.Badge{}).
+		Where("code = ?", code).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *adminRepository) GetSlide(ctx context.Context, id uuid.UUID) (*entity.Slide, error) {
+	var slide entity.Slide
+	err := postgresql.Conn(ctx, r.db).
+		Preload("Vocabularies", func(db *gorm.DB) *gorm.DB {
+			return db.Order("word_krama ASC")
+		}).
+		Where("id = ?", id).
+		First(&slide).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &slide, nil
+}
+
+func (r *adminRepository) CreateSlide(ctx context.Context, slide *entity.Slide) error {
+	return postgresql.Conn(ctx, r.db).Omit("Vocabularies", "Ending").Create(slide).Error
+}
+
+// UpdateSlide saves everything but the choices, which are edited one by one.
+func (r *adminRepository) UpdateSlide(ctx context.Context, slide *entity.Slide) error {
+	return postgresql.Conn(ctx, r.db).Model(slide).
+		Select("key", "type", "is_checkpoint", "background_image_url", "characters", "speaker_name", "content", "subtitles", "next_slide_id", "routes", "ending_id", "quiz", "translation").
+		Updates(slide).Error
+}
+
+func (r *adminRepository) UpdateSlideChoices(ctx context.Context, slide *entity.Slide) error {
+	return postgresql.Conn(ctx, r.db).Model(slide).Select("choices").Updates(slide).Error
+}
+
+func (r *adminRepository) ReplaceSlideVocab(ctx context.Context, slide *entity.Slide, vocabIDs []uuid.UUID) error {
+	vocabs := make([]entity.Dictionary, 0, len(vocabIDs))
+	for _, id := range vocabIDs {
+		vocabs = append(vocabs, entity.Dictionary{ID: id})
+	}
+	return postgresql.Conn(ctx, r.db).Model(slide).Association("Vocabularies").Replace(vocabs)
+}
+
+func (r *adminRepository) DeleteSlide(ctx context.Context, id uuid.UUID) error {
+	return postgresql.Conn(ctx, r.db).Where("id = ?", id).Delete(&entity.Slide{}).Error
+}
+
+// ListChapterVersions lists the versions of the chapter newest first, without
+// their content.
+func (r *adminRepository) ListChapterVersions(ctx context.Context, chapterID uuid.UUID) ([]entity.ChapterVersion, error) {
+	var versions []entity.ChapterVersion
+	err := postgresql.Conn(ctx, r.db).
+		Omit("content").
+		Where("chapter_id = ?", chapterID).
+		Order("number DESC").
+		Find(&versions).Error
+	if err != nil {
+		return nil, err
+	}
+	return versions, nil
+}
+
+func (r *adminRepository) GetChapterVersion(ctx context.Context, id uuid.UUID) (*entity.ChapterVersion, error) {
+	var version entity.ChapterVersion
+	err := postgresql.Conn(ctx, r.db).Where("id = ?", id).First(&version).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &version, nil
+}
+
+func (r *adminRepository) FindChapterVersion(ctx context.Context, chapterID uuid.UUID, number int) (*entity.ChapterVersion, error) {
+	var version entity.ChapterVersion
+	err := postgresql.Conn(ctx, r.db).
+		Omit("content").
+		Where("chapter_id = ? AND number = ?", chapterID, number).
+		First(&version).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &version, nil
+}
+
+// PublishChapter snapshots the chapter as its next version and makes it the
+// one new runs start on. The caller holds the chapter row lock, so publishes
+// of the same chapter are numbered one after another.
+func (r *adminRepository) PublishChapter(ctx context.Context, chapter *entity.Chapter) (*entity.ChapterVersion, error) {
+	db := postgresql.Conn(ctx, r.db)
+
+	var last int
+	err := db.Model(&entity.ChapterVersion{}).
+		Where("chapter_id = ?", chapter.ID).
+		Select("COALESCE(MAX(number), 0)").
+		Scan(&last).Error
+	if err != nil {
+		return nil, err
+	}
+
+	version, err := entity.NewChapterVersion(chapter, last+1)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Omit(clause.Associations).Create(version).Error; err != nil {
+		return nil, err
+	}
+	if err := r.SetPublishedVersion(ctx, chapter.ID, version.ID); err != nil {
+		return nil, err
+	}
+	return version, nil
+}
+
+// SetPublishedVersion makes the version the one new runs of the chapter start on.
+func (r *adminRepository) SetPublishedVersion(ctx context.Context, chapterID, versionID uuid.UUID) error {
+	return postgresql.Conn(ctx, r.db).Model(&entity.Chapter{}).
+		Where("id = ?", chapterID).
+		Update("published_version_id", versionID).Error
+}
+
+func (r *adminRepository) ListWords(ctx context.Context, search string, limit, offset int) ([]entity.Dictionary, int64, error) {
+	var words []entity.Dictionary
+	var total int64
+	query := postgresql.Conn(ctx, r.db).Model(&entity.Dictionary{})
+
+	if search != "" {
+		searchLower := "%" + strings.ToLower(search) + "%"
+		query = query.Where(
+			"LOWER(word_krama) LIKE ? OR LOWER(word_ngoko) LIKE ? OR LOWER(word_indo) LIKE ?",
+			searchLower, searchLower, searchLower,
+		)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.
+		Order("word_krama ASC").
+		Limit(limit).
+		Offset(offset).
+		Find(&words).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return words, total, nil
+}
+
+func (r *adminRepository) GetWordsByIDs(ctx context.Context, ids []uuid.UUID) ([]entity.Dictionary, error) {
+	var words []entity.Dictionary
+	if len(ids) == 0 {
+		return words, nil
+	}
+
+	err := postgresql.Conn(ctx, r.db).Where("id IN ?", ids).Find(&words).Error
+	if err != nil {
+		return nil, err
+	}
+	return words, nil
+}
+
+func (r *adminRepository) GetWord(ctx context.Context, id uuid.UUID) (*entity.Dictionary, error) {
+	var word entity.Dictionary
+	err := postgresql.Conn(ctx, r.db).Where("id = ?", id).First(&word).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &word, nil
+}
+
+// FindWordByKrama matches the krama word case insensitively, the same way
+// slide markers are matched.
+func (r *adminRepository) FindWordByKrama(ctx context.Context, krama string) (*entity.Dictionary, error) {
+	var word entity.Dictionary
+	err := postgresql.Conn(ctx, r.db).
+		Where("LOWER(word_krama) = ?", strings.ToLower(krama)).
+		First(&word).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &word, nil
+}
+
+func (r *adminRepository) CreateWord(ctx context.Context, word *entity.Dictionary) error {
+	return postgresql.Conn(ctx, r.db).Create(word).Error
+}
+
+func (r *adminRepository) UpdateWord(ctx context.Context, word *entity.Dictionary) error {
+	return postgresql.Conn(ctx, r.db).Model(word).
+		Select("word_krama", "word_ngoko", "word_indo").
+		Updates(word).Error
+}
+
+func (r *adminRepository) DeleteWord(ctx context.Context, id uuid.UUID) error {
+	return postgresql.Conn(ctx, r.db).Where("id = ?", id).Delete(&entity.Dictionary{}).Error
+}
+
+// CountWordUsage counts the slides linking the word, the quizzes asking or
+// offering it and the published chapter versions holding it.
+func (r *adminRepository) CountWordUsage(ctx context.Context, id uuid.UUID) (int64, error) {
+	db := postgresql.Conn(ctx, r.db)
+
+	var links int64
+	if err := db.Table("slide_vocabularies").Where("dictionary_id = ?", id).Count(&links).Error; err != nil {
+		return 0, err
+	}
+
+	var quizzes int64
+	err := db.Model(&entity.Slide{}).
+		Where("type = ?", entity.SlideQuiz).
+		Where("quiz->>'word_id' = ? OR quiz->'distractor_ids' @> to_jsonb(?::text)", id.String(), id.String()).
+		Count(&quizzes).Error
+	if err != nil {
+		return 0, err
+	}
+
+	// ids are unique enough to be looked for in the snapshot text
+	var versions int64
+	err = db.Model(&entity.ChapterVersion{}).
+		Where("strpos(content::text, ?) > 0", id.String()).
+		Count(&versions).Error
+	if err != nil {
+		return 0, err
+	}
+
+	return links + quizzes + versions, nil
+}
+
+// ChapterIDsByWord lists the chapters with slides linking the word.
+func (r *adminRepository) ChapterIDsByWord(ctx context.Context, id uuid.UUID) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := postgresql.Conn(ctx, r.db).Table("slides AS s").
+		Joins("JOIN slide_vocabularies sv ON sv.slide_id = s.id").
+		Where("sv.dictionary_id = ?", id).
+		Distinct().
+		Pluck("s.chapter_id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
@@ -0,0 +1,136 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+
+	"github.com/Ablebil/lathi-be/internal/app/story/graph"
+	"github.com/Ablebil/lathi-be/internal/config"
+	"github.com/Ablebil/lathi-be/internal/domain/contract"
+	"github.com/Ablebil/lathi-be/internal/domain/dto"
+	"github.com/Ablebil/lathi-be/internal/domain/entity"
+	"github.com/Ablebil/lathi-be/internal/domain/types"
+	"github.com/Ablebil/lathi-be/internal/infra/postgresql"
+	"github.com/Ablebil/lathi-be/pkg/i18n"
+	"github.com/Ablebil/lathi-be/pkg/response"
+	"github.com/google/uuid"
+)
+
+// returned inside an edit transaction to roll back an edit the usecase refused
+var errRejected = errors.New("edit rejected")
+
+type adminUsecase struct {
+	adminRepo contract.AdminRepositoryItf
+	lbRepo    contract.LeaderboardRepositoryItf
+	tx        postgresql.TransactorItf
+	env       *config.Env
+}
+
+func NewAdminUsecase(adminRepo contract.AdminRepositoryItf, lbRepo contract.LeaderboardRepositoryItf, tx postgresql.TransactorItf, env *config.Env) contract.AdminUsecaseItf {
+	return &adminUsecase{
+		adminRepo: adminRepo,
+		lbRepo:    lbRepo,
+		tx:        tx,
+		env:       env,
+	}
+}
+
+// edit runs fn in a transaction. fn refuses the edit by returning an APIError,
+// which rolls back everything it wrote.
+func (uc *adminUsecase) edit(ctx context.Context, fn func(ctx context.Context) (*response.APIError, error)) *response.APIError {
+	var apiErr *response.APIError
+	err := uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		if apiErr, err = fn(ctx); err != nil {
+			return err
+		}
+		if apiErr != nil {
+			return errRejected
+		}
+		return nil
+	})
+	if errors.Is(err, errRejected) {
+		return apiErr
+	}
+	if err != nil {
+		slog.Error("failed to save story edit", "error", err)
+		return response.ErrInternal(i18n.CommonTryAgain)
+	}
+	return nil
+}
+
+// lockChapter locks the chapter for the rest of the edit and loads it with its
+// slides and endings.
+func (uc *adminUsecase) lockChapter(ctx context.Context, chapterID uuid.UUID) (*entity.Chapter, *response.APIError, error) {
+	locked, err := uc.adminRepo.LockChapter(ctx, chapterID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if locked == nil {
+		return nil, response.ErrNotFound(i18n.StoryChapterNotFound), nil
+	}
+
+	chapter, err := uc.adminRepo.GetChapter(ctx, chapterID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return chapter, nil, nil
+}
+
+// checkChapter reloads the chapter inside the edit and runs the story graph
+// validator on it. Errors refuse the edit, the warnings are returned.
+func (uc *adminUsecase) checkChapter(ctx context.Context, chapterID uuid.UUID) (*entity.Chapter, []dto.StoryIssueResponse, *response.APIError, error) {
+	chapter, err := uc.adminRepo.GetChapter(ctx, chapterID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	g, err := graph.FromChapter(chapter)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	issues, err := graph.Check(g)
+	var invalid *graph.ValidationError
+	if errors.As(err, &invalid) {
+		return nil, nil, response.ErrUnprocessable(i18n.AdminInvalidStory, toIssueResponses(issues)), nil
+	}
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return chapter, toIssueResponses(issues), nil, nil
+}
+
+func toIssueResponses(issues []graph.Issue) []dto.StoryIssueResponse {
+	res := make([]dto.StoryIssueResponse, 0, len(issues))
+	for _, i := range issues {
+		res = append(res, dto.StoryIssueResponse{
+			Severity: string(i.Severity),
+			Slide:    i.Slide,
+			Code:     i.Code,
+			Message:  i.Message,
+		})
+	}
+	return res
+}
+
+func findSlide(chapter *entity.Chapter, slideID uuid.UUID) *entity.Slide {
+	for i := range chapter.Slides {
+		if chapter.Slides[i].ID == slideID {
+			return &chapter.Slides[i]
+		}
+	}
+	return nil
+}
+
+// toJSONB encodes v for a jsonb column, nil slices and maps are stored as empty.
+func toJSONB(v any, empty string) types.JSONB {
+	b, err := json.Marshal(v)
+	if err != nil || string(b) == "null" {
+		return types.JSONB(empty)
+	}
+	return types.JSONB(b)
+}
@@ -0,0 +1,518 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+
+	"github.com/Ablebil/lathi-be/internal/app/story/graph"
+	"github.com/Ablebil/lathi-be/internal/domain/dto"
+	"github.com/Ablebil/lathi-be/internal/domain/entity"
+	"github.com/Ablebil/lathi-be/internal/domain/types"
+	"github.com/Ablebil/lathi-be/pkg/i18n"
+	"github.com/Ablebil/lathi-be/pkg/response"
+	"github.com/google/uuid"
+)
+
+func (uc *adminUsecase) ListChapters(ctx context.Context) ([]dto.AdminChapterResponse, *response.APIError) {
+	chapters, err := uc.adminRepo.ListChapters(ctx)
+	if err != nil {
+		slog.Error("failed to get chapters", "error", err)
+		return nil, response.ErrInternal(i18n.CommonTryAgain)
+	}
+
+	resp, err := uc.toChapterList(ctx, chapters)
+	if err != nil {
+		slog.Error("failed to build chapter list", "error", err)
+		return nil, response.ErrInternal(i18n.CommonTryAgain)
+	}
+	return resp, nil
+}
+
+func (uc *adminUsecase) GetChapter(ctx context.Context, chapterID uuid.UUID) (*dto.AdminChapterDetailResponse, *response.APIError) {
+	chapter, err := uc.adminRepo.GetChapter(ctx, chapterID)
+	if err != nil {
+		slog.Error("failed to get chapter", "error", err)
+		return nil, response.ErrInternal(i18n.CommonTryAgain)
+	}
+	if chapter == nil {
+		return nil, response.ErrNotFound(i18n.StoryChapterNotFound)
+	}
+
+	resp, err := toChapterDetail(chapter)
+	if err != nil {
+		slog.Error("failed to build chapter detail", "error", err, "chapter_id", chapter.ID)
+		return nil, response.ErrInternal(i18n.CommonTryAgain)
+	}
+	return resp, nil
+}
+
+// CreateChapter adds an empty chapter after the last one of its story, so no
+// player's progress changes meaning.
+func (uc *adminUsecase) CreateChapter(ctx context.Context, req *dto.AdminChapterRequest) (*dto.AdminChapterResponse, *response.APIError) {
+	if apiErr := checkChapterRequest(req); apiErr != nil {
+		return nil, apiErr
+	}
+
+	// entry points need slides, they are set once the chapter has some
+	chapter := &entity.Chapter{EntryPoints: types.JSONB("{}")}
+	applyChapter(chapter, req)
+
+	apiErr := uc.edit(ctx, func(ctx context.Context) (*response.APIError, error) {
+		story, err := uc.lockStory(ctx, req.StoryID)
+		if err != nil {
+			return nil, err
+		}
+		if story == nil {
+			return response.NewFieldValidationError("story_id", "exists"), nil
+		}
+		chapter.StoryID = story.ID
+
+		chapters, err := uc.adminRepo.LockChapters(ctx, story.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		if apiErr, err := uc.checkUnlock(ctx, uuid.Nil, req.Unlock); err != nil || apiErr != nil {
+			return apiErr, err
+		}
+
+		chapter.OrderIndex = 1
+		if len(chapters) > 0 {
+			chapter.OrderIndex = chapters[len(chapters)-1].OrderIndex + 1
+		}
+		return nil, uc.adminRepo.CreateChapter(ctx, chapter)
+	})
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	resp, err := toChapterResponse(chapter, 0)
+	if err != nil {
+		slog.Error("failed to build chapter", "error", err, "chapter_id", chapter.ID)
+		return nil, response.ErrInternal(i18n.CommonTryAgain)
+	}
+	return &resp, nil
+}
+
+func (uc *adminUsecase) UpdateChapter(ctx context.Context, chapterID uuid.UUID, req *dto.AdminChapterRequest) (*dto.AdminChapterResponse, *response.APIError) {
+	if apiErr := checkChapterRequest(req); apiErr != nil {
+		return nil, apiErr
+	}
+
+	var resp dto.AdminChapterResponse
+	apiErr := uc.edit(ctx, func(ctx context.Context) (*response.APIError, error) {
+		chapter, apiErr, err := uc.lockChapter(ctx, chapterID)
+		if err != nil || apiErr != nil {
+			return apiErr, err
+		}
+
+		if req.StoryID != nil && *req.StoryID != chapter.StoryID {
+			return response.NewFieldValidationError("story_id", "immutable"), nil
+		}
+		if req.StartSlideID != nil && findSlide(chapter, *req.StartSlideID) == nil {
+			return response.NewFieldValidationError("start_slide_id", "exists"), nil
+		}
+		for _, id := range req.EntryPoints {
+			if findSlide(chapter, id) == nil {
+				return response.NewFieldValidationError("entry_points", "exists"), nil
+			}
+		}
+		if apiErr, err := uc.checkUnlock(ctx, chapterID, req.Unlock); err != nil || apiErr != nil {
+			return apiErr, err
+		}
+
+		applyChapter(chapter, req)
+		chapter.StartSlideID = req.StartSlideID
+		chapter.EntryPoints = toJSONB(req.EntryPoints, "{}")
+		if err := uc.adminRepo.UpdateChapter(ctx, chapter); err != nil {
+			return nil, err
+		}
+
+		chapter, issues, apiErr, err := uc.checkChapter(ctx, chapterID)
+		if err != nil || apiErr != nil {
+			return apiErr, err
+		}
+
+		if resp, err = toChapterResponse(chapter, len(chapter.Slides)); err != nil {
+			return nil, err
+		}
+		resp.Issues = issues
+		return nil, nil
+	})
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	return &resp, nil
+}
+
+// DeleteChapter removes the chapter with everything played in it and closes
+// the gap it leaves in the order of its story. Players who completed it lose
+// the completion and the endings they reached in it, so their chapter counts,
+// bonuses, titles and scores follow. Badges already earned are kept.
+func (uc *adminUsecase) DeleteChapter(ctx context.Context, chapterID uuid.UUID) *response.APIError {
+	var progressChanged bool
+	apiErr := uc.edit(ctx, func(ctx context.Context) (*response.APIError, error) {
+		story, err := uc.adminRepo.GetChapterStory(ctx, chapterID)
+		if err != nil {
+			return nil, err
+		}
+		if story == nil {
+			return response.ErrNotFound(i18n.StoryChapterNotFound), nil
+		}
+		if _, err := uc.adminRepo.LockStory(ctx, story.ID); err != nil {
+			return nil, err
+		}
+
+		chapters, err := uc.adminRepo.LockChapters(ctx, story.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		remaining := make([]entity.Chapter, 0, len(chapters))
+		for _, ch := range chapters {
+			if ch.ID != chapterID {
+				remaining = append(remaining, ch)
+			}
+		}
+		if len(remaining) == len(chapters) {
+			return response.ErrNotFound(i18n.StoryChapterNotFound), nil
+		}
+
+		if err := uc.adminRepo.DeleteChapter(ctx, chapterID); err != nil {
+			return nil, err
+		}
+		if err := uc.adminRepo.DropUnlockChapter(ctx, chapterID); err != nil {
+			return nil, err
+		}
+
+		if err := uc.applyOrder(ctx, remaining); err != nil {
+			return nil, err
+		}
+
+		if progressChanged, err = uc.adminRepo.SyncCompletedChapters(ctx); err != nil {
+			return nil, err
+		}
+		bonusChanged, err := uc.adminRepo.SyncBonusScores(ctx)
+		if err != nil {
+			return nil, err
+		}
+		progressChanged = progressChanged || bonusChanged
+
+		return nil, uc.syncTitles(ctx, story.ID)
+	})
+	if apiErr != nil {
+		return apiErr
+	}
+
+	if progressChanged {
+		uc.rebuildLeaderboard(ctx)
+	}
+	return nil
+}
+
+// ReorderChapters sets the play order of the chapters of one story.
+func (uc *adminUsecase) ReorderChapters(ctx context.Context, req *dto.AdminChapterOrderRequest) ([]dto.AdminChapterResponse, *response.APIError) {
+	var ordered []entity.Chapter
+	apiErr := uc.edit(ctx, func(ctx context.Context) (*response.APIError, error) {
+		story, err := uc.lockStory(ctx, req.StoryID)
+		if err != nil {
+			return nil, err
+		}
+		if story == nil {
+			return response.NewFieldValidationError("story_id", "exists"), nil
+		}
+
+		chapters, err := uc.adminRepo.LockChapters(ctx, story.ID)
+		if err != nil {
+			return nil, err
+		}
+		if len(req.ChapterIDs) != len(chapters) {
+			return response.ErrBadRequest(i18n.AdminInvalidOrder), nil
+		}
+
+		byID := make(map[uuid.UUID]entity.Chapter, len(chapters))
+		for _, ch := range chapters {
+			byID[ch.ID] = ch
+		}
+
+		ordered = make([]entity.Chapter, 0, len(chapters))
+		for _, id := range req.ChapterIDs {
+			ch, ok := byID[id]
+			if !ok {
+				return response.ErrBadRequest(i18n.AdminInvalidOrder), nil
+			}
+			delete(byID, id)
+			ordered = append(ordered, ch)
+		}
+
+		return nil, uc.applyOrder(ctx, ordered)
+	})
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	resp, err := uc.toChapterList(ctx, ordered)
+	if err != nil {
+		slog.Error("failed to build chapter list", "error", err)
+		return nil, response.ErrInternal(i18n.CommonTryAgain)
+	}
+	return resp, nil
+}
+
+// applyOrder numbers the chapters 1, 2, 3... in the given order, updating
+// them in place. Completions are kept per chapter, so progress follows the
+// chapters wherever they move.
+func (uc *adminUsecase) applyOrder(ctx context.Context, chapters []entity.Chapter) error {
+	order := make(map[uuid.UUID]int)
+	for i := range chapters {
+		if chapters[i].OrderIndex != i+1 {
+			order[chapters[i].ID] = i + 1
+			chapters[i].OrderIndex = i + 1
+		}
+	}
+	return uc.adminRepo.SetChapterOrder(ctx, order)
+}
+
+// syncTitles gives every player the titles their remaining completions earn
+// in the story and over every story, now that the chapter totals changed.
+func (uc *adminUsecase) syncTitles(ctx context.Context, storyID uuid.UUID) error {
+	storyChapters, err := uc.adminRepo.CountPublishedChaptersByStory(ctx)
+	if err != nil {
+		return err
+	}
+
+	totalChapters := 0
+	for _, n := range storyChapters {
+		totalChapters += n
+	}
+
+	progress, completed, err := uc.adminRepo.ListStoryProgress(ctx, storyID)
+	if err != nil {
+		return err
+	}
+
+	storyTitles := make(map[uuid.UUID]entity.Title)
+	for _, p := range progress {
+		if title := entity.TitleFor(completed[p.UserID], storyChapters[storyID]); title != p.Title {
+			storyTitles[p.UserID] = title
+		}
+	}
+	if err := uc.adminRepo.UpdateStoryTitles(ctx, storyID, storyTitles); err != nil {
+		return err
+	}
+
+	users, err := uc.adminRepo.ListUserTitles(ctx)
+	if err != nil {
+		return err
+	}
+
+	userTitles := make(map[uuid.UUID]entity.Title)
+	for _, u := range users {
+		if title := entity.TitleFor(u.ChaptersCompleted, totalChapters); title != u.CurrentTitle {
+			userTitles[u.ID] = title
+		}
+	}
+	return uc.adminRepo.UpdateUserTitles(ctx, userTitles)
+}
+
+// rebuildLeaderboard refreshes the scores after progress was rewritten. The
+// database is already right, so a failure is only logged.
+func (uc *adminUsecase) rebuildLeaderboard(ctx context.Context) {
+	if err := uc.lbRepo.RebuildLeaderboard(ctx); err != nil {
+		slog.Error("failed to rebuild leaderboard after chapter delete", "error", err)
+	}
+}
+
+// lockStory locks the story chapters are added to or ordered in, the main
+// story when storyID is nil. The story is nil when it doesn't exist.
+func (uc *adminUsecase) lockStory(ctx context.Context, storyID *uuid.UUID) (*entity.Story, error) {
+	if storyID == nil {
+		main, err := uc.adminRepo.GetStoryBySlug(ctx, entity.MainStorySlug)
+		if err != nil || main == nil {
+			return nil, err
+		}
+		storyID = &main.ID
+	}
+	return uc.adminRepo.LockStory(ctx, *storyID)
+}
+
+func checkChapterRequest(req *dto.AdminChapterRequest) *response.APIError {
+	for key, img := range req.ProtagonistSprites {
+		if strings.TrimSpace(key) == "" || strings.TrimSpace(img) == "" {
+			return response.NewFieldValidationError("protagonist_sprites", "required")
+		}
+	}
+	for name := range req.EntryPoints {
+		if strings.TrimSpace(name) == "" {
+			return response.NewFieldValidationError("entry_points", "required")
+		}
+	}
+	if req.Unlock != nil && req.Unlock.MinVocab < 0 {
+		return response.NewFieldValidationError("unlock", "min")
+	}
+	return nil
+}
+
+// checkUnlock makes sure the unlock rules only refer to other chapters and
+// their endings. chapterID is nil for a chapter that doesn't exist yet.
+func (uc *adminUsecase) checkUnlock(ctx context.Context, chapterID uuid.UUID, rules *entity.UnlockRules) (*response.APIError, error) {
+	if rules == nil {
+		return nil, nil
+	}
+
+	chapters := uniqueIDs(rules.ChaptersCompleted)
+	for _, id := range chapters {
+		if id == chapterID {
+			return response.NewFieldValidationError("unlock", "exists"), nil
+		}
+	}
+	if len(chapters) > 0 {
+		count, err := uc.adminRepo.CountChaptersByIDs(ctx, chapters)
+		if err != nil {
+			return nil, err
+		}
+		if count != int64(len(chapters)) {
+			return response.NewFieldValidationError("unlock", "exists"), nil
+		}
+	}
+
+	endings := uniqueIDs(rules.EndingsReached)
+	if len(endings) > 0 {
+		count, err := uc.adminRepo.CountEndingsByIDs(ctx, endings, chapterID)
+		if err != nil {
+			return nil, err
+		}
+		if count != int64(len(endings)) {
+			return response.NewFieldValidationError("unlock", "exists"), nil
+		}
+	}
+	return nil, nil
+}
+
+func uniqueIDs(ids []uuid.UUID) []uuid.UUID {
+	seen := make(map[uuid.UUID]bool, len(ids))
+	res := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			res = append(res, id)
+		}
+	}
+	return res
+}
+
+func applyChapter(chapter *entity.Chapter, req *dto.AdminChapterRequest) {
+	chapter.Title = req.Title
+	chapter.Description = req.Description
+	chapter.CoverImageURL = req.CoverImageURL
+	chapter.ProtagonistName = req.ProtagonistName
+	if chapter.ProtagonistName == "" {
+		chapter.ProtagonistName = entity.DefaultProtagonist
+	}
+	chapter.ProtagonistSprites = toJSONB(req.ProtagonistSprites, "{}")
+	chapter.PublishAt = nil
+	if req.PublishAt != nil {
+		at := req.PublishAt.UTC()
+		chapter.PublishAt = &at
+	}
+	chapter.Unlock = toJSONB(req.Unlock, "{}")
+}
+
+func (uc *adminUsecase) toChapterList(ctx context.Context, chapters []entity.Chapter) ([]dto.AdminChapterResponse, error) {
+	counts, err := uc.adminRepo.CountSlidesByChapter(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]dto.AdminChapterResponse, 0, len(chapters))
+	for i := range chapters {
+		item, err := toChapterResponse(&chapters[i], counts[chapters[i].ID])
+		if err != nil {
+			return nil, err
+		}
+		resp = append(resp, item)
+	}
+	return resp, nil
+}
+
+func toChapterResponse(chapter *entity.Chapter, slideCount int) (dto.AdminChapterResponse, error) {
+	entries, err := chapter.GetEntryPoints()
+	if err != nil {
+		return dto.AdminChapterResponse{}, err
+	}
+
+	sprites := map[string]string{}
+	if len(chapter.ProtagonistSprites) > 0 {
+		if err := json.Unmarshal(chapter.ProtagonistSprites, &sprites); err != nil {
+			return dto.AdminChapterResponse{}, err
+		}
+	}
+
+	unlock, err := chapter.GetUnlockRules()
+	if err != nil {
+		return dto.AdminChapterResponse{}, err
+	}
+
+	return dto.AdminChapterResponse{
+		ID:                 chapter.ID,
+		StoryID:            chapter.StoryID,
+		Title:              chapter.Title,
+		Description:        chapter.Description,
+		CoverImageURL:      chapter.CoverImageURL,
+		OrderIndex:         chapter.OrderIndex,
+		StartSlideID:       chapter.StartSlideID,
+		EntryPoints:        entries,
+		ProtagonistName:    chapter.ProtagonistName,
+		ProtagonistSprites: sprites,
+		SlideCount:         slideCount,
+		PublishedVersionID: chapter.PublishedVersionID,
+		PublishAt:          chapter.PublishAt,
+		Unlock:             *unlock,
+	}, nil
+}
+
+func toChapterDetail(chapter *entity.Chapter) (*dto.AdminChapterDetailResponse, error) {
+	base, err := toChapterResponse(chapter, len(chapter.Slides))
+	if err != nil {
+		return nil, err
+	}
+
+	g, err := graph.FromChapter(chapter)
+	if err != nil {
+		return nil, err
+	}
+	base.Issues = toIssueResponses(graph.Validate(g))
+
+	resp := &dto.AdminChapterDetailResponse{
+		AdminChapterResponse: base,
+		Endings:              make([]dto.AdminEndingResponse, 0, len(chapter.Endings)),
+		Slides:               make([]dto.AdminSlideResponse, 0, len(chapter.Slides)),
+	}
+	for _, e := range chapter.Endings {
+		resp.Endings = append(resp.Endings, dto.AdminEndingResponse{
+			ID:         e.ID,
+			Key:        e.Key,
+			Title:      e.Title,
+			Kind:       string(e.Kind),
+			BadgeCode:  e.BadgeCode,
+			ScoreBonus: e.ScoreBonus,
+		})
+	}
+
+	byID := make(map[string]*entity.Slide, len(chapter.Slides))
+	for i := range chapter.Slides {
+		byID[chapter.Slides[i].ID.String()] = &chapter.Slides[i]
+	}
+	for _, id := range graph.Order(g) {
+		slide, err := toSlideResponse(byID[id])
+		if err != nil {
+			return nil, err
+		}
+		resp.Slides = append(resp.Slides, slide)
+	}
+
+	return resp, nil
+}
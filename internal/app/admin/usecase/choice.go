@@ -0,0 +1,112 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Ablebil/lathi-be/internal/domain/dto"
+	"github.com/Ablebil/lathi-be/internal/domain/entity"
+	"github.com/Ablebil/lathi-be/pkg/i18n"
+	"github.com/Ablebil/lathi-be/pkg/response"
+	"github.com/google/uuid"
+)
+
+func (uc *adminUsecase) AddChoice(ctx context.Context, slideID uuid.UUID, req *dto.AdminChoice) (*dto.AdminSlideResponse, *response.APIError) {
+	return uc.editChoices(ctx, slideID, func(choices []entity.Choice) ([]entity.Choice, *response.APIError) {
+		choice, apiErr := toChoice(req)
+		if apiErr != nil {
+			return nil, apiErr
+		}
+		return append(choices, choice), nil
+	})
+}
+
+func (uc *adminUsecase) UpdateChoice(ctx context.Context, slideID uuid.UUID, index int, req *dto.AdminChoice) (*dto.AdminSlideResponse, *response.APIError) {
+	return uc.editChoices(ctx, slideID, func(choices []entity.Choice) ([]entity.Choice, *response.APIError) {
+		if index < 0 || index >= len(choices) {
+			return nil, response.ErrNotFound(i18n.AdminChoiceNotFound)
+		}
+		choice, apiErr := toChoice(req)
+		if apiErr != nil {
+			return nil, apiErr
+		}
+		choices[index] = choice
+		return choices, nil
+	})
+}
+
+// DeleteChoice removes the choice, the choices after it move up one index.
+func (uc *adminUsecase) DeleteChoice(ctx context.Context, slideID uuid.UUID, index int) (*dto.AdminSlideResponse, *response.APIError) {
+	return uc.editChoices(ctx, slideID, func(choices []entity.Choice) ([]entity.Choice, *response.APIError) {
+		if index < 0 || index >= len(choices) {
+			return nil, response.ErrNotFound(i18n.AdminChoiceNotFound)
+		}
+		return append(choices[:index], choices[index+1:]...), nil
+	})
+}
+
+// editChoices replaces the slide's choices with what fn makes of them and
+// returns the slide as stored.
+func (uc *adminUsecase) editChoices(ctx context.Context, slideID uuid.UUID, fn func(choices []entity.Choice) ([]entity.Choice, *response.APIError)) (*dto.AdminSlideResponse, *response.APIError) {
+	var resp dto.AdminSlideResponse
+	apiErr := uc.editSlide(ctx, slideID, func(ctx context.Context, chapter *entity.Chapter, slide *entity.Slide) (*response.APIError, error) {
+		if slide.Type != entity.SlideStory {
+			return response.ErrBadRequest(i18n.AdminChoicesNotAllowed), nil
+		}
+
+		choices, err := slide.GetChoices()
+		if err != nil {
+			return nil, err
+		}
+
+		choices, apiErr := fn(choices)
+		if apiErr != nil {
+			return apiErr, nil
+		}
+
+		slide.Choices = toJSONB(choices, "[]")
+		if err := uc.adminRepo.UpdateSlideChoices(ctx, slide); err != nil {
+			return nil, err
+		}
+
+		resp, apiErr, err = uc.checkSlide(ctx, chapter.ID, slide.ID)
+		return apiErr, err
+	})
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	return &resp, nil
+}
+
+// toChoice checks what the struct tags can't and converts the choice. Where
+// it leads is left to the graph validator.
+func toChoice(req *dto.AdminChoice) (entity.Choice, *response.APIError) {
+	if apiErr := checkSubtitles("subtitles", req.Subtitles); apiErr != nil {
+		return entity.Choice{}, apiErr
+	}
+	if err := req.ShowIf.Validate(); err != nil {
+		return entity.Choice{}, response.NewFieldValidationError("show_if", "condition")
+	}
+	if err := req.EnableIf.Validate(); err != nil {
+		return entity.Choice{}, response.NewFieldValidationError("enable_if", "condition")
+	}
+
+	choice := entity.Choice{
+		Text:        req.Text,
+		Subtitles:   req.Subtitles,
+		NextSlideID: req.NextSlideID,
+		MoodImpact:  req.MoodImpact,
+		ShowIf:      req.ShowIf,
+		EnableIf:    req.EnableIf,
+	}
+	for i, e := range req.Effects {
+		effect := entity.Effect{Var: e.Var, Op: e.Op, Value: e.Value, Scope: e.Scope}
+		if err := effect.Validate(); err != nil {
+			return entity.Choice{}, response.NewFieldValidationError(fmt.Sprintf("effects[%d]", i), "effect")
+		}
+		choice.Effects = append(choice.Effects, effect)
+	}
+
+	return choice, nil
+}
@@ -0,0 +1,392 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/Ablebil/lathi-be/internal/app/story/grading"
+	"github.com/Ablebil/lathi-be/internal/domain/dto"
+	"github.com/Ablebil/lathi-be/internal/domain/entity"
+	"github.com/Ablebil/lathi-be/internal/domain/types"
+	"github.com/Ablebil/lathi-be/pkg/i18n"
+	"github.com/Ablebil/lathi-be/pkg/response"
+	"github.com/google/uuid"
+)
+
+func (uc *adminUsecase) GetSlide(ctx context.Context, slideID uuid.UUID) (*dto.AdminSlideResponse, *response.APIError) {
+	slide, err := uc.adminRepo.GetSlide(ctx, slideID)
+	if err != nil {
+		slog.Error("failed to get slide", "error", err)
+		return nil, response.ErrInternal(i18n.CommonTryAgain)
+	}
+	if slide == nil {
+		return nil, response.ErrNotFound(i18n.StorySlideNotFound)
+	}
+
+	resp, err := toSlideResponse(slide)
+	if err != nil {
+		slog.Error("failed to build slide", "error", err, "slide_id", slide.ID)
+		return nil, response.ErrInternal(i18n.CommonTryAgain)
+	}
+	return &resp, nil
+}
+
+// CreateSlide adds a slide to the chapter. The first slide of a chapter
+// becomes its start slide.
+func (uc *adminUsecase) CreateSlide(ctx context.Context, chapterID uuid.UUID, req *dto.AdminSlideRequest) (*dto.AdminSlideResponse, *response.APIError) {
+	var resp dto.AdminSlideResponse
+	apiErr := uc.edit(ctx, func(ctx context.Context) (*response.APIError, error) {
+		chapter, apiErr, err := uc.lockChapter(ctx, chapterID)
+		if err != nil || apiErr != nil {
+			return apiErr, err
+		}
+
+		slide := &entity.Slide{ChapterID: chapterID, Choices: types.JSONB("[]")}
+		if apiErr, err := uc.applySlide(ctx, chapter, slide, req); err != nil || apiErr != nil {
+			return apiErr, err
+		}
+
+		if err := uc.adminRepo.CreateSlide(ctx, slide); err != nil {
+			return nil, err
+		}
+		if err := uc.adminRepo.ReplaceSlideVocab(ctx, slide, req.VocabIDs); err != nil {
+			return nil, err
+		}
+		if chapter.StartSlideID == nil {
+			if err := uc.adminRepo.SetStartSlide(ctx, chapterID, slide.ID); err != nil {
+				return nil, err
+			}
+		}
+
+		resp, apiErr, err = uc.checkSlide(ctx, chapterID, slide.ID)
+		return apiErr, err
+	})
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	return &resp, nil
+}
+
+// UpdateSlide replaces everything on the slide but its choices, which are
+// edited through their own endpoints.
+func (uc *adminUsecase) UpdateSlide(ctx context.Context, slideID uuid.UUID, req *dto.AdminSlideRequest) (*dto.AdminSlideResponse, *response.APIError) {
+	var resp dto.AdminSlideResponse
+	apiErr := uc.editSlide(ctx, slideID, func(ctx context.Context, chapter *entity.Chapter, slide *entity.Slide) (*response.APIError, error) {
+		if apiErr, err := uc.applySlide(ctx, chapter, slide, req); err != nil || apiErr != nil {
+			return apiErr, err
+		}
+
+		choices, err := slide.GetChoices()
+		if err != nil {
+			return nil, err
+		}
+		if slide.Type != entity.SlideStory && len(choices) > 0 {
+			return response.ErrBadRequest(i18n.AdminChoicesNotAllowed), nil
+		}
+
+		if err := uc.adminRepo.UpdateSlide(ctx, slide); err != nil {
+			return nil, err
+		}
+		if err := uc.adminRepo.ReplaceSlideVocab(ctx, slide, req.VocabIDs); err != nil {
+			return nil, err
+		}
+
+		var apiErr *response.APIError
+		resp, apiErr, err = uc.checkSlide(ctx, chapter.ID, slide.ID)
+		return apiErr, err
+	})
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	return &resp, nil
+}
+
+// DeleteSlide removes the slide from the draft, slides still pointed at are
+// refused by the graph validator. Runs in progress play a published version
+// and keep the slide until they restart.
+func (uc *adminUsecase) DeleteSlide(ctx context.Context, slideID uuid.UUID) *response.APIError {
+	return uc.editSlide(ctx, slideID, func(ctx context.Context, chapter *entity.Chapter, slide *entity.Slide) (*response.APIError, error) {
+		// the last slide takes the start with it, any other start slide has to be moved first
+		if len(chapter.Slides) == 1 {
+			chapter.StartSlideID = nil
+			if err := uc.adminRepo.UpdateChapter(ctx, chapter); err != nil {
+				return nil, err
+			}
+		}
+
+		if err := uc.adminRepo.DeleteSlide(ctx, slide.ID); err != nil {
+			return nil, err
+		}
+
+		_, _, apiErr, err := uc.checkChapter(ctx, chapter.ID)
+		return apiErr, err
+	})
+}
+
+// editSlide locks the slide's chapter and runs fn on the slide as stored.
+func (uc *adminUsecase) editSlide(ctx context.Context, slideID uuid.UUID, fn func(ctx context.Context, chapter *entity.Chapter, slide *entity.Slide) (*response.APIError, error)) *response.APIError {
+	return uc.edit(ctx, func(ctx context.Context) (*response.APIError, error) {
+		found, err := uc.adminRepo.GetSlide(ctx, slideID)
+		if err != nil {
+			return nil, err
+		}
+		if found == nil {
+			return response.ErrNotFound(i18n.StorySlideNotFound), nil
+		}
+
+		chapter, apiErr, err := uc.lockChapter(ctx, found.ChapterID)
+		if err != nil || apiErr != nil {
+			return apiErr, err
+		}
+
+		// read again under the lock, the slide may have changed or gone since
+		slide := findSlide(chapter, slideID)
+		if slide == nil {
+			return response.ErrNotFound(i18n.StorySlideNotFound), nil
+		}
+		return fn(ctx, chapter, slide)
+	})
+}
+
+// checkSlide validates the chapter after the slide was written and returns the
+// slide as stored with the warnings left in the chapter.
+func (uc *adminUsecase) checkSlide(ctx context.Context, chapterID, slideID uuid.UUID) (dto.AdminSlideResponse, *response.APIError, error) {
+	chapter, issues, apiErr, err := uc.checkChapter(ctx, chapterID)
+	if err != nil || apiErr != nil {
+		return dto.AdminSlideResponse{}, apiErr, err
+	}
+
+	resp, err := toSlideResponse(findSlide(chapter, slideID))
+	if err != nil {
+		return dto.AdminSlideResponse{}, nil, err
+	}
+	resp.Issues = issues
+	return resp, nil, nil
+}
+
+// applySlide checks what the struct tags can't and copies the request onto
+// the slide. Links between slides are left to the graph validator.
+func (uc *adminUsecase) applySlide(ctx context.Context, chapter *entity.Chapter, slide *entity.Slide, req *dto.AdminSlideRequest) (*response.APIError, error) {
+	if req.Key != "" {
+		for _, s := range chapter.Slides {
+			if s.ID != slide.ID && s.Key == req.Key {
+				return response.ErrConflict(i18n.AdminSlideKeyTaken), nil
+			}
+		}
+	}
+
+	if apiErr := checkSubtitles("subtitles", req.Subtitles); apiErr != nil {
+		return apiErr, nil
+	}
+
+	protagonist, err := chapter.Protagonist()
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range req.Characters {
+		if _, ok := protagonist.Sprites[c.ImageURL]; c.Name == entity.PlayerPlaceholder && len(protagonist.Sprites) > 0 && !ok {
+			return response.NewFieldValidationError("characters", "sprite"), nil
+		}
+	}
+
+	for i, r := range req.Routes {
+		if err := r.If.Validate(); err != nil {
+			return response.NewFieldValidationError(fmt.Sprintf("routes[%d].if", i), "condition"), nil
+		}
+	}
+
+	if apiErr, err := uc.checkWordsExist(ctx, "vocab_ids", req.VocabIDs); err != nil || apiErr != nil {
+		return apiErr, err
+	}
+	if req.Quiz != nil {
+		for _, d := range req.Quiz.DistractorIDs {
+			if d == req.Quiz.WordID {
+				return response.NewFieldValidationError("quiz.distractor_ids", "excluded"), nil
+			}
+		}
+		if req.Quiz.Ask == req.Quiz.Answer {
+			return response.NewFieldValidationError("quiz.answer", "nefield"), nil
+		}
+		words := append([]uuid.UUID{req.Quiz.WordID}, req.Quiz.DistractorIDs...)
+		if apiErr, err := uc.checkWordsExist(ctx, "quiz", words); err != nil || apiErr != nil {
+			return apiErr, err
+		}
+	}
+	if req.Translation != nil {
+		for _, a := range req.Translation.Accepted {
+			if grading.Normalize(a) == "" {
+				return response.NewFieldValidationError("translation.accepted", "required"), nil
+			}
+		}
+	}
+
+	chars := make([]entity.Character, 0, len(req.Characters))
+	for _, c := range req.Characters {
+		chars = append(chars, entity.Character{Name: c.Name, ImageURL: c.ImageURL, IsActive: c.IsActive})
+	}
+	routes := make([]entity.Route, 0, len(req.Routes))
+	for _, r := range req.Routes {
+		routes = append(routes, entity.Route{If: r.If, NextSlideID: r.NextSlideID})
+	}
+
+	slide.Key = req.Key
+	slide.SpeakerName = req.SpeakerName
+	slide.BackgroundImageURL = req.BackgroundImageURL
+	slide.Characters = toJSONB(chars, "[]")
+	slide.Content = req.Content
+	slide.Subtitles = toJSONB(req.Subtitles, "{}")
+	slide.NextSlideID = req.NextSlideID
+	slide.Routes = toJSONB(routes, "[]")
+	slide.EndingID = req.EndingID
+	slide.IsCheckpoint = req.IsCheckpoint
+
+	slide.Type = entity.SlideStory
+	slide.Quiz = types.JSONB("{}")
+	slide.Translation = types.JSONB("{}")
+	if q := req.Quiz; q != nil {
+		slide.Type = entity.SlideQuiz
+		slide.Quiz = toJSONB(entity.Quiz{
+			WordID:        q.WordID,
+			Ask:           entity.WordForm(q.Ask),
+			Answer:        entity.WordForm(q.Answer),
+			DistractorIDs: q.DistractorIDs,
+			HeartPenalty:  q.HeartPenalty,
+		}, "{}")
+	}
+	if t := req.Translation; t != nil {
+		slide.Type = entity.SlideTranslate
+		slide.Translation = toJSONB(entity.Translation{
+			Prompt:       t.Prompt,
+			Accepted:     t.Accepted,
+			HeartPenalty: t.HeartPenalty,
+		}, "{}")
+	}
+
+	return nil, nil
+}
+
+// checkWordsExist refuses dictionary ids that are not in the dictionary.
+func (uc *adminUsecase) checkWordsExist(ctx context.Context, field string, ids []uuid.UUID) (*response.APIError, error) {
+	words, err := uc.adminRepo.GetWordsByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	found := make(map[uuid.UUID]bool, len(words))
+	for _, w := range words {
+		found[w.ID] = true
+	}
+	for _, id := range ids {
+		if !found[id] {
+			return response.NewFieldValidationError(field, "exists"), nil
+		}
+	}
+	return nil, nil
+}
+
+func checkSubtitles(field string, subs entity.Subtitles) *response.APIError {
+	for lang, text := range subs {
+		if !entity.IsSubtitleLanguage(lang) {
+			return response.NewFieldValidationError(field, "language")
+		}
+		if text == "" {
+			return response.NewFieldValidationError(field, "required")
+		}
+	}
+	return nil
+}
+
+func toSlideResponse(slide *entity.Slide) (dto.AdminSlideResponse, error) {
+	chars, err := slide.GetCharacters()
+	if err != nil {
+		return dto.AdminSlideResponse{}, err
+	}
+	routes, err := slide.GetRoutes()
+	if err != nil {
+		return dto.AdminSlideResponse{}, err
+	}
+	choices, err := slide.GetChoices()
+	if err != nil {
+		return dto.AdminSlideResponse{}, err
+	}
+	subs, err := slide.GetSubtitles()
+	if err != nil {
+		return dto.AdminSlideResponse{}, err
+	}
+
+	resp := dto.AdminSlideResponse{
+		ID:                 slide.ID,
+		ChapterID:          slide.ChapterID,
+		Key:                slide.Key,
+		Type:               string(slide.Type),
+		SpeakerName:        slide.SpeakerName,
+		BackgroundImageURL: slide.BackgroundImageURL,
+		Characters:         make([]dto.AdminCharacter, 0, len(chars)),
+		Content:            slide.Content,
+		Subtitles:          subs,
+		NextSlideID:        slide.NextSlideID,
+		Routes:             make([]dto.AdminRoute, 0, len(routes)),
+		Choices:            make([]dto.AdminChoice, 0, len(choices)),
+		EndingID:           slide.EndingID,
+		IsCheckpoint:       slide.IsCheckpoint,
+		Vocabularies:       make([]dto.VocabItemResponse, 0, len(slide.Vocabularies)),
+	}
+	for _, c := range chars {
+		resp.Characters = append(resp.Characters, dto.AdminCharacter{Name: c.Name, ImageURL: c.ImageURL, IsActive: c.IsActive})
+	}
+	for _, r := range routes {
+		resp.Routes = append(resp.Routes, dto.AdminRoute{If: r.If, NextSlideID: r.NextSlideID})
+	}
+	for _, c := range choices {
+		resp.Choices = append(resp.Choices, toChoiceResponse(c))
+	}
+	for _, v := range slide.Vocabularies {
+		resp.Vocabularies = append(resp.Vocabularies, toWordResponse(&v))
+	}
+
+	quiz, err := slide.GetQuiz()
+	if err != nil {
+		return dto.AdminSlideResponse{}, err
+	}
+	if quiz != nil {
+		resp.Quiz = &dto.AdminQuiz{
+			WordID:        quiz.WordID,
+			Ask:           string(quiz.Ask),
+			Answer:        string(quiz.Answer),
+			DistractorIDs: quiz.DistractorIDs,
+			HeartPenalty:  quiz.HeartPenalty,
+		}
+	}
+
+	translation, err := slide.GetTranslation()
+	if err != nil {
+		return dto.AdminSlideResponse{}, err
+	}
+	if translation != nil {
+		resp.Translation = &dto.AdminTranslation{
+			Prompt:       translation.Prompt,
+			Accepted:     translation.Accepted,
+			HeartPenalty: translation.HeartPenalty,
+		}
+	}
+
+	return resp, nil
+}
+
+func toChoiceResponse(c entity.Choice) dto.AdminChoice {
+	choice := dto.AdminChoice{
+		Text:        c.Text,
+		Subtitles:   c.Subtitles,
+		NextSlideID: c.NextSlideID,
+		MoodImpact:  c.MoodImpact,
+		ShowIf:      c.ShowIf,
+		EnableIf:    c.EnableIf,
+	}
+	for _, e := range c.Effects {
+		choice.Effects = append(choice.Effects, dto.AdminEffect{Var: e.Var, Op: e.Op, Value: e.Value, Scope: e.Scope})
+	}
+	return choice
+}
@@ -0,0 +1,158 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+	"regexp"
+
+	"github.com/Ablebil/lathi-be/internal/domain/dto"
+	"github.com/Ablebil/lathi-be/internal/domain/entity"
+	"github.com/Ablebil/lathi-be/pkg/i18n"
+	"github.com/Ablebil/lathi-be/pkg/response"
+	"github.com/google/uuid"
+)
+
+var storySlug = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+func (uc *adminUsecase) ListStories(ctx context.Context) ([]dto.AdminStoryResponse, *response.APIError) {
+	stories, err := uc.adminRepo.ListStories(ctx)
+	if err != nil {
+		slog.Error("failed to get stories", "error", err)
+		return nil, response.ErrInternal(i18n.CommonTryAgain)
+	}
+
+	counts, err := uc.adminRepo.CountChaptersByStory(ctx)
+	if err != nil {
+		slog.Error("failed to count chapters", "error", err)
+		return nil, response.ErrInternal(i18n.CommonTryAgain)
+	}
+
+	resp := make([]dto.AdminStoryResponse, 0, len(stories))
+	for i := range stories {
+		resp = append(resp, toStoryResponse(&stories[i], counts[stories[i].ID]))
+	}
+	return resp, nil
+}
+
+// CreateStory adds an empty story, by default after the last one. Its
+// chapters are added through the chapter endpoints.
+func (uc *adminUsecase) CreateStory(ctx context.Context, req *dto.AdminStoryRequest) (*dto.AdminStoryResponse, *response.APIError) {
+	if !storySlug.MatchString(req.Slug) {
+		return nil, response.NewFieldValidationError("slug", "slug")
+	}
+
+	story := &entity.Story{}
+	apiErr := uc.edit(ctx, func(ctx context.Context) (*response.APIError, error) {
+		if apiErr, err := uc.checkStory(ctx, uuid.Nil, req); err != nil || apiErr != nil {
+			return apiErr, err
+		}
+
+		stories, err := uc.adminRepo.ListStories(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		applyStory(story, req)
+		if req.OrderIndex == nil {
+			story.OrderIndex = 1
+			if len(stories) > 0 {
+				story.OrderIndex = stories[len(stories)-1].OrderIndex + 1
+			}
+		}
+		return nil, uc.adminRepo.CreateStory(ctx, story)
+	})
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	resp := toStoryResponse(story, 0)
+	return &resp, nil
+}
+
+func (uc *adminUsecase) UpdateStory(ctx context.Context, storyID uuid.UUID, req *dto.AdminStoryRequest) (*dto.AdminStoryResponse, *response.APIError) {
+	if !storySlug.MatchString(req.Slug) {
+		return nil, response.NewFieldValidationError("slug", "slug")
+	}
+
+	var resp dto.AdminStoryResponse
+	apiErr := uc.edit(ctx, func(ctx context.Context) (*response.APIError, error) {
+		story, err := uc.adminRepo.LockStory(ctx, storyID)
+		if err != nil {
+			return nil, err
+		}
+		if story == nil {
+			return response.ErrNotFound(i18n.StoryBookNotFound), nil
+		}
+		// players and bundles find the main story by its slug
+		if story.IsMain() && req.Slug != entity.MainStorySlug {
+			return response.NewFieldValidationError("slug", "immutable"), nil
+		}
+		if apiErr, err := uc.checkStory(ctx, storyID, req); err != nil || apiErr != nil {
+			return apiErr, err
+		}
+
+		applyStory(story, req)
+		if err := uc.adminRepo.UpdateStory(ctx, story); err != nil {
+			return nil, err
+		}
+
+		counts, err := uc.adminRepo.CountChaptersByStory(ctx)
+		if err != nil {
+			return nil, err
+		}
+		resp = toStoryResponse(story, counts[story.ID])
+		return nil, nil
+	})
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	return &resp, nil
+}
+
+// checkStory makes sure the slug is free and the badge exists.
+func (uc *adminUsecase) checkStory(ctx context.Context, storyID uuid.UUID, req *dto.AdminStoryRequest) (*response.APIError, error) {
+	existing, err := uc.adminRepo.GetStoryBySlug(ctx, req.Slug)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil && existing.ID != storyID {
+		return response.ErrConflict(i18n.AdminStorySlugTaken), nil
+	}
+
+	if req.BadgeCode != "" {
+		ok, err := uc.adminRepo.BadgeExists(ctx, req.BadgeCode)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return response.NewFieldValidationError("badge_code", "exists"), nil
+		}
+	}
+	return nil, nil
+}
+
+func applyStory(story *entity.Story, req *dto.AdminStoryRequest) {
+	story.Slug = req.Slug
+	story.Title = req.Title
+	story.Description = req.Description
+	story.CoverImageURL = req.CoverImageURL
+	story.BadgeCode = req.BadgeCode
+	if req.OrderIndex != nil {
+		story.OrderIndex = *req.OrderIndex
+	}
+}
+
+func toStoryResponse(story *entity.Story, chapterCount int) dto.AdminStoryResponse {
+	return dto.AdminStoryResponse{
+		ID:            story.ID,
+		Slug:          story.Slug,
+		Title:         story.Title,
+		Description:   story.Description,
+		CoverImageURL: story.CoverImageURL,
+		OrderIndex:    story.OrderIndex,
+		IsMain:        story.IsMain(),
+		BadgeCode:     story.BadgeCode,
+		ChapterCount:  chapterCount,
+	}
+}
@@ -0,0 +1,138 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/Ablebil/lathi-be/internal/domain/dto"
+	"github.com/Ablebil/lathi-be/internal/domain/entity"
+	"github.com/Ablebil/lathi-be/pkg/i18n"
+	"github.com/Ablebil/lathi-be/pkg/response"
+	"github.com/google/uuid"
+)
+
+// PublishChapter snapshots the chapter draft as its next version and makes it
+// the one new runs start on. Runs in progress stay on their version until the
+// slot is restarted. The draft has to pass the story graph validator.
+func (uc *adminUsecase) PublishChapter(ctx context.Context, chapterID uuid.UUID) (*dto.AdminChapterVersionResponse, *response.APIError) {
+	var resp dto.AdminChapterVersionResponse
+	apiErr := uc.edit(ctx, func(ctx context.Context) (*response.APIError, error) {
+		if _, apiErr, err := uc.lockChapter(ctx, chapterID); err != nil || apiErr != nil {
+			return apiErr, err
+		}
+
+		chapter, issues, apiErr, err := uc.checkChapter(ctx, chapterID)
+		if err != nil || apiErr != nil {
+			return apiErr, err
+		}
+		if len(chapter.Slides) == 0 {
+			return response.ErrBadRequest(i18n.StoryChapterEmpty), nil
+		}
+
+		if chapter.PublishedVersionID != nil {
+			published, err := uc.adminRepo.GetChapterVersion(ctx, *chapter.PublishedVersionID)
+			if err != nil {
+				return nil, err
+			}
+			if published != nil {
+				same, err := published.SameAs(chapter)
+				if err != nil {
+					return nil, err
+				}
+				if same {
+					return response.ErrConflict(i18n.AdminNothingToPublish), nil
+				}
+			}
+		}
+
+		version, err := uc.adminRepo.PublishChapter(ctx, chapter)
+		if err != nil {
+			return nil, err
+		}
+
+		resp = toVersionResponse(version, version.ID)
+		resp.Issues = issues
+		return nil, nil
+	})
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	return &resp, nil
+}
+
+func (uc *adminUsecase) ListChapterVersions(ctx context.Context, chapterID uuid.UUID) ([]dto.AdminChapterVersionResponse, *response.APIError) {
+	chapter, err := uc.adminRepo.GetChapter(ctx, chapterID)
+	if err != nil {
+		slog.Error("failed to get chapter", "error", err)
+		return nil, response.ErrInternal(i18n.CommonTryAgain)
+	}
+	if chapter == nil {
+		return nil, response.ErrNotFound(i18n.StoryChapterNotFound)
+	}
+
+	versions, err := uc.adminRepo.ListChapterVersions(ctx, chapterID)
+	if err != nil {
+		slog.Error("failed to get chapter versions", "error", err)
+		return nil, response.ErrInternal(i18n.CommonTryAgain)
+	}
+
+	var publishedID uuid.UUID
+	if chapter.PublishedVersionID != nil {
+		publishedID = *chapter.PublishedVersionID
+	}
+
+	resp := make([]dto.AdminChapterVersionResponse, 0, len(versions))
+	for i := range versions {
+		resp = append(resp, toVersionResponse(&versions[i], publishedID))
+	}
+	return resp, nil
+}
+
+// RollbackChapter publishes an older version again. The draft is left as it
+// is, runs in progress stay on their version.
+func (uc *adminUsecase) RollbackChapter(ctx context.Context, chapterID uuid.UUID, req *dto.AdminRollbackRequest) (*dto.AdminChapterVersionResponse, *response.APIError) {
+	var resp dto.AdminChapterVersionResponse
+	apiErr := uc.edit(ctx, func(ctx context.Context) (*response.APIError, error) {
+		chapter, err := uc.adminRepo.LockChapter(ctx, chapterID)
+		if err != nil {
+			return nil, err
+		}
+		if chapter == nil {
+			return response.ErrNotFound(i18n.StoryChapterNotFound), nil
+		}
+
+		version, err := uc.adminRepo.FindChapterVersion(ctx, chapterID, req.Version)
+		if err != nil {
+			return nil, err
+		}
+		if version == nil {
+			return response.ErrNotFound(i18n.AdminVersionNotFound), nil
+		}
+		if chapter.PublishedVersionID != nil && *chapter.PublishedVersionID == version.ID {
+			return response.ErrConflict(i18n.AdminVersionIsPublished), nil
+		}
+
+		if err := uc.adminRepo.SetPublishedVersion(ctx, chapterID, version.ID); err != nil {
+			return nil, err
+		}
+
+		resp = toVersionResponse(version, version.ID)
+		return nil, nil
+	})
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	return &resp, nil
+}
+
+func toVersionResponse(version *entity.ChapterVersion, publishedID uuid.UUID) dto.AdminChapterVersionResponse {
+	return dto.AdminChapterVersionResponse{
+		ID:          version.ID,
+		Number:      version.Number,
+		Title:       version.Title,
+		IsPublished: version.ID == publishedID,
+		PublishedAt: version.PublishedAt,
+	}
+}
@@ -0,0 +1,164 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+	"math"
+
+	"github.com/Ablebil/lathi-be/internal/domain/dto"
+	"github.com/Ablebil/lathi-be/internal/domain/entity"
+	"github.com/Ablebil/lathi-be/pkg/i18n"
+	"github.com/Ablebil/lathi-be/pkg/response"
+	"github.com/google/uuid"
+)
+
+// ListWords pages through the whole dictionary, unlike the player endpoint it
+// doesn't hide words nobody unlocked.
+func (uc *adminUsecase) ListWords(ctx context.Context, req *dto.AdminWordListRequest) (*dto.AdminWordListResponse, *response.APIError) {
+	page := req.Page
+	if page < 0 {
+		return nil, response.ErrBadRequest(i18n.CommonInvalidPage)
+	} else if page < 1 {
+		page = 1
+	}
+
+	limit := req.Limit
+	if limit < 0 {
+		return nil, response.ErrBadRequest(i18n.CommonInvalidLimit)
+	} else if limit < 1 {
+		limit = uc.env.DefaultPageLimit
+	} else if limit > uc.env.MaxPageLimit {
+		limit = uc.env.MaxPageLimit
+	}
+
+	offset := (page - 1) * limit
+
+	words, total, err := uc.adminRepo.ListWords(ctx, req.Search, limit, offset)
+	if err != nil {
+		slog.Error("failed to list words", "error", err)
+		return nil, response.ErrInternal(i18n.CommonTryAgain)
+	}
+
+	items := make([]dto.VocabItemResponse, 0, len(words))
+	for i := range words {
+		items = append(items, toWordResponse(&words[i]))
+	}
+
+	return &dto.AdminWordListResponse{
+		Items: items,
+		Pagination: dto.PaginationMeta{
+			CurrentPage:  page,
+			TotalPage:    int(math.Ceil(float64(total) / float64(limit))),
+			TotalItems:   total,
+			ItemsPerPage: limit,
+		},
+	}, nil
+}
+
+func (uc *adminUsecase) CreateWord(ctx context.Context, req *dto.AdminWordRequest) (*dto.VocabItemResponse, *response.APIError) {
+	word := &entity.Dictionary{
+		WordKrama: req.WordKrama,
+		WordNgoko: req.WordNgoko,
+		WordIndo:  req.WordIndo,
+	}
+
+	apiErr := uc.edit(ctx, func(ctx context.Context) (*response.APIError, error) {
+		existing, err := uc.adminRepo.FindWordByKrama(ctx, req.WordKrama)
+		if err != nil {
+			return nil, err
+		}
+		if existing != nil {
+			return response.ErrConflict(i18n.AdminWordTaken), nil
+		}
+		return nil, uc.adminRepo.CreateWord(ctx, word)
+	})
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	resp := toWordResponse(word)
+	return &resp, nil
+}
+
+// UpdateWord saves the word and validates every chapter linking it again, a
+// renamed krama word no longer matches the {word} markers written for it.
+func (uc *adminUsecase) UpdateWord(ctx context.Context, wordID uuid.UUID, req *dto.AdminWordRequest) (*dto.VocabItemResponse, *response.APIError) {
+	var word *entity.Dictionary
+	apiErr := uc.edit(ctx, func(ctx context.Context) (*response.APIError, error) {
+		var err error
+		if word, err = uc.adminRepo.GetWord(ctx, wordID); err != nil {
+			return nil, err
+		}
+		if word == nil {
+			return response.ErrNotFound(i18n.AdminWordNotFound), nil
+		}
+
+		existing, err := uc.adminRepo.FindWordByKrama(ctx, req.WordKrama)
+		if err != nil {
+			return nil, err
+		}
+		if existing != nil && existing.ID != wordID {
+			return response.ErrConflict(i18n.AdminWordTaken), nil
+		}
+
+		word.WordKrama = req.WordKrama
+		word.WordNgoko = req.WordNgoko
+		word.WordIndo = req.WordIndo
+		if err := uc.adminRepo.UpdateWord(ctx, word); err != nil {
+			return nil, err
+		}
+
+		chapterIDs, err := uc.adminRepo.ChapterIDsByWord(ctx, wordID)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range chapterIDs {
+			if _, apiErr, err := uc.lockChapter(ctx, id); err != nil || apiErr != nil {
+				return apiErr, err
+			}
+			if _, _, apiErr, err := uc.checkChapter(ctx, id); err != nil || apiErr != nil {
+				return apiErr, err
+			}
+		}
+		return nil, nil
+	})
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	resp := toWordResponse(word)
+	return &resp, nil
+}
+
+// DeleteWord removes a word no slide links or quizzes on. Unlocks of the word
+// go with it.
+func (uc *adminUsecase) DeleteWord(ctx context.Context, wordID uuid.UUID) *response.APIError {
+	return uc.edit(ctx, func(ctx context.Context) (*response.APIError, error) {
+		word, err := uc.adminRepo.GetWord(ctx, wordID)
+		if err != nil {
+			return nil, err
+		}
+		if word == nil {
+			return response.ErrNotFound(i18n.AdminWordNotFound), nil
+		}
+
+		used, err := uc.adminRepo.CountWordUsage(ctx, wordID)
+		if err != nil {
+			return nil, err
+		}
+		if used > 0 {
+			return response.ErrConflict(i18n.AdminWordInUse), nil
+		}
+
+		return nil, uc.adminRepo.DeleteWord(ctx, wordID)
+	})
+}
+
+func toWordResponse(word *entity.Dictionary) dto.VocabItemResponse {
+	return dto.VocabItemResponse{
+		ID:        word.ID,
+		WordKrama: word.WordKrama,
+		WordNgoko: word.WordNgoko,
+		WordIndo:  word.WordIndo,
+	}
+}
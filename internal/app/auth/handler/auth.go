@@ -7,6 +7,7 @@ import (
 	"github.com/Ablebil/lathi-be/internal/domain/contract"
 	"github.com/Ablebil/lathi-be/internal/domain/dto"
 	"github.com/Ablebil/lathi-be/internal/middleware"
+	"github.com/Ablebil/lathi-be/pkg/i18n"
 	"github.com/Ablebil/lathi-be/pkg/response"
 	"github.com/Ablebil/lathi-be/pkg/validator"
 	"github.com/gofiber/fiber/v2"
@@ -36,7 +37,7 @@ func NewAuthHandler(router fiber.Router, validator validator.ValidatorItf, env *
 func (h *authHandler) register(ctx *fiber.Ctx) error {
 	req := new(dto.RegisterRequest)
 	if err := ctx.BodyParser(&req); err != nil {
-		return response.Error(ctx, response.ErrBadRequest("Data yang kamu kirim belum pas, coba cek lagi ya"), err)
+		return response.Error(ctx, response.ErrBadRequest(i18n.CommonInvalidBody), err)
 	}
 
 	if err := h.val.ValidateStruct(req); err != nil {
@@ -47,13 +48,13 @@ func (h *authHandler) register(ctx *fiber.Ctx) error {
 		return response.Error(ctx, apiErr, nil)
 	}
 
-	return response.Success(ctx, fiber.StatusCreated, "Pendaftaran berhasil, cek email kamu buat verifikasi, ya", nil)
+	return response.Success(ctx, fiber.StatusCreated, i18n.AuthRegistered, nil)
 }
 
 func (h *authHandler) verify(ctx *fiber.Ctx) error {
 	req := new(dto.VerifyRequest)
 	if err := ctx.BodyParser(&req); err != nil {
-		return response.Error(ctx, response.ErrBadRequest("Data yang kamu kirim belum pas, coba cek lagi ya"), err)
+		return response.Error(ctx, response.ErrBadRequest(i18n.CommonInvalidBody), err)
 	}
 
 	if err := h.val.ValidateStruct(req); err != nil {
@@ -64,13 +65,13 @@ func (h *authHandler) verify(ctx *fiber.Ctx) error {
 		return response.Error(ctx, apiErr, nil)
 	}
 
-	return response.Success(ctx, fiber.StatusOK, "Email kamu udah diverifikasi, yuk login sekarang!", nil)
+	return response.Success(ctx, fiber.StatusOK, i18n.AuthVerified, nil)
 }
 
 func (h *authHandler) login(ctx *fiber.Ctx) error {
 	req := new(dto.LoginRequest)
 	if err := ctx.BodyParser(&req); err != nil {
-		return response.Error(ctx, response.ErrBadRequest("Data yang kamu kirim belum pas, coba cek lagi ya"), err)
+		return response.Error(ctx, response.ErrBadRequest(i18n.CommonInvalidBody), err)
 	}
 
 	if err := h.val.ValidateStruct(req); err != nil {
@@ -97,7 +98,7 @@ func (h *authHandler) login(ctx *fiber.Ctx) error {
 		}(),
 	})
 
-	return response.Success(ctx, fiber.StatusOK, "Login sukses! Yuk mulai eksplorasi!", map[string]string{
+	return response.Success(ctx, fiber.StatusOK, i18n.AuthLoggedIn, map[string]string{
 		"access_token": resp.AccessToken,
 	})
 }
@@ -105,7 +106,7 @@ func (h *authHandler) login(ctx *fiber.Ctx) error {
 func (h *authHandler) refresh(ctx *fiber.Ctx) error {
 	refreshToken := ctx.Cookies("refresh_token")
 	if refreshToken == "" {
-		return response.Error(ctx, response.ErrUnauthorized("Sesi kamu udah habis, coba login lagi ya"), nil)
+		return response.Error(ctx, response.ErrUnauthorized(i18n.AuthSessionExpired), nil)
 	}
 
 	resp, apiErr := h.uc.Refresh(ctx.Context(), refreshToken)
@@ -128,7 +129,7 @@ func (h *authHandler) refresh(ctx *fiber.Ctx) error {
 		}(),
 	})
 
-	return response.Success(ctx, fiber.StatusOK, "Sesi kamu udah diperbarui, yuk lanjut eksplorasi!", map[string]string{
+	return response.Success(ctx, fiber.StatusOK, i18n.AuthRefreshed, map[string]string{
 		"access_token": resp.AccessToken,
 	})
 }
@@ -136,7 +137,7 @@ func (h *authHandler) refresh(ctx *fiber.Ctx) error {
 func (h *authHandler) logout(ctx *fiber.Ctx) error {
 	refreshToken := ctx.Cookies("refresh_token")
 	if refreshToken == "" {
-		return response.Error(ctx, response.ErrUnauthorized("Sesi kamu udah habis, coba login lagi ya"), nil)
+		return response.Error(ctx, response.ErrUnauthorized(i18n.AuthSessionExpired), nil)
 	}
 
 	if apiErr := h.uc.Logout(ctx.Context(), refreshToken); apiErr != nil {
@@ -158,5 +159,5 @@ func (h *authHandler) logout(ctx *fiber.Ctx) error {
 		}(),
 	})
 
-	return response.Success(ctx, fiber.StatusOK, "Logout berhasil, sampai jumpa lagi!", nil)
+	return response.Success(ctx, fiber.StatusOK, i18n.AuthLoggedOut, nil)
 }
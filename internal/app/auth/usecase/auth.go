@@ -12,6 +12,7 @@ import (
 	"github.com/Ablebil/lathi-be/internal/domain/entity"
 	"github.com/Ablebil/lathi-be/internal/infra/redis"
 	"github.com/Ablebil/lathi-be/pkg/bcrypt"
+	"github.com/Ablebil/lathi-be/pkg/i18n"
 	"github.com/Ablebil/lathi-be/pkg/jwt"
 	"github.com/Ablebil/lathi-be/pkg/mail"
 	"github.com/Ablebil/lathi-be/pkg/response"
@@ -42,36 +43,37 @@ func (uc *authUsecase) Register(ctx context.Context, req *dto.RegisterRequest) *
 	user, err := uc.repo.GetUserByEmail(ctx, req.Email)
 	if err != nil {
 		slog.Error("failed to get user", "error", err)
-		return response.ErrInternal("Coba lagi nanti ya!")
+		return response.ErrInternal(i18n.CommonTryAgain)
 	}
 	if user != nil {
-		return response.ErrConflict("Email ini udah pernah didaftarin, coba email lain ya")
+		return response.ErrConflict(i18n.AuthEmailTaken)
 	}
 
 	userByUsn, err := uc.repo.GetUserByUsername(ctx, req.Username)
 	if err != nil {
 		slog.Error("failed to get user", "error", err)
-		return response.ErrInternal("Coba lagi nanti ya!")
+		return response.ErrInternal(i18n.CommonTryAgain)
 	}
 	if userByUsn != nil {
-		return response.ErrConflict("Username ini udah dipake, coba yang lain ya")
+		return response.ErrConflict(i18n.UserUsernameTaken)
 	}
 
 	hashed, err := uc.bcrypt.Hash(req.Password)
 	if err != nil {
 		slog.Error("failed to hash password", "error", err)
-		return response.ErrInternal("Coba lagi nanti ya!")
+		return response.ErrInternal(i18n.CommonTryAgain)
 	}
 
 	newUser := &entity.User{
 		Username:  req.Username,
 		Email:     req.Email,
 		Password:  hashed,
+		Role:      entity.RolePlayer,
 		AvatarURL: uc.env.DefaultAvatarURL,
 	}
 	if err := uc.repo.CreateUser(ctx, newUser); err != nil {
 		slog.Error("failed to create user", "error", err)
-		return response.ErrInternal("Coba lagi nanti ya!")
+		return response.ErrInternal(i18n.CommonTryAgain)
 	}
 
 	// generate verif token
@@ -79,7 +81,7 @@ func (uc *authUsecase) Register(ctx context.Context, req *dto.RegisterRequest) *
 	cacheKey := fmt.Sprintf("verify:%s", token)
 	if err := uc.cache.Set(ctx, cacheKey, newUser.Email, uc.env.VerifTokenTTL); err != nil {
 		slog.Error("failed to store verification token", "error", err)
-		return response.ErrInternal("Coba lagi nanti ya!")
+		return response.ErrInternal(i18n.CommonTryAgain)
 	}
 
 	u, _ := url.Parse(uc.env.VerifURL)
@@ -95,7 +97,7 @@ func (uc *authUsecase) Register(ctx context.Context, req *dto.RegisterRequest) *
 	}
 	if err := uc.mail.Send(newUser.Email, "Verifikasi Email", "verification.html", mailData); err != nil {
 		slog.Error("failed to send verification email", "error", err)
-		return response.ErrInternal("Coba lagi nanti ya!")
+		return response.ErrInternal(i18n.CommonTryAgain)
 	}
 
 	return nil
@@ -105,25 +107,25 @@ func (uc *authUsecase) Verify(ctx context.Context, req *dto.VerifyRequest) *resp
 	cacheKey := fmt.Sprintf("verify:%s", req.Token)
 	var email string
 	if err := uc.cache.Get(ctx, cacheKey, &email); err != nil {
-		return response.ErrBadRequest("Token verifikasi ga valid atau udah kadaluarsa, coba daftar lagi ya")
+		return response.ErrBadRequest(i18n.AuthInvalidVerification)
 	}
 
 	user, err := uc.repo.GetUserByEmail(ctx, email)
 	if err != nil {
 		slog.Error("failed to get user", "error", err)
-		return response.ErrInternal("Coba lagi nanti ya!")
+		return response.ErrInternal(i18n.CommonTryAgain)
 	}
 	if user == nil {
-		return response.ErrNotFound("Akun ga ditemukan, coba daftar dulu ya")
+		return response.ErrNotFound(i18n.UserNotFound)
 	}
 	if user.IsVerified {
-		return response.ErrBadRequest("Akunmu udah terverifikasi sebelumnya")
+		return response.ErrBadRequest(i18n.AuthAlreadyVerified)
 	}
 
 	user.IsVerified = true
 	if err := uc.repo.UpdateUser(ctx, user); err != nil {
 		slog.Error("failed to update user", "error", err)
-		return response.ErrInternal("Coba lagi nanti ya!")
+		return response.ErrInternal(i18n.CommonTryAgain)
 	}
 
 	_ = uc.cache.Del(ctx, cacheKey)
@@ -134,31 +136,31 @@ func (uc *authUsecase) Login(ctx context.Context, req *dto.LoginRequest) (*dto.T
 	user, err := uc.repo.GetUserByEmail(ctx, req.Email)
 	if err != nil {
 		slog.Error("failed to get user", "error", err)
-		return nil, response.ErrInternal("Coba lagi nanti ya!")
+		return nil, response.ErrInternal(i18n.CommonTryAgain)
 	}
 	if user == nil || !uc.bcrypt.Compare(req.Password, user.Password) {
-		return nil, response.ErrUnauthorized("Email atau password kamu salah")
+		return nil, response.ErrUnauthorized(i18n.AuthInvalidCredentials)
 	}
 	if !user.IsVerified {
-		return nil, response.ErrUnauthorized("Akunmu belum terverifikasi, cek email kamu ya")
+		return nil, response.ErrUnauthorized(i18n.AuthNotVerified)
 	}
 
-	accessToken, err := uc.jwt.CreateAccessToken(user.ID, user.Username, user.Email, uc.env.AccessTTL)
+	accessToken, err := uc.jwt.CreateAccessToken(user.ID, user.Username, user.Email, string(user.Role), user.Language, uc.env.AccessTTL)
 	if err != nil {
 		slog.Error("failed to create access token", "error", err)
-		return nil, response.ErrInternal("Coba lagi nanti ya!")
+		return nil, response.ErrInternal(i18n.CommonTryAgain)
 	}
 
 	refreshToken, err := uc.jwt.CreateRefreshToken(user.ID, uc.env.RefreshTTL)
 	if err != nil {
 		slog.Error("failed to create refresh token", "error", err)
-		return nil, response.ErrInternal("Coba lagi nanti ya!")
+		return nil, response.ErrInternal(i18n.CommonTryAgain)
 	}
 
 	cacheKey := fmt.Sprintf("refresh:%s", refreshToken)
 	if err := uc.cache.Set(ctx, cacheKey, user.ID.String(), uc.env.RefreshTTL); err != nil {
 		slog.Error("failed to store refresh token", "error", err)
-		return nil, response.ErrInternal("Coba lagi nanti ya!")
+		return nil, response.ErrInternal(i18n.CommonTryAgain)
 	}
 
 	return &dto.TokenResponse{
@@ -171,31 +173,31 @@ func (uc *authUsecase) Refresh(ctx context.Context, refreshToken string) (*dto.T
 	cacheKey := fmt.Sprintf("refresh:%s", refreshToken)
 	var userID string
 	if err := uc.cache.Get(ctx, cacheKey, &userID); err != nil {
-		return nil, response.ErrUnauthorized("Sesi kamu udah habis, coba login lagi ya")
+		return nil, response.ErrUnauthorized(i18n.AuthSessionExpired)
 	}
 
 	user, err := uc.repo.GetUserByID(ctx, uuid.MustParse(userID))
 	if err != nil || user == nil {
-		return nil, response.ErrUnauthorized("Sesi kamu udah habis, coba login lagi ya")
+		return nil, response.ErrUnauthorized(i18n.AuthSessionExpired)
 	}
 
-	newAccessToken, err := uc.jwt.CreateAccessToken(user.ID, user.Username, user.Email, uc.env.AccessTTL)
+	newAccessToken, err := uc.jwt.CreateAccessToken(user.ID, user.Username, user.Email, string(user.Role), user.Language, uc.env.AccessTTL)
 	if err != nil {
 		slog.Error("failed to create access token", "error", err)
-		return nil, response.ErrInternal("Coba lagi nanti ya!")
+		return nil, response.ErrInternal(i18n.CommonTryAgain)
 	}
 
 	newRefreshToken, err := uc.jwt.CreateRefreshToken(user.ID, uc.env.RefreshTTL)
 	if err != nil {
 		slog.Error("failed to create refresh token", "error", err)
-		return nil, response.ErrInternal("Coba lagi nanti ya!")
+		return nil, response.ErrInternal(i18n.CommonTryAgain)
 	}
 
 	_ = uc.cache.Del(ctx, cacheKey)
 	newCacheKey := fmt.Sprintf("refresh:%s", newRefreshToken)
 	if err := uc.cache.Set(ctx, newCacheKey, user.ID.String(), uc.env.RefreshTTL); err != nil {
 		slog.Error("failed to store refresh token", "error", err)
-		return nil, response.ErrInternal("Coba lagi nanti ya!")
+		return nil, response.ErrInternal(i18n.CommonTryAgain)
 	}
 
 	return &dto.TokenResponse{
@@ -208,7 +210,7 @@ func (uc *authUsecase) Logout(ctx context.Context, refreshToken string) *respons
 	cacheKey := fmt.Sprintf("refresh:%s", refreshToken)
 	if err := uc.cache.Del(ctx, cacheKey); err != nil {
 		slog.Error("failed to delete refresh token", "error", err)
-		return response.ErrInternal("Coba lagi nanti ya!")
+		return response.ErrInternal(i18n.CommonTryAgain)
 	}
 
 	return nil
@@ -6,6 +6,7 @@ import (
 	"github.com/Ablebil/lathi-be/internal/domain/contract"
 	"github.com/Ablebil/lathi-be/internal/domain/dto"
 	"github.com/Ablebil/lathi-be/internal/middleware"
+	"github.com/Ablebil/lathi-be/pkg/i18n"
 	"github.com/Ablebil/lathi-be/pkg/response"
 	"github.com/Ablebil/lathi-be/pkg/validator"
 	"github.com/gofiber/fiber/v2"
@@ -30,7 +31,7 @@ func NewDictionaryHandler(router fiber.Router, validator validator.ValidatorItf,
 func (h *dictionaryHandler) getDictionaryList(ctx *fiber.Ctx) error {
 	userIDStr, ok := ctx.Locals("user_id").(string)
 	if !ok {
-		return response.Error(ctx, response.ErrUnauthorized("Kamu belum login, yuk login dulu"), nil)
+		return response.Error(ctx, response.ErrUnauthorized(i18n.AuthNotLoggedIn), nil)
 	}
 	userID, _ := uuid.Parse(userIDStr)
 
@@ -43,17 +44,17 @@ func (h *dictionaryHandler) getDictionaryList(ctx *fiber.Ctx) error {
 	queryParams := ctx.Queries()
 	for k, v := range queryParams {
 		if !allowedParams[k] {
-			return response.Error(ctx, response.ErrBadRequest("Query parameter '"+k+"' ga dikenali"), nil)
+			return response.Error(ctx, response.ErrBadRequest(i18n.CommonUnknownQueryParam, k), nil)
 		}
 
 		if v == "" {
-			return response.Error(ctx, response.ErrBadRequest("Query parameter '"+k+"' ga boleh kosong"), nil)
+			return response.Error(ctx, response.ErrBadRequest(i18n.CommonEmptyQueryParam, k), nil)
 		}
 	}
 
 	req := new(dto.DictionaryListRequest)
 	if err := ctx.QueryParser(req); err != nil {
-		return response.Error(ctx, response.ErrBadRequest("Format query ga valid"), err)
+		return response.Error(ctx, response.ErrBadRequest(i18n.CommonInvalidQuery), err)
 	}
 
 	resp, apiErr := h.uc.GetDictionaryList(ctx.Context(), userID, req)
@@ -61,5 +62,5 @@ func (h *dictionaryHandler) getDictionaryList(ctx *fiber.Ctx) error {
 		return response.Error(ctx, apiErr, nil)
 	}
 
-	return response.Success(ctx, fiber.StatusOK, "Kamus berhasil dimuat", resp)
+	return response.Success(ctx, fiber.StatusOK, i18n.DictionaryLoaded, resp)
 }
@@ -6,6 +6,7 @@ import (
 
 	"github.com/Ablebil/lathi-be/internal/domain/dto"
 	"github.com/Ablebil/lathi-be/internal/domain/entity"
+	"github.com/Ablebil/lathi-be/internal/infra/postgresql"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
@@ -54,6 +55,6 @@ func (r *dictionaryRepository) GetDictionaries(ctx context.Context, userID uuid.
 
 func (r *dictionaryRepository) CountTotalVocabs(ctx context.Context) (int64, error) {
 	var count int64
-	err := r.db.WithContext(ctx).Model(&entity.Dictionary{}).Count(&count).Error
+	err := postgresql.Conn(ctx, r.db).Model(&entity.Dictionary{}).Count(&count).Error
 	return count, err
 }
@@ -8,6 +8,7 @@ import (
 	"github.com/Ablebil/lathi-be/internal/config"
 	"github.com/Ablebil/lathi-be/internal/domain/contract"
 	"github.com/Ablebil/lathi-be/internal/domain/dto"
+	"github.com/Ablebil/lathi-be/pkg/i18n"
 	"github.com/Ablebil/lathi-be/pkg/response"
 	"github.com/google/uuid"
 )
@@ -27,14 +28,14 @@ func NewDictionaryUsecase(repo contract.DictionaryRepositoryItf, env *config.Env
 func (uc *dictionaryUsecase) GetDictionaryList(ctx context.Context, userID uuid.UUID, req *dto.DictionaryListRequest) (*dto.DictionaryListResponse, *response.APIError) {
 	page := req.Page
 	if page < 0 {
-		return nil, response.ErrBadRequest("Halaman ga valid")
+		return nil, response.ErrBadRequest(i18n.CommonInvalidPage)
 	} else if page < 1 {
 		page = 1
 	}
 
 	limit := req.Limit
 	if limit < 0 {
-		return nil, response.ErrBadRequest("Jumlah data per halaman ga valid")
+		return nil, response.ErrBadRequest(i18n.CommonInvalidLimit)
 	} else if limit < 1 {
 		limit = uc.env.DefaultPageLimit
 	} else if limit > uc.env.MaxPageLimit {
@@ -46,7 +47,7 @@ func (uc *dictionaryUsecase) GetDictionaryList(ctx context.Context, userID uuid.
 	items, total, err := uc.repo.GetDictionaries(ctx, userID, req.Search, limit, offset)
 	if err != nil {
 		slog.Error("failed to get dictionaries", "error", err)
-		return nil, response.ErrInternal("Coba lagi nanti ya!")
+		return nil, response.ErrInternal(i18n.CommonTryAgain)
 	}
 
 	for i := range items {
@@ -5,6 +5,7 @@ import (
 
 	"github.com/Ablebil/lathi-be/internal/domain/contract"
 	"github.com/Ablebil/lathi-be/internal/middleware"
+	"github.com/Ablebil/lathi-be/pkg/i18n"
 	"github.com/Ablebil/lathi-be/pkg/response"
 	"github.com/gofiber/fiber/v2"
 )
@@ -28,5 +29,5 @@ func (h *leaderboardHandler) getLeaderboard(ctx *fiber.Ctx) error {
 		return response.Error(ctx, apiErr, nil)
 	}
 
-	return response.Success(ctx, fiber.StatusOK, "Leaderboard berhasil dimuat", resp)
+	return response.Success(ctx, fiber.StatusOK, i18n.LeaderboardLoaded, resp)
 }
@@ -6,6 +6,7 @@ import (
 
 	"github.com/Ablebil/lathi-be/internal/domain/contract"
 	"github.com/Ablebil/lathi-be/internal/domain/entity"
+	"github.com/Ablebil/lathi-be/internal/infra/postgresql"
 	"github.com/Ablebil/lathi-be/internal/infra/redis"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -25,14 +26,14 @@ func NewLeaderboardRepository(db *gorm.DB, cache redis.RedisItf) contract.Leader
 
 func (r *leaderboardRepository) UpdateUserScore(ctx context.Context, userID uuid.UUID) error {
 	var user entity.User
-	err := r.db.WithContext(ctx).
-		Select("last_chapter_completed", "total_words_collected").
+	err := postgresql.Conn(ctx, r.db).
+		Select("chapters_completed", "total_words_collected", "bonus_score").
 		First(&user, userID).Error
 	if err != nil {
 		return err
 	}
 
-	score := calculateScore(user.LastChapterCompleted, user.TotalWordsCollected)
+	score := calculateScore(user.ChaptersCompleted, user.TotalWordsCollected, user.BonusScore)
 	return r.cache.ZAdd(ctx, "leaderboard:global", float64(score), userID.String())
 }
 
@@ -50,7 +51,7 @@ func (r *leaderboardRepository) GetTopUsers(ctx context.Context, limit int) ([]c
 		}
 
 		var user entity.User
-		if err := r.db.WithContext(ctx).Select("username", "avatar_url", "current_title").First(&user, userID).Error; err != nil {
+		if err := postgresql.Conn(ctx, r.db).Select("username", "avatar_url", "current_title").First(&user, userID).Error; err != nil {
 			continue
 		}
 
@@ -87,16 +88,16 @@ func (r *leaderboardRepository) GetUserRank(ctx context.Context, userID uuid.UUI
 
 func (r *leaderboardRepository) RebuildLeaderboard(ctx context.Context) error {
 	var users []entity.User
-	err := r.db.WithContext(ctx).
+	err := postgresql.Conn(ctx, r.db).
 		Where("is_verified = ?", true).
-		Select("id", "last_chapter_completed", "total_words_collected").
+		Select("id", "chapters_completed", "total_words_collected", "bonus_score").
 		Find(&users).Error
 	if err != nil {
 		return err
 	}
 
 	for _, user := range users {
-		score := calculateScore(user.LastChapterCompleted, user.TotalWordsCollected)
+		score := calculateScore(user.ChaptersCompleted, user.TotalWordsCollected, user.BonusScore)
 		if err := r.cache.ZAdd(ctx, "leaderboard:global", float64(score), user.ID.String()); err != nil {
 			return err
 		}
@@ -110,6 +111,6 @@ func (r *leaderboardRepository) RemoveUserFromLeaderboard(ctx context.Context, u
 	return r.cache.ZRem(ctx, key, userID.String())
 }
 
-func calculateScore(chaptersCompleted, vocabsCollected int) int {
-	return (chaptersCompleted * 100) + (vocabsCollected * 10)
+func calculateScore(chaptersCompleted, vocabsCollected, bonus int) int {
+	return (chaptersCompleted * 100) + (vocabsCollected * 10) + bonus
 }
@@ -7,6 +7,7 @@ import (
 	"github.com/Ablebil/lathi-be/internal/domain/contract"
 	"github.com/Ablebil/lathi-be/internal/domain/dto"
 	"github.com/Ablebil/lathi-be/internal/infra/minio"
+	"github.com/Ablebil/lathi-be/pkg/i18n"
 	"github.com/Ablebil/lathi-be/pkg/response"
 )
 
@@ -26,7 +27,7 @@ func (uc *leaderboardUsecase) GetLeaderboard(ctx context.Context) (*dto.Leaderbo
 	entries, err := uc.repo.GetTopUsers(ctx, 5)
 	if err != nil {
 		slog.Error("failed to get top users", "error", err)
-		return nil, response.ErrInternal("Coba lagi nanti ya!")
+		return nil, response.ErrInternal(i18n.CommonTryAgain)
 	}
 
 	var topUsers []dto.LeaderboardItemResponse
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/Ablebil/lathi-be/internal/domain/contract"
+	"github.com/Ablebil/lathi-be/internal/domain/entity"
+	"github.com/Ablebil/lathi-be/internal/domain/types"
+	"github.com/Ablebil/lathi-be/internal/infra/postgresql"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type outboxRepository struct {
+	db *gorm.DB
+}
+
+func NewOutboxRepository(db *gorm.DB) contract.OutboxRepositoryItf {
+	return &outboxRepository{
+		db: db,
+	}
+}
+
+func (r *outboxRepository) Enqueue(ctx context.Context, topic string, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	return postgresql.Conn(ctx, r.db).Create(&entity.OutboxEvent{
+		Topic:       topic,
+		Payload:     types.JSONB(data),
+		AvailableAt: time.Now(),
+	}).Error
+}
+
+// ClaimPending locks due events for the current transaction, skipping rows
+// another worker already holds.
+func (r *outboxRepository) ClaimPending(ctx context.Context, limit, maxAttempts int) ([]entity.OutboxEvent, error) {
+	var events []entity.OutboxEvent
+	err := postgresql.Conn(ctx, r.db).
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("processed_at IS NULL AND available_at <= ? AND attempts < ?", time.Now(), maxAttempts).
+		Order("available_at ASC").
+		Limit(limit).
+		Find(&events).Error
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+func (r *outboxRepository) MarkProcessed(ctx context.Context, id uuid.UUID) error {
+	return postgresql.Conn(ctx, r.db).Model(&entity.OutboxEvent{}).
+		Where("id = ?", id).
+		Update("processed_at", time.Now()).Error
+}
+
+func (r *outboxRepository) MarkFailed(ctx context.Context, id uuid.UUID, reason string, retryAt time.Time) error {
+	return postgresql.Conn(ctx, r.db).Model(&entity.OutboxEvent{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"attempts":     gorm.Expr("attempts + 1"),
+			"last_error":   reason,
+			"available_at": retryAt,
+		}).Error
+}
+
+func (r *outboxRepository) DeleteProcessed(ctx context.Context, before time.Time) (int64, error) {
+	result := postgresql.Conn(ctx, r.db).
+		Where("processed_at IS NOT NULL AND processed_at < ?", before).
+		Delete(&entity.OutboxEvent{})
+
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,94 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/Ablebil/lathi-be/internal/domain/contract"
+	"github.com/Ablebil/lathi-be/internal/domain/entity"
+	"github.com/Ablebil/lathi-be/internal/infra/postgresql"
+)
+
+const (
+	batchSize   = 50
+	maxAttempts = 10
+	baseBackoff = 10 * time.Second
+	maxBackoff  = 1 * time.Hour
+)
+
+type handlerFunc func(ctx context.Context, event entity.OutboxEvent) error
+
+type outboxUsecase struct {
+	repo     contract.OutboxRepositoryItf
+	tx       postgresql.TransactorItf
+	handlers map[string]handlerFunc
+}
+
+func NewOutboxUsecase(repo contract.OutboxRepositoryItf, tx postgresql.TransactorItf, lbRepo contract.LeaderboardRepositoryItf) contract.OutboxUsecaseItf {
+	uc := &outboxUsecase{
+		repo: repo,
+		tx:   tx,
+	}
+
+	uc.handlers = map[string]handlerFunc{
+		entity.OutboxTopicLeaderboardScore: func(ctx context.Context, event entity.OutboxEvent) error {
+			var payload entity.LeaderboardScorePayload
+			if err := json.Unmarshal(event.Payload, &payload); err != nil {
+				return err
+			}
+			return lbRepo.UpdateUserScore(ctx, payload.UserID)
+		},
+	}
+
+	return uc
+}
+
+// Dispatch delivers one batch of due events. Failed events are retried with
+// exponential backoff until they run out of attempts.
+func (uc *outboxUsecase) Dispatch(ctx context.Context) (int, error) {
+	delivered := 0
+	err := uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		events, err := uc.repo.ClaimPending(ctx, batchSize, maxAttempts)
+		if err != nil {
+			return err
+		}
+
+		for _, event := range events {
+			if err := uc.handle(ctx, event); err != nil {
+				slog.Error("failed to deliver outbox event", "error", err, "id", event.ID, "topic", event.Topic, "attempt", event.Attempts+1)
+				if err := uc.repo.MarkFailed(ctx, event.ID, err.Error(), time.Now().Add(backoff(event.Attempts))); err != nil {
+					return err
+				}
+				continue
+			}
+
+			if err := uc.repo.MarkProcessed(ctx, event.ID); err != nil {
+				return err
+			}
+			delivered++
+		}
+
+		return nil
+	})
+
+	return delivered, err
+}
+
+func (uc *outboxUsecase) handle(ctx context.Context, event entity.OutboxEvent) error {
+	handler, ok := uc.handlers[event.Topic]
+	if !ok {
+		return fmt.Errorf("no handler for topic %q", event.Topic)
+	}
+	return handler(ctx, event)
+}
+
+func backoff(attempts int) time.Duration {
+	d := baseBackoff << attempts
+	if d <= 0 || d > maxBackoff {
+		return maxBackoff
+	}
+	return d
+}
@@ -0,0 +1,167 @@
+package grading
+
+import (
+	"strings"
+	"unicode"
+)
+
+const (
+	StatusCorrect = "correct"
+	StatusTypo    = "typo" // accepted but misspelled
+	StatusWrong   = "wrong"
+	StatusMissing = "missing" // expected word the player left out
+	StatusExtra   = "extra"
+)
+
+// Token is the feedback for one word of the player's answer. Missing words
+// have no text, only the expected word.
+type Token struct {
+	Text     string `json:"text"`
+	Expected string `json:"expected,omitempty"`
+	Status   string `json:"status"`
+}
+
+type Result struct {
+	Correct  bool
+	Expected string // closest accepted answer
+	Tokens   []Token
+}
+
+// spelling variants that are written interchangeably in Javanese
+var spelling = strings.NewReplacer("é", "e", "è", "e", "ê", "e", "dh", "d", "th", "t")
+
+// Normalize lower cases the text, folds spelling variants and drops punctuation.
+func Normalize(s string) string {
+	return strings.Join(normalizeTokens(fields(s)), " ")
+}
+
+// Grade compares the answer against every accepted answer word by word and
+// returns the closest match. Words within a small edit distance of the
+// expected word count as typos and don't make the answer wrong.
+func Grade(answer string, accepted []string) Result {
+	typed := fields(answer)
+	typedNorm := normalizeTokens(typed)
+
+	var best Result
+	bestCost := -1
+	for _, acc := range accepted {
+		expected := fields(acc)
+		cost, tokens := align(typed, typedNorm, expected, normalizeTokens(expected))
+		if bestCost < 0 || cost < bestCost {
+			bestCost = cost
+			best = Result{Correct: cost == 0, Expected: acc, Tokens: tokens}
+		}
+	}
+
+	return best
+}
+
+func fields(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+func normalizeTokens(tokens []string) []string {
+	norm := make([]string, len(tokens))
+	for i, t := range tokens {
+		norm[i] = spelling.Replace(strings.ToLower(t))
+	}
+	return norm
+}
+
+// align runs a word level edit distance between the typed and expected words
+// and walks it back into per word feedback.
+func align(typed, typedNorm, expected, expectedNorm []string) (int, []Token) {
+	n, m := len(typed), len(expected)
+
+	match := func(i, j int) (int, string) {
+		if typedNorm[i] == expectedNorm[j] {
+			return 0, StatusCorrect
+		}
+		if levenshtein(typedNorm[i], expectedNorm[j]) <= tolerance(expectedNorm[j]) {
+			return 0, StatusTypo
+		}
+		return 1, StatusWrong
+	}
+
+	dist := make([][]int, n+1)
+	for i := range dist {
+		dist[i] = make([]int, m+1)
+		dist[i][0] = i
+	}
+	for j := 0; j <= m; j++ {
+		dist[0][j] = j
+	}
+	for i := 1; i <= n; i++ {
+		for j := 1; j <= m; j++ {
+			cost, _ := match(i-1, j-1)
+			dist[i][j] = min(dist[i-1][j-1]+cost, dist[i-1][j]+1, dist[i][j-1]+1)
+		}
+	}
+
+	var tokens []Token
+	i, j := n, m
+	for i > 0 || j > 0 {
+		if i > 0 && j > 0 {
+			cost, status := match(i-1, j-1)
+			if dist[i][j] == dist[i-1][j-1]+cost {
+				t := Token{Text: typed[i-1], Status: status}
+				if status != StatusCorrect {
+					t.Expected = expected[j-1]
+				}
+				tokens = append(tokens, t)
+				i, j = i-1, j-1
+				continue
+			}
+		}
+		if i > 0 && dist[i][j] == dist[i-1][j]+1 {
+			tokens = append(tokens, Token{Text: typed[i-1], Status: StatusExtra})
+			i--
+			continue
+		}
+		tokens = append(tokens, Token{Expected: expected[j-1], Status: StatusMissing})
+		j--
+	}
+
+	for l, r := 0, len(tokens)-1; l < r; l, r = l+1, r-1 {
+		tokens[l], tokens[r] = tokens[r], tokens[l]
+	}
+
+	return dist[n][m], tokens
+}
+
+// tolerance is how many typos a word of this length may have.
+func tolerance(word string) int {
+	switch n := len([]rune(word)); {
+	case n <= 3:
+		return 0
+	case n <= 6:
+		return 1
+	default:
+		return 2
+	}
+}
+
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j-1]+cost, prev[j]+1, cur[j-1]+1)
+		}
+		prev, cur = cur, prev
+	}
+
+	return prev[len(rb)]
+}
@@ -0,0 +1,176 @@
+package grading
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"lower cases", "Kula Badhe", "kula bade"},
+		{"folds accented e", "badhé tindèk", "bade tindek"},
+		{"folds dh and th", "dhateng Bathara", "dateng batara"},
+		{"drops punctuation", "Inggih, leres!", "inggih leres"},
+		{"collapses spaces", "  kula   nggih ", "kula nggih"},
+		{"empty", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Normalize(tt.in); got != tt.want {
+				t.Errorf("Normalize(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGrade(t *testing.T) {
+	tests := []struct {
+		name     string
+		answer   string
+		accepted []string
+		correct  bool
+		expected string
+		tokens   []Token
+	}{
+		{
+			name:     "exact",
+			answer:   "kula badhe tindak",
+			accepted: []string{"kula badhe tindak"},
+			correct:  true,
+			expected: "kula badhe tindak",
+			tokens: []Token{
+				{Text: "kula", Status: StatusCorrect},
+				{Text: "badhe", Status: StatusCorrect},
+				{Text: "tindak", Status: StatusCorrect},
+			},
+		},
+		{
+			name:     "spelling variants and case",
+			answer:   "Kula badé tindhak.",
+			accepted: []string{"kula badhe tindak"},
+			correct:  true,
+			expected: "kula badhe tindak",
+			tokens: []Token{
+				{Text: "Kula", Status: StatusCorrect},
+				{Text: "badé", Status: StatusCorrect},
+				{Text: "tindhak", Status: StatusCorrect},
+			},
+		},
+		{
+			name:     "typo within tolerance",
+			answer:   "kula badhe tindal",
+			accepted: []string{"kula badhe tindak"},
+			correct:  true,
+			expected: "kula badhe tindak",
+			tokens: []Token{
+				{Text: "kula", Status: StatusCorrect},
+				{Text: "badhe", Status: StatusCorrect},
+				{Text: "tindal", Expected: "tindak", Status: StatusTypo},
+			},
+		},
+		{
+			name:     "four letter word allows one typo",
+			answer:   "kulo",
+			accepted: []string{"kula"},
+			correct:  true,
+			expected: "kula",
+			tokens:   []Token{{Text: "kulo", Expected: "kula", Status: StatusTypo}},
+		},
+		{
+			name:     "three letter words allow no typo",
+			answer:   "aku",
+			accepted: []string{"ana"},
+			correct:  false,
+			expected: "ana",
+			tokens:   []Token{{Text: "aku", Expected: "ana", Status: StatusWrong}},
+		},
+		{
+			name:     "wrong word",
+			answer:   "aku arep tindak",
+			accepted: []string{"kula badhe tindak"},
+			correct:  false,
+			expected: "kula badhe tindak",
+			tokens: []Token{
+				{Text: "aku", Expected: "kula", Status: StatusWrong},
+				{Text: "arep", Expected: "badhe", Status: StatusWrong},
+				{Text: "tindak", Status: StatusCorrect},
+			},
+		},
+		{
+			name:     "missing word",
+			answer:   "kula tindak",
+			accepted: []string{"kula badhe tindak"},
+			correct:  false,
+			expected: "kula badhe tindak",
+			tokens: []Token{
+				{Text: "kula", Status: StatusCorrect},
+				{Expected: "badhe", Status: StatusMissing},
+				{Text: "tindak", Status: StatusCorrect},
+			},
+		},
+		{
+			name:     "extra word",
+			answer:   "kula badhe sampun tindak",
+			accepted: []string{"kula badhe tindak"},
+			correct:  false,
+			expected: "kula badhe tindak",
+			tokens: []Token{
+				{Text: "kula", Status: StatusCorrect},
+				{Text: "badhe", Status: StatusCorrect},
+				{Text: "sampun", Status: StatusExtra},
+				{Text: "tindak", Status: StatusCorrect},
+			},
+		},
+		{
+			name:     "closest accepted answer wins",
+			answer:   "kula nedha",
+			accepted: []string{"kula badhe tindak", "kula nedha"},
+			correct:  true,
+			expected: "kula nedha",
+			tokens: []Token{
+				{Text: "kula", Status: StatusCorrect},
+				{Text: "nedha", Status: StatusCorrect},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Grade(tt.answer, tt.accepted)
+			if got.Correct != tt.correct {
+				t.Errorf("Correct = %v, want %v", got.Correct, tt.correct)
+			}
+			if got.Expected != tt.expected {
+				t.Errorf("Expected = %q, want %q", got.Expected, tt.expected)
+			}
+			if !reflect.DeepEqual(got.Tokens, tt.tokens) {
+				t.Errorf("Tokens = %+v, want %+v", got.Tokens, tt.tokens)
+			}
+		})
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"kula", "", 4},
+		{"kula", "kula", 0},
+		{"kula", "kulo", 1},
+		{"tindak", "tidak", 1},
+		{"badé", "bade", 1},
+	}
+
+	for _, tt := range tests {
+		if got := levenshtein(tt.a, tt.b); got != tt.want {
+			t.Errorf("levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,429 @@
+package graph
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Ablebil/lathi-be/internal/domain/entity"
+)
+
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+const (
+	CodeDanglingReference  = "dangling_reference"
+	CodeUnreachableSlide   = "unreachable_slide"
+	CodeNoExit             = "no_exit"
+	CodeEmptyChoiceText    = "empty_choice_text"
+	CodeUnknownVocabMarker = "unknown_vocab_marker"
+	CodeMalformedMarker    = "malformed_marker"
+	CodeMissingStart       = "missing_start"
+	CodeUnknownEnding      = "unknown_ending"
+	CodeEndingNotTerminal  = "ending_not_terminal"
+	CodeMissingEnding      = "missing_ending"
+	CodeMissingFallback    = "missing_fallback"
+)
+
+// Graph is a chapter reduced to what the validator needs. Nodes are keyed by
+// whatever identifies a slide in the source (bundle key or slide id).
+type Graph struct {
+	Start   string
+	Entries map[string]string // named entry points, name -> node id
+	Endings []string          // ids of the endings defined by the chapter
+	Nodes   []Node
+}
+
+type Node struct {
+	ID      string
+	Label   string // shown in issues, falls back to ID
+	Content string
+	Next    string
+	Routes  []string // conditional next slides
+	Choices []Edge
+	Vocab   []string // krama words attached to the slide
+	Ending  string   // ending reached when the story stops here
+}
+
+type Edge struct {
+	Text        string
+	Next        string
+	Conditional bool // hidden or disabled unless a condition holds
+}
+
+type Issue struct {
+	Severity Severity `json:"severity"`
+	Slide    string   `json:"slide,omitempty"`
+	Code     string   `json:"code"`
+	Message  string   `json:"message"`
+}
+
+func (i Issue) String() string {
+	if i.Slide == "" {
+		return fmt.Sprintf("[%s] %s: %s", i.Severity, i.Code, i.Message)
+	}
+	return fmt.Sprintf("[%s] slide %s: %s: %s", i.Severity, i.Slide, i.Code, i.Message)
+}
+
+// ValidationError is returned when a graph has at least one error-level issue.
+type ValidationError struct {
+	Issues []Issue
+}
+
+func (e *ValidationError) Error() string {
+	var msgs []string
+	for _, i := range e.Issues {
+		if i.Severity == SeverityError {
+			msgs = append(msgs, i.String())
+		}
+	}
+	return fmt.Sprintf("story graph has %d error(s): %s", len(msgs), strings.Join(msgs, "; "))
+}
+
+// Validate reports dangling references, unreachable slides, slides that can
+// never reach an ending, routes or conditional choices without a fallback,
+// empty choices, malformed markers, vocab markers without a matching
+// vocabulary entry and ending records attached to the wrong slides.
+func Validate(g *Graph) []Issue {
+	var issues []Issue
+	if len(g.Nodes) == 0 {
+		return issues
+	}
+
+	nodes := make(map[string]*Node, len(g.Nodes))
+	for i := range g.Nodes {
+		nodes[g.Nodes[i].ID] = &g.Nodes[i]
+	}
+
+	start := g.Start
+	if start == "" {
+		issues = append(issues, Issue{
+			Severity: SeverityError,
+			Code:     CodeMissingStart,
+			Message:  "chapter has no start slide",
+		})
+		return issues
+	}
+	if _, ok := nodes[start]; !ok {
+		issues = append(issues, Issue{
+			Severity: SeverityError,
+			Code:     CodeMissingStart,
+			Message:  fmt.Sprintf("start slide %q does not exist", start),
+		})
+		return issues
+	}
+
+	for name, id := range g.Entries {
+		if _, ok := nodes[id]; !ok {
+			issues = append(issues, Issue{
+				Severity: SeverityError,
+				Code:     CodeDanglingReference,
+				Message:  fmt.Sprintf("entry point %q points to unknown slide %q", name, id),
+			})
+		}
+	}
+
+	endingIDs := make(map[string]bool, len(g.Endings))
+	for _, id := range g.Endings {
+		endingIDs[id] = true
+	}
+
+	for i := range g.Nodes {
+		n := &g.Nodes[i]
+
+		if n.Ending != "" && !endingIDs[n.Ending] {
+			issues = append(issues, n.issue(SeverityError, CodeUnknownEnding, "ending %q does not exist", n.Ending))
+		}
+
+		if n.Next != "" {
+			if _, ok := nodes[n.Next]; !ok {
+				issues = append(issues, n.issue(SeverityError, CodeDanglingReference, "next slide %q does not exist", n.Next))
+			}
+		}
+
+		for ri, r := range n.Routes {
+			if _, ok := nodes[r]; !ok {
+				issues = append(issues, n.issue(SeverityError, CodeDanglingReference, "route %d points to unknown slide %q", ri, r))
+			}
+		}
+		// hidden choices fall through to the routes too, so choices don't make up for it
+		if len(n.Routes) > 0 && n.Next == "" {
+			issues = append(issues, n.issue(SeverityError, CodeMissingFallback, "routes have no next slide to fall back to when no condition holds"))
+		} else if n.conditionalOnly() && n.Next == "" {
+			issues = append(issues, n.issue(SeverityError, CodeMissingFallback, "every choice is conditional and there is no next slide to fall back to when none can be picked"))
+		}
+
+		for ci, c := range n.Choices {
+			if strings.TrimSpace(c.Text) == "" {
+				issues = append(issues, n.issue(SeverityError, CodeEmptyChoiceText, "choice %d has no text", ci))
+			}
+			if _, ok := nodes[c.Next]; !ok {
+				issues = append(issues, n.issue(SeverityError, CodeDanglingReference, "choice %d points to unknown slide %q", ci, c.Next))
+			}
+		}
+
+		issues = append(issues, checkVocabMarkers(n)...)
+	}
+
+	reachable := walk(start, func(id string) []string {
+		return nodes[id].targets(nodes)
+	})
+
+	// reverse edges, used to find every slide that can still reach an ending
+	incoming := make(map[string][]string, len(nodes))
+	var endings []string
+	for i := range g.Nodes {
+		n := &g.Nodes[i]
+		targets := n.targets(nodes)
+		if len(targets) == 0 {
+			endings = append(endings, n.ID)
+		}
+		for _, t := range targets {
+			incoming[t] = append(incoming[t], n.ID)
+		}
+	}
+
+	canFinish := make(map[string]bool, len(nodes))
+	for _, e := range endings {
+		for id := range walk(e, func(id string) []string { return incoming[id] }) {
+			canFinish[id] = true
+		}
+	}
+
+	for i := range g.Nodes {
+		n := &g.Nodes[i]
+		if !reachable[n.ID] {
+			issues = append(issues, n.issue(SeverityWarning, CodeUnreachableSlide, "slide cannot be reached from the start slide"))
+			continue
+		}
+		if !canFinish[n.ID] {
+			issues = append(issues, n.issue(SeverityError, CodeNoExit, "every path from this slide loops forever without reaching an ending"))
+		}
+
+		terminal := len(n.targets(nodes)) == 0
+		if n.Ending != "" && !terminal {
+			issues = append(issues, n.issue(SeverityWarning, CodeEndingNotTerminal, "ending %q is only reached when the story stops on this slide", n.Ending))
+		}
+		if n.Ending == "" && terminal && len(g.Endings) > 0 {
+			issues = append(issues, n.issue(SeverityWarning, CodeMissingEnding, "story stops here without an ending"))
+		}
+	}
+
+	return issues
+}
+
+func HasErrors(issues []Issue) bool {
+	for _, i := range issues {
+		if i.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// Check validates the graph and wraps error-level issues in a ValidationError.
+func Check(g *Graph) ([]Issue, error) {
+	issues := Validate(g)
+	if HasErrors(issues) {
+		return issues, &ValidationError{Issues: issues}
+	}
+	return issues, nil
+}
+
+// FromChapter builds a graph from a stored chapter with its slides, slide
+// vocabularies and endings preloaded.
+func FromChapter(chapter *entity.Chapter) (*Graph, error) {
+	entries, err := chapter.GetEntryPoints()
+	if err != nil {
+		return nil, fmt.Errorf("chapter %s has invalid entry points: %w", chapter.ID, err)
+	}
+
+	g := &Graph{Entries: make(map[string]string, len(entries))}
+	for name, id := range entries {
+		g.Entries[name] = id.String()
+	}
+	if chapter.StartSlideID != nil {
+		g.Start = chapter.StartSlideID.String()
+	}
+	for _, e := range chapter.Endings {
+		g.Endings = append(g.Endings, e.ID.String())
+	}
+	for _, s := range chapter.Slides {
+		choices, err := s.GetChoices()
+		if err != nil {
+			return nil, fmt.Errorf("slide %s has invalid choices: %w", s.ID, err)
+		}
+
+		n := Node{
+			ID:      s.ID.String(),
+			Label:   s.Key,
+			Content: s.Content,
+		}
+		if s.NextSlideID != nil {
+			n.Next = s.NextSlideID.String()
+		}
+		if s.EndingID != nil {
+			n.Ending = s.EndingID.String()
+		}
+
+		routes, err := s.GetRoutes()
+		if err != nil {
+			return nil, fmt.Errorf("slide %s has invalid routes: %w", s.ID, err)
+		}
+		for _, r := range routes {
+			n.Routes = append(n.Routes, r.NextSlideID.String())
+		}
+		for _, c := range choices {
+			n.Choices = append(n.Choices, Edge{
+				Text:        c.Text,
+				Next:        c.NextSlideID.String(),
+				Conditional: c.ShowIf != nil || c.EnableIf != nil,
+			})
+		}
+		for _, v := range s.Vocabularies {
+			n.Vocab = append(n.Vocab, v.WordKrama)
+		}
+
+		g.Nodes = append(g.Nodes, n)
+	}
+
+	return g, nil
+}
+
+// Order lists node ids in story order: breadth first from the start slide so
+// branches of a choice sit next to each other, followed by any unreachable
+// slides in their original order.
+func Order(g *Graph) []string {
+	nodes := make(map[string]*Node, len(g.Nodes))
+	for i := range g.Nodes {
+		nodes[g.Nodes[i].ID] = &g.Nodes[i]
+	}
+
+	order := make([]string, 0, len(g.Nodes))
+	seen := make(map[string]bool, len(g.Nodes))
+	if _, ok := nodes[g.Start]; ok {
+		seen[g.Start] = true
+		queue := []string{g.Start}
+		for len(queue) > 0 {
+			id := queue[0]
+			queue = queue[1:]
+			order = append(order, id)
+			for _, t := range nodes[id].targets(nodes) {
+				if !seen[t] {
+					seen[t] = true
+					queue = append(queue, t)
+				}
+			}
+		}
+	}
+
+	for _, n := range g.Nodes {
+		if !seen[n.ID] {
+			order = append(order, n.ID)
+		}
+	}
+
+	return order
+}
+
+func checkVocabMarkers(n *Node) []Issue {
+	vocab := make(map[string]bool, len(n.Vocab))
+	for _, v := range n.Vocab {
+		vocab[normalizeWord(v)] = true
+	}
+
+	texts := []string{n.Content}
+	for _, c := range n.Choices {
+		texts = append(texts, c.Text)
+	}
+
+	var issues []Issue
+	for _, text := range texts {
+		segs, err := entity.ParseSegments(text)
+		if err != nil {
+			issues = append(issues, n.issue(SeverityError, CodeMalformedMarker, "%v", err))
+			continue
+		}
+		for _, seg := range segs {
+			if seg.Type == entity.SegmentVocab && !vocab[normalizeWord(seg.Text)] {
+				issues = append(issues, n.issue(SeverityError, CodeUnknownVocabMarker, "marker {%s} has no matching vocabulary on the slide", seg.Text))
+			}
+		}
+	}
+	return issues
+}
+
+func normalizeWord(w string) string {
+	return strings.ToLower(strings.TrimSpace(w))
+}
+
+// targets mirrors how SubmitAction moves: choices win over routes and the plain
+// next slide, which are only taken as well when every choice is conditional.
+// Conditions are ignored, any branch may be taken.
+func (n *Node) targets(nodes map[string]*Node) []string {
+	var out []string
+	for _, c := range n.Choices {
+		if _, ok := nodes[c.Next]; ok {
+			out = append(out, c.Next)
+		}
+	}
+	if len(n.Choices) > 0 && !n.conditionalOnly() {
+		return out
+	}
+
+	for _, r := range n.Routes {
+		if _, ok := nodes[r]; ok {
+			out = append(out, r)
+		}
+	}
+
+	if n.Next != "" {
+		if _, ok := nodes[n.Next]; ok {
+			out = append(out, n.Next)
+		}
+	}
+	return out
+}
+
+// conditionalOnly reports whether the slide has choices and every one of them
+// may be hidden or disabled.
+func (n *Node) conditionalOnly() bool {
+	for _, c := range n.Choices {
+		if !c.Conditional {
+			return false
+		}
+	}
+	return len(n.Choices) > 0
+}
+
+func (n *Node) issue(sev Severity, code, format string, args ...any) Issue {
+	label := n.Label
+	if label == "" {
+		label = n.ID
+	}
+	return Issue{
+		Severity: sev,
+		Slide:    label,
+		Code:     code,
+		Message:  fmt.Sprintf(format, args...),
+	}
+}
+
+func walk(from string, next func(id string) []string) map[string]bool {
+	seen := map[string]bool{from: true}
+	queue := []string{from}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		for _, t := range next(id) {
+			if !seen[t] {
+				seen[t] = true
+				queue = append(queue, t)
+			}
+		}
+	}
+	return seen
+}
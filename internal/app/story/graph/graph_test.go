@@ -0,0 +1,201 @@
+package graph
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func codes(issues []Issue) []string {
+	var out []string
+	for _, i := range issues {
+		out = append(out, string(i.Severity)+":"+i.Slide+":"+i.Code)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name  string
+		graph Graph
+		want  []string
+	}{
+		{
+			name: "linear chapter",
+			graph: Graph{Start: "a", Nodes: []Node{
+				{ID: "a", Next: "b"},
+				{ID: "b"},
+			}},
+		},
+		{
+			name:  "empty chapter",
+			graph: Graph{},
+		},
+		{
+			name:  "missing start",
+			graph: Graph{Nodes: []Node{{ID: "a"}}},
+			want:  []string{"error::missing_start"},
+		},
+		{
+			name:  "unknown start",
+			graph: Graph{Start: "x", Nodes: []Node{{ID: "a"}}},
+			want:  []string{"error::missing_start"},
+		},
+		{
+			name: "dangling next and choice",
+			graph: Graph{Start: "a", Nodes: []Node{
+				{ID: "a", Next: "x"},
+				{ID: "c", Choices: []Edge{{Text: "go", Next: "z"}}},
+			}},
+			want: []string{
+				"error:a:dangling_reference",
+				"error:c:dangling_reference",
+				"warning:c:unreachable_slide",
+			},
+		},
+		{
+			name: "dangling route",
+			graph: Graph{Start: "a", Nodes: []Node{
+				{ID: "a", Routes: []string{"y"}, Next: "b"},
+				{ID: "b"},
+			}},
+			want: []string{"error:a:dangling_reference"},
+		},
+		{
+			name: "dangling entry point",
+			graph: Graph{Start: "a", Entries: map[string]string{"retry": "x"}, Nodes: []Node{
+				{ID: "a"},
+			}},
+			want: []string{"error::dangling_reference"},
+		},
+		{
+			name: "unreachable slide",
+			graph: Graph{Start: "a", Nodes: []Node{
+				{ID: "a"},
+				{ID: "b", Label: "lost"},
+			}},
+			want: []string{"warning:lost:unreachable_slide"},
+		},
+		{
+			name: "loop without exit",
+			graph: Graph{Start: "a", Nodes: []Node{
+				{ID: "a", Next: "b"},
+				{ID: "b", Next: "a"},
+			}},
+			want: []string{"error:a:no_exit", "error:b:no_exit"},
+		},
+		{
+			name: "loop with a way out",
+			graph: Graph{Start: "a", Nodes: []Node{
+				{ID: "a", Choices: []Edge{{Text: "again", Next: "a"}, {Text: "leave", Next: "b"}}},
+				{ID: "b"},
+			}},
+		},
+		{
+			name: "routes without fallback",
+			graph: Graph{Start: "a", Nodes: []Node{
+				{ID: "a", Routes: []string{"b"}},
+				{ID: "b"},
+			}},
+			want: []string{"error:a:missing_fallback"},
+		},
+		{
+			name: "routes with fallback",
+			graph: Graph{Start: "a", Nodes: []Node{
+				{ID: "a", Routes: []string{"b"}, Next: "c"},
+				{ID: "b"},
+				{ID: "c"},
+			}},
+		},
+		{
+			name: "conditional choices without fallback",
+			graph: Graph{Start: "a", Nodes: []Node{
+				{ID: "a", Choices: []Edge{{Text: "x", Next: "b", Conditional: true}, {Text: "y", Next: "b", Conditional: true}}},
+				{ID: "b"},
+			}},
+			want: []string{"error:a:missing_fallback"},
+		},
+		{
+			name: "conditional choices with fallback",
+			graph: Graph{Start: "a", Nodes: []Node{
+				{ID: "a", Choices: []Edge{{Text: "x", Next: "b", Conditional: true}}, Next: "c"},
+				{ID: "b"},
+				{ID: "c"},
+			}},
+		},
+		{
+			name: "one unconditional choice is enough",
+			graph: Graph{Start: "a", Nodes: []Node{
+				{ID: "a", Choices: []Edge{{Text: "x", Next: "b", Conditional: true}, {Text: "y", Next: "c"}}},
+				{ID: "b"},
+				{ID: "c"},
+			}},
+		},
+		{
+			name: "empty choice text",
+			graph: Graph{Start: "a", Nodes: []Node{
+				{ID: "a", Choices: []Edge{{Text: "  ", Next: "b"}}},
+				{ID: "b"},
+			}},
+			want: []string{"error:a:empty_choice_text"},
+		},
+		{
+			name: "vocab markers",
+			graph: Graph{Start: "a", Nodes: []Node{
+				{ID: "a", Content: "{Kula} {badhe} tindak", Vocab: []string{"kula"}, Next: "b"},
+				{ID: "b", Content: "{kula"},
+			}},
+			want: []string{"error:a:unknown_vocab_marker", "error:b:malformed_marker"},
+		},
+		{
+			name: "endings",
+			graph: Graph{Start: "a", Endings: []string{"good"}, Nodes: []Node{
+				{ID: "a", Ending: "good", Choices: []Edge{{Text: "x", Next: "b"}, {Text: "y", Next: "c"}}},
+				{ID: "b", Ending: "bad"},
+				{ID: "c"},
+			}},
+			want: []string{
+				"error:b:unknown_ending",
+				"warning:a:ending_not_terminal",
+				"warning:c:missing_ending",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := codes(Validate(&tt.graph))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Validate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	warnOnly := &Graph{Start: "a", Nodes: []Node{{ID: "a"}, {ID: "b"}}}
+	if _, err := Check(warnOnly); err != nil {
+		t.Errorf("Check() with warnings only returned %v", err)
+	}
+
+	broken := &Graph{Start: "a", Nodes: []Node{{ID: "a", Next: "x"}}}
+	if _, err := Check(broken); err == nil {
+		t.Error("Check() with a dangling reference returned no error")
+	}
+}
+
+func TestOrder(t *testing.T) {
+	g := &Graph{Start: "a", Nodes: []Node{
+		{ID: "lost"},
+		{ID: "d"},
+		{ID: "c", Next: "d"},
+		{ID: "b", Next: "d"},
+		{ID: "a", Choices: []Edge{{Text: "x", Next: "b"}, {Text: "y", Next: "c"}}},
+	}}
+
+	want := []string{"a", "b", "c", "d", "lost"}
+	if got := Order(g); !reflect.DeepEqual(got, want) {
+		t.Errorf("Order() = %v, want %v", got, want)
+	}
+}
@@ -1,11 +1,14 @@
 package handler
 
 import (
+	"strconv"
 	"time"
 
 	"github.com/Ablebil/lathi-be/internal/domain/contract"
 	"github.com/Ablebil/lathi-be/internal/domain/dto"
+	"github.com/Ablebil/lathi-be/internal/domain/entity"
 	"github.com/Ablebil/lathi-be/internal/middleware"
+	"github.com/Ablebil/lathi-be/pkg/i18n"
 	"github.com/Ablebil/lathi-be/pkg/response"
 	"github.com/Ablebil/lathi-be/pkg/validator"
 	"github.com/gofiber/fiber/v2"
@@ -24,17 +27,63 @@ func NewStoryHandler(router fiber.Router, validator validator.ValidatorItf, mw m
 	}
 
 	storyRouter := router.Group("/stories", mw.Authenticate)
+	storyRouter.Get("/books", mw.RateLimit(30, 1*time.Minute, "story_books"), handler.getStoryList)
+	storyRouter.Get("/books/:id", mw.RateLimit(30, 1*time.Minute, "story_book"), handler.getStoryDetail)
 	storyRouter.Get("/chapters", mw.RateLimit(30, 1*time.Minute, "story_chapters"), handler.getChapterList)
 	storyRouter.Get("/chapters/:id/content", mw.RateLimit(20, 1*time.Minute, "story_content"), handler.getChapterContent)
+	storyRouter.Get("/chapters/:id/slides", mw.RateLimit(60, 1*time.Minute, "story_slides"), handler.getUpcomingSlides)
 	storyRouter.Get("/chapters/:id/session", mw.RateLimit(20, 1*time.Minute, "story_session"), handler.getUserSession)
 	storyRouter.Post("/chapters/:id/start", mw.RateLimit(10, 1*time.Minute, "story_start"), handler.startSession)
+	storyRouter.Post("/chapters/:id/resume", mw.RateLimit(10, 1*time.Minute, "story_resume"), handler.resumeFromCheckpoint)
+	storyRouter.Get("/chapters/:id/saves", mw.RateLimit(20, 1*time.Minute, "story_saves"), handler.listSaves)
+	storyRouter.Post("/chapters/:id/saves", mw.RateLimit(10, 1*time.Minute, "story_save_create"), handler.createSave)
+	storyRouter.Get("/chapters/:id/saves/:slot", mw.RateLimit(20, 1*time.Minute, "story_save_load"), handler.loadSave)
+	storyRouter.Delete("/chapters/:id/saves/:slot", mw.RateLimit(10, 1*time.Minute, "story_save_delete"), handler.deleteSave)
+	storyRouter.Get("/chapters/:id/attempts", mw.RateLimit(20, 1*time.Minute, "story_attempts"), handler.listAttempts)
+	storyRouter.Get("/chapters/:id/history", mw.RateLimit(30, 1*time.Minute, "story_history"), handler.getHistory)
 	storyRouter.Post("/action", mw.RateLimit(60, 1*time.Minute, "story_action"), handler.submitAction)
 }
 
+func (h *storyHandler) getStoryList(ctx *fiber.Ctx) error {
+	userIDStr, ok := ctx.Locals("user_id").(string)
+	if !ok {
+		return response.Error(ctx, response.ErrUnauthorized(i18n.AuthNotLoggedIn), nil)
+	}
+	userID, _ := uuid.Parse(userIDStr)
+
+	resp, apiErr := h.uc.GetStoryList(ctx.Context(), userID)
+	if apiErr != nil {
+		return response.Error(ctx, apiErr, nil)
+	}
+
+	return response.Success(ctx, fiber.StatusOK, i18n.StoryBooksLoaded, resp)
+}
+
+func (h *storyHandler) getStoryDetail(ctx *fiber.Ctx) error {
+	userIDStr, ok := ctx.Locals("user_id").(string)
+	if !ok {
+		return response.Error(ctx, response.ErrUnauthorized(i18n.AuthNotLoggedIn), nil)
+	}
+	userID, _ := uuid.Parse(userIDStr)
+
+	storyIDStr := ctx.Params("id")
+	storyID, err := uuid.Parse(storyIDStr)
+	if err != nil {
+		return response.Error(ctx, response.NewParamValidationError("id", "uuid"), err)
+	}
+
+	resp, apiErr := h.uc.GetStoryDetail(ctx.Context(), userID, storyID)
+	if apiErr != nil {
+		return response.Error(ctx, apiErr, nil)
+	}
+
+	return response.Success(ctx, fiber.StatusOK, i18n.StoryBookLoaded, resp)
+}
+
 func (h *storyHandler) getChapterList(ctx *fiber.Ctx) error {
 	userIDStr, ok := ctx.Locals("user_id").(string)
 	if !ok {
-		return response.Error(ctx, response.ErrUnauthorized("Kamu belum login, yuk login dulu"), nil)
+		return response.Error(ctx, response.ErrUnauthorized(i18n.AuthNotLoggedIn), nil)
 	}
 	userID, _ := uuid.Parse(userIDStr)
 
@@ -43,13 +92,13 @@ func (h *storyHandler) getChapterList(ctx *fiber.Ctx) error {
 		return response.Error(ctx, apiErr, nil)
 	}
 
-	return response.Success(ctx, fiber.StatusOK, "Daftar chapter berhasil dimuat", resp)
+	return response.Success(ctx, fiber.StatusOK, i18n.StoryChaptersLoaded, resp)
 }
 
 func (h *storyHandler) getChapterContent(ctx *fiber.Ctx) error {
 	userIDStr, ok := ctx.Locals("user_id").(string)
 	if !ok {
-		return response.Error(ctx, response.ErrUnauthorized("Kamu belum login, yuk login dulu"), nil)
+		return response.Error(ctx, response.ErrUnauthorized(i18n.AuthNotLoggedIn), nil)
 	}
 	userID, _ := uuid.Parse(userIDStr)
 
@@ -59,18 +108,80 @@ func (h *storyHandler) getChapterContent(ctx *fiber.Ctx) error {
 		return response.Error(ctx, response.NewParamValidationError("id", "uuid"), err)
 	}
 
-	resp, apiErr := h.uc.GetChapterContent(ctx.Context(), userID, chapterID)
+	slot, apiErr := slotQuery(ctx)
+	if apiErr != nil {
+		return response.Error(ctx, apiErr, nil)
+	}
+
+	// overrides the user's subtitle preference for this request
+	subtitle := ctx.Query("subtitle")
+	if apiErr := checkSubtitle(subtitle); apiErr != nil {
+		return response.Error(ctx, apiErr, nil)
+	}
+
+	resp, apiErr := h.uc.GetChapterContent(ctx.Context(), userID, chapterID, slot, subtitle)
+	if apiErr != nil {
+		return response.Error(ctx, apiErr, nil)
+	}
+
+	return response.Success(ctx, fiber.StatusOK, i18n.StoryContentLoaded, resp)
+}
+
+func (h *storyHandler) getUpcomingSlides(ctx *fiber.Ctx) error {
+	userIDStr, ok := ctx.Locals("user_id").(string)
+	if !ok {
+		return response.Error(ctx, response.ErrUnauthorized(i18n.AuthNotLoggedIn), nil)
+	}
+	userID, _ := uuid.Parse(userIDStr)
+
+	chapterIDStr := ctx.Params("id")
+	chapterID, err := uuid.Parse(chapterIDStr)
+	if err != nil {
+		return response.Error(ctx, response.NewParamValidationError("id", "uuid"), err)
+	}
+
+	allowedParams := map[string]bool{
+		"slot":     true,
+		"depth":    true,
+		"subtitle": true,
+	}
+
+	queryParams := ctx.Queries()
+	for k, v := range queryParams {
+		if !allowedParams[k] {
+			return response.Error(ctx, response.ErrBadRequest(i18n.CommonUnknownQueryParam, k), nil)
+		}
+
+		if v == "" {
+			return response.Error(ctx, response.ErrBadRequest(i18n.CommonEmptyQueryParam, k), nil)
+		}
+	}
+
+	slot, apiErr := slotQuery(ctx)
+	if apiErr != nil {
+		return response.Error(ctx, apiErr, nil)
+	}
+
+	req := new(dto.UpcomingSlidesRequest)
+	if err := ctx.QueryParser(req); err != nil {
+		return response.Error(ctx, response.ErrBadRequest(i18n.CommonInvalidQuery), err)
+	}
+	if apiErr := checkSubtitle(req.Subtitle); apiErr != nil {
+		return response.Error(ctx, apiErr, nil)
+	}
+
+	resp, apiErr := h.uc.GetUpcomingSlides(ctx.Context(), userID, chapterID, slot, req)
 	if apiErr != nil {
 		return response.Error(ctx, apiErr, nil)
 	}
 
-	return response.Success(ctx, fiber.StatusOK, "Konten chapter berhasil dimuat", resp)
+	return response.Success(ctx, fiber.StatusOK, i18n.StoryUpcomingLoaded, resp)
 }
 
 func (h *storyHandler) getUserSession(ctx *fiber.Ctx) error {
 	userIDStr, ok := ctx.Locals("user_id").(string)
 	if !ok {
-		return response.Error(ctx, response.ErrUnauthorized("Kamu belum login, yuk login dulu"), nil)
+		return response.Error(ctx, response.ErrUnauthorized(i18n.AuthNotLoggedIn), nil)
 	}
 	userID, _ := uuid.Parse(userIDStr)
 
@@ -80,18 +191,23 @@ func (h *storyHandler) getUserSession(ctx *fiber.Ctx) error {
 		return response.Error(ctx, response.NewParamValidationError("id", "uuid"), err)
 	}
 
-	resp, apiErr := h.uc.GetUserSession(ctx.Context(), userID, chapterID)
+	slot, apiErr := slotQuery(ctx)
+	if apiErr != nil {
+		return response.Error(ctx, apiErr, nil)
+	}
+
+	resp, apiErr := h.uc.GetUserSession(ctx.Context(), userID, chapterID, slot)
 	if apiErr != nil {
 		return response.Error(ctx, apiErr, nil)
 	}
 
-	return response.Success(ctx, fiber.StatusOK, "Progressmu berhasil dipulihkan", resp)
+	return response.Success(ctx, fiber.StatusOK, i18n.StoryProgressLoaded, resp)
 }
 
 func (h *storyHandler) startSession(ctx *fiber.Ctx) error {
 	userIDStr, ok := ctx.Locals("user_id").(string)
 	if !ok {
-		return response.Error(ctx, response.ErrUnauthorized("Kamu belum login, yuk login dulu"), nil)
+		return response.Error(ctx, response.ErrUnauthorized(i18n.AuthNotLoggedIn), nil)
 	}
 	userID, _ := uuid.Parse(userIDStr)
 
@@ -101,23 +217,246 @@ func (h *storyHandler) startSession(ctx *fiber.Ctx) error {
 		return response.Error(ctx, response.NewParamValidationError("id", "uuid"), err)
 	}
 
-	if apiErr := h.uc.StartSession(ctx.Context(), userID, chapterID); apiErr != nil {
+	slot, apiErr := slotQuery(ctx)
+	if apiErr != nil {
+		return response.Error(ctx, apiErr, nil)
+	}
+
+	if apiErr := h.uc.StartSession(ctx.Context(), userID, chapterID, slot); apiErr != nil {
 		return response.Error(ctx, apiErr, nil)
 	}
 
-	return response.Success(ctx, fiber.StatusOK, "Permainan dimulai, semangat ya!", nil)
+	return response.Success(ctx, fiber.StatusOK, i18n.StoryStarted, nil)
+}
+
+func (h *storyHandler) resumeFromCheckpoint(ctx *fiber.Ctx) error {
+	userIDStr, ok := ctx.Locals("user_id").(string)
+	if !ok {
+		return response.Error(ctx, response.ErrUnauthorized(i18n.AuthNotLoggedIn), nil)
+	}
+	userID, _ := uuid.Parse(userIDStr)
+
+	chapterIDStr := ctx.Params("id")
+	chapterID, err := uuid.Parse(chapterIDStr)
+	if err != nil {
+		return response.Error(ctx, response.NewParamValidationError("id", "uuid"), err)
+	}
+
+	slot, apiErr := slotQuery(ctx)
+	if apiErr != nil {
+		return response.Error(ctx, apiErr, nil)
+	}
+
+	resp, apiErr := h.uc.ResumeFromCheckpoint(ctx.Context(), userID, chapterID, slot)
+	if apiErr != nil {
+		return response.Error(ctx, apiErr, nil)
+	}
+
+	return response.Success(ctx, fiber.StatusOK, i18n.StoryResumed, resp)
+}
+
+func (h *storyHandler) listSaves(ctx *fiber.Ctx) error {
+	userIDStr, ok := ctx.Locals("user_id").(string)
+	if !ok {
+		return response.Error(ctx, response.ErrUnauthorized(i18n.AuthNotLoggedIn), nil)
+	}
+	userID, _ := uuid.Parse(userIDStr)
+
+	chapterIDStr := ctx.Params("id")
+	chapterID, err := uuid.Parse(chapterIDStr)
+	if err != nil {
+		return response.Error(ctx, response.NewParamValidationError("id", "uuid"), err)
+	}
+
+	resp, apiErr := h.uc.ListSaves(ctx.Context(), userID, chapterID)
+	if apiErr != nil {
+		return response.Error(ctx, apiErr, nil)
+	}
+
+	return response.Success(ctx, fiber.StatusOK, i18n.SaveListLoaded, resp)
+}
+
+func (h *storyHandler) createSave(ctx *fiber.Ctx) error {
+	userIDStr, ok := ctx.Locals("user_id").(string)
+	if !ok {
+		return response.Error(ctx, response.ErrUnauthorized(i18n.AuthNotLoggedIn), nil)
+	}
+	userID, _ := uuid.Parse(userIDStr)
+
+	chapterIDStr := ctx.Params("id")
+	chapterID, err := uuid.Parse(chapterIDStr)
+	if err != nil {
+		return response.Error(ctx, response.NewParamValidationError("id", "uuid"), err)
+	}
+
+	req := new(dto.CreateSaveRequest)
+	if err := ctx.BodyParser(&req); err != nil {
+		return response.Error(ctx, response.ErrBadRequest(i18n.CommonInvalidBody), err)
+	}
+
+	if err := h.val.ValidateStruct(req); err != nil {
+		return response.Error(ctx, response.NewValidationError(err), err)
+	}
+
+	resp, apiErr := h.uc.CreateSave(ctx.Context(), userID, chapterID, req)
+	if apiErr != nil {
+		return response.Error(ctx, apiErr, nil)
+	}
+
+	return response.Success(ctx, fiber.StatusCreated, i18n.SaveCreated, resp)
+}
+
+func (h *storyHandler) loadSave(ctx *fiber.Ctx) error {
+	userIDStr, ok := ctx.Locals("user_id").(string)
+	if !ok {
+		return response.Error(ctx, response.ErrUnauthorized(i18n.AuthNotLoggedIn), nil)
+	}
+	userID, _ := uuid.Parse(userIDStr)
+
+	chapterIDStr := ctx.Params("id")
+	chapterID, err := uuid.Parse(chapterIDStr)
+	if err != nil {
+		return response.Error(ctx, response.NewParamValidationError("id", "uuid"), err)
+	}
+
+	slot, apiErr := parseSlot("slot", ctx.Params("slot"))
+	if apiErr != nil {
+		return response.Error(ctx, apiErr, nil)
+	}
+
+	resp, apiErr := h.uc.LoadSave(ctx.Context(), userID, chapterID, slot)
+	if apiErr != nil {
+		return response.Error(ctx, apiErr, nil)
+	}
+
+	return response.Success(ctx, fiber.StatusOK, i18n.SaveLoaded, resp)
+}
+
+func (h *storyHandler) deleteSave(ctx *fiber.Ctx) error {
+	userIDStr, ok := ctx.Locals("user_id").(string)
+	if !ok {
+		return response.Error(ctx, response.ErrUnauthorized(i18n.AuthNotLoggedIn), nil)
+	}
+	userID, _ := uuid.Parse(userIDStr)
+
+	chapterIDStr := ctx.Params("id")
+	chapterID, err := uuid.Parse(chapterIDStr)
+	if err != nil {
+		return response.Error(ctx, response.NewParamValidationError("id", "uuid"), err)
+	}
+
+	slot, apiErr := parseSlot("slot", ctx.Params("slot"))
+	if apiErr != nil {
+		return response.Error(ctx, apiErr, nil)
+	}
+
+	if apiErr := h.uc.DeleteSave(ctx.Context(), userID, chapterID, slot); apiErr != nil {
+		return response.Error(ctx, apiErr, nil)
+	}
+
+	return response.Success(ctx, fiber.StatusOK, i18n.SaveDeleted, nil)
+}
+
+func (h *storyHandler) listAttempts(ctx *fiber.Ctx) error {
+	userIDStr, ok := ctx.Locals("user_id").(string)
+	if !ok {
+		return response.Error(ctx, response.ErrUnauthorized(i18n.AuthNotLoggedIn), nil)
+	}
+	userID, _ := uuid.Parse(userIDStr)
+
+	chapterIDStr := ctx.Params("id")
+	chapterID, err := uuid.Parse(chapterIDStr)
+	if err != nil {
+		return response.Error(ctx, response.NewParamValidationError("id", "uuid"), err)
+	}
+
+	allowedParams := map[string]bool{
+		"page":  true,
+		"limit": true,
+	}
+
+	queryParams := ctx.Queries()
+	for k, v := range queryParams {
+		if !allowedParams[k] {
+			return response.Error(ctx, response.ErrBadRequest(i18n.CommonUnknownQueryParam, k), nil)
+		}
+
+		if v == "" {
+			return response.Error(ctx, response.ErrBadRequest(i18n.CommonEmptyQueryParam, k), nil)
+		}
+	}
+
+	req := new(dto.AttemptListRequest)
+	if err := ctx.QueryParser(req); err != nil {
+		return response.Error(ctx, response.ErrBadRequest(i18n.CommonInvalidQuery), err)
+	}
+
+	resp, apiErr := h.uc.ListAttempts(ctx.Context(), userID, chapterID, req)
+	if apiErr != nil {
+		return response.Error(ctx, apiErr, nil)
+	}
+
+	return response.Success(ctx, fiber.StatusOK, i18n.StoryAttemptsLoaded, resp)
+}
+
+func (h *storyHandler) getHistory(ctx *fiber.Ctx) error {
+	userIDStr, ok := ctx.Locals("user_id").(string)
+	if !ok {
+		return response.Error(ctx, response.ErrUnauthorized(i18n.AuthNotLoggedIn), nil)
+	}
+	userID, _ := uuid.Parse(userIDStr)
+
+	chapterIDStr := ctx.Params("id")
+	chapterID, err := uuid.Parse(chapterIDStr)
+	if err != nil {
+		return response.Error(ctx, response.NewParamValidationError("id", "uuid"), err)
+	}
+
+	allowedParams := map[string]bool{
+		"slot":  true,
+		"page":  true,
+		"limit": true,
+	}
+
+	queryParams := ctx.Queries()
+	for k, v := range queryParams {
+		if !allowedParams[k] {
+			return response.Error(ctx, response.ErrBadRequest(i18n.CommonUnknownQueryParam, k), nil)
+		}
+
+		if v == "" {
+			return response.Error(ctx, response.ErrBadRequest(i18n.CommonEmptyQueryParam, k), nil)
+		}
+	}
+
+	slot, apiErr := slotQuery(ctx)
+	if apiErr != nil {
+		return response.Error(ctx, apiErr, nil)
+	}
+
+	req := new(dto.HistoryListRequest)
+	if err := ctx.QueryParser(req); err != nil {
+		return response.Error(ctx, response.ErrBadRequest(i18n.CommonInvalidQuery), err)
+	}
+
+	resp, apiErr := h.uc.GetHistory(ctx.Context(), userID, chapterID, slot, req)
+	if apiErr != nil {
+		return response.Error(ctx, apiErr, nil)
+	}
+
+	return response.Success(ctx, fiber.StatusOK, i18n.StoryHistoryLoaded, resp)
 }
 
 func (h *storyHandler) submitAction(ctx *fiber.Ctx) error {
 	userIDStr, ok := ctx.Locals("user_id").(string)
 	if !ok {
-		return response.Error(ctx, response.ErrUnauthorized("Kamu belum login, yuk login dulu"), nil)
+		return response.Error(ctx, response.ErrUnauthorized(i18n.AuthNotLoggedIn), nil)
 	}
 	userID, _ := uuid.Parse(userIDStr)
 
 	req := new(dto.StoryActionRequest)
 	if err := ctx.BodyParser(&req); err != nil {
-		return response.Error(ctx, response.ErrBadRequest("Data yang kamu kirim belum pas, coba cek lagi ya"), err)
+		return response.Error(ctx, response.ErrBadRequest(i18n.CommonInvalidBody), err)
 	}
 
 	if err := h.val.ValidateStruct(req); err != nil {
@@ -129,5 +468,29 @@ func (h *storyHandler) submitAction(ctx *fiber.Ctx) error {
 		return response.Error(ctx, apiErr, nil)
 	}
 
-	return response.Success(ctx, fiber.StatusOK, "Aksimu berhasil diproses!", resp)
+	return response.Success(ctx, fiber.StatusOK, i18n.StoryActionProcessed, resp)
+}
+
+// slotQuery reads the optional ?slot= query, defaulting to the main run.
+func slotQuery(ctx *fiber.Ctx) (int, *response.APIError) {
+	raw := ctx.Query("slot")
+	if raw == "" {
+		return entity.DefaultSaveSlot, nil
+	}
+	return parseSlot("slot", raw)
+}
+
+func parseSlot(field, raw string) (int, *response.APIError) {
+	slot, err := strconv.Atoi(raw)
+	if err != nil || slot < 1 || slot > entity.MaxSaveSlots {
+		return 0, response.NewParamValidationError(field, "slot")
+	}
+	return slot, nil
+}
+
+func checkSubtitle(lang string) *response.APIError {
+	if lang != "" && !entity.IsSubtitleLanguage(lang) {
+		return response.NewParamValidationError("subtitle", "oneof")
+	}
+	return nil
 }
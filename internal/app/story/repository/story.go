@@ -2,11 +2,15 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"strings"
 	"time"
 
 	"github.com/Ablebil/lathi-be/internal/domain/contract"
 	"github.com/Ablebil/lathi-be/internal/domain/entity"
+	"github.com/Ablebil/lathi-be/internal/domain/types"
+	"github.com/Ablebil/lathi-be/internal/infra/postgresql"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
@@ -22,58 +26,175 @@ func NewStoryRepository(db *gorm.DB) contract.StoryRepositoryItf {
 	}
 }
 
+// GetAllChapters lists the published chapters of every story, story by story,
+// with the title, description and cover of their published version, and their
+// release time and unlock rules.
 func (r *storyRepository) GetAllChapters(ctx context.Context) ([]entity.Chapter, error) {
 	var chapters []entity.Chapter
-	err := r.db.WithContext(ctx).Order("order_index ASC").Find(&chapters).Error
+	err := postgresql.Conn(ctx, r.db).Table("chapters AS c").
+		Joins("JOIN chapter_versions cv ON cv.id = c.published_version_id").
+		Joins("JOIN stories s ON s.id = c.story_id").
+		Select("c.id, c.story_id, c.order_index, c.published_version_id, c.publish_at, c.unlock, cv.title, cv.description, cv.cover_image_url").
+		Order("s.order_index ASC, c.order_index ASC").
+		Scan(&chapters).Error
 	if err != nil {
 		return nil, err
 	}
 	return chapters, nil
 }
 
-func (r *storyRepository) GetChapterByID(ctx context.Context, id uuid.UUID) (*entity.Chapter, error) {
-	var chapter entity.Chapter
-	err := r.db.WithContext(ctx).
-		Preload("Slides", func(db *gorm.DB) *gorm.DB {
-			return db.Order("id ASC")
-		}).
-		Preload("Slides.Vocabularies").
-		Where("id = ?", id).
-		First(&chapter).Error
+// GetStories lists the stories that have a published chapter.
+func (r *storyRepository) GetStories(ctx context.Context) ([]entity.Story, error) {
+	var stories []entity.Story
+	err := postgresql.Conn(ctx, r.db).
+		Where("EXISTS (SELECT 1 FROM chapters c WHERE c.story_id = stories.id AND c.published_version_id IS NOT NULL)").
+		Order("order_index ASC").
+		Find(&stories).Error
+	if err != nil {
+		return nil, err
+	}
+	return stories, nil
+}
+
+func (r *storyRepository) GetStory(ctx context.Context, id uuid.UUID) (*entity.Story, error) {
+	var story entity.Story
+	err := postgresql.Conn(ctx, r.db).Where("id = ?", id).First(&story).Error
 
 	if errors.Is(err, gorm.ErrRecordNotFound) {
 		return nil, nil
 	}
+	if err != nil {
+		return nil, err
+	}
+	return &story, nil
+}
 
+// GetStoryProgress returns the user's progress keyed by story, stories the
+// user hasn't completed a chapter of are missing.
+func (r *storyRepository) GetStoryProgress(ctx context.Context, userID uuid.UUID) (map[uuid.UUID]entity.UserStoryProgress, error) {
+	var rows []entity.UserStoryProgress
+	if err := postgresql.Conn(ctx, r.db).Where("user_id = ?", userID).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	progress := make(map[uuid.UUID]entity.UserStoryProgress, len(rows))
+	for _, p := range rows {
+		progress[p.StoryID] = p
+	}
+	return progress, nil
+}
+
+// GetCompletedChapters returns the IDs of the chapters the user has completed.
+func (r *storyRepository) GetCompletedChapters(ctx context.Context, userID uuid.UUID) (map[uuid.UUID]bool, error) {
+	var ids []uuid.UUID
+	err := postgresql.Conn(ctx, r.db).Model(&entity.UserChapterCompletion{}).
+		Where("user_id = ?", userID).
+		Pluck("chapter_id", &ids).Error
 	if err != nil {
 		return nil, err
 	}
 
-	return &chapter, nil
+	completed := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		completed[id] = true
+	}
+	return completed, nil
+}
+
+// GetCompletedOrderIndexes returns the order indexes of the chapters the user
+// has completed in the story of the chapter.
+func (r *storyRepository) GetCompletedOrderIndexes(ctx context.Context, userID, chapterID uuid.UUID) (map[int]bool, error) {
+	var indexes []int
+	err := postgresql.Conn(ctx, r.db).Table("user_chapter_completions AS ucc").
+		Joins("JOIN chapters c ON c.id = ucc.chapter_id").
+		Where("ucc.user_id = ? AND c.story_id = (SELECT story_id FROM chapters WHERE id = ?)", userID, chapterID).
+		Pluck("c.order_index", &indexes).Error
+	if err != nil {
+		return nil, err
+	}
+
+	completed := make(map[int]bool, len(indexes))
+	for _, i := range indexes {
+		completed[i] = true
+	}
+	return completed, nil
+}
+
+// RecordChapterCompletion marks the chapter as completed by the user,
+// reporting whether it is the first time.
+func (r *storyRepository) RecordChapterCompletion(ctx context.Context, userID, chapterID uuid.UUID) (bool, error) {
+	result := postgresql.Conn(ctx, r.db).Clauses(clause.OnConflict{
+		DoNothing: true,
+	}).Create(&entity.UserChapterCompletion{
+		UserID:    userID,
+		ChapterID: chapterID,
+	})
+
+	return result.RowsAffected > 0, result.Error
 }
 
-func (r *storyRepository) GetSlideByID(ctx context.Context, id uuid.UUID) (*entity.Slide, error) {
-	var slide entity.Slide
-	err := r.db.WithContext(ctx).
-		Preload("Vocabularies").
-		Where("id = ?", id).
-		First(&slide).Error
+// UpdateStoryTitle sets the user's title in the story, creating their
+// progress in it on the first completed chapter.
+func (r *storyRepository) UpdateStoryTitle(ctx context.Context, userID, storyID uuid.UUID, title entity.Title) error {
+	return postgresql.Conn(ctx, r.db).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "story_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"title", "updated_at"}),
+	}).Create(&entity.UserStoryProgress{
+		UserID:  userID,
+		StoryID: storyID,
+		Title:   title,
+	}).Error
+}
+
+// GetChapterMeta loads the chapter without its slides.
+func (r *storyRepository) GetChapterMeta(ctx context.Context, id uuid.UUID) (*entity.Chapter, error) {
+	var chapter entity.Chapter
+	err := postgresql.Conn(ctx, r.db).Where("id = ?", id).First(&chapter).Error
 
 	if errors.Is(err, gorm.ErrRecordNotFound) {
 		return nil, nil
 	}
+	if err != nil {
+		return nil, err
+	}
+	return &chapter, nil
+}
+
+// GetPublishedVersion loads the version of the chapter players currently get,
+// nil when the chapter doesn't exist or was never published.
+func (r *storyRepository) GetPublishedVersion(ctx context.Context, chapterID uuid.UUID) (*entity.ChapterVersion, error) {
+	var version entity.ChapterVersion
+	err := postgresql.Conn(ctx, r.db).
+		Joins("JOIN chapters c ON c.published_version_id = chapter_versions.id").
+		Where("c.id = ?", chapterID).
+		First(&version).Error
 
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
 	if err != nil {
 		return nil, err
 	}
+	return &version, nil
+}
+
+func (r *storyRepository) GetChapterVersion(ctx context.Context, id uuid.UUID) (*entity.ChapterVersion, error) {
+	var version entity.ChapterVersion
+	err := postgresql.Conn(ctx, r.db).Where("id = ?", id).First(&version).Error
 
-	return &slide, nil
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &version, nil
 }
 
-func (r *storyRepository) FindSession(ctx context.Context, userID, chapterID uuid.UUID) (*entity.UserStorySession, error) {
+func (r *storyRepository) FindSession(ctx context.Context, userID, chapterID uuid.UUID, slot int) (*entity.UserStorySession, error) {
 	var session entity.UserStorySession
-	err := r.db.WithContext(ctx).
-		Where("user_id = ? AND chapter_id = ?", userID, chapterID).
+	err := postgresql.Conn(ctx, r.db).
+		Where("user_id = ? AND chapter_id = ? AND slot = ?", userID, chapterID, slot).
 		First(&session).Error
 
 	if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -85,16 +206,231 @@ func (r *storyRepository) FindSession(ctx context.Context, userID, chapterID uui
 	return &session, nil
 }
 
+func (r *storyRepository) ListSessions(ctx context.Context, userID, chapterID uuid.UUID) ([]entity.UserStorySession, error) {
+	var sessions []entity.UserStorySession
+	err := postgresql.Conn(ctx, r.db).
+		Where("user_id = ? AND chapter_id = ?", userID, chapterID).
+		Order("slot ASC").
+		Find(&sessions).Error
+	if err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
 func (r *storyRepository) CreateSession(ctx context.Context, session *entity.UserStorySession) error {
 	// upsert session
-	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
-		Columns:   []clause.Column{{Name: "user_id"}, {Name: "chapter_id"}},
-		DoUpdates: clause.AssignmentColumns([]string{"current_slide_id", "current_hearts", "is_game_over", "is_completed", "history_log", "updated_at"}),
+	// a restart bumps the version so actions from the previous run can't be replayed,
+	// and hides the history of the previous run without deleting it
+	updates := clause.AssignmentColumns([]string{"current_slide_id", "current_hearts", "is_game_over", "is_completed", "variables", "ending_id", "chapter_version_id", "checkpoint_slide_id", "checkpoint_hearts", "checkpoint_variables", "updated_at"})
+	updates = append(updates, clause.Assignment{
+		Column: clause.Column{Name: "version"},
+		Value:  gorm.Expr("user_story_sessions.version + 1"),
+	}, clause.Assignment{
+		Column: clause.Column{Name: "history_from"},
+		Value:  gorm.Expr("(SELECT COALESCE(MAX(sequence), 0) FROM story_events WHERE session_id = user_story_sessions.id)"),
+	})
+
+	return postgresql.Conn(ctx, r.db).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "chapter_id"}, {Name: "slot"}},
+		DoUpdates: updates,
 	}).Create(session).Error
 }
 
-func (r *storyRepository) UpdateSession(ctx context.Context, session *entity.UserStorySession) error {
-	return r.db.WithContext(ctx).Save(session).Error
+// InsertSession creates the session only if its slot is free, reporting false
+// when the slot is already taken.
+func (r *storyRepository) InsertSession(ctx context.Context, session *entity.UserStorySession) (bool, error) {
+	result := postgresql.Conn(ctx, r.db).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "chapter_id"}, {Name: "slot"}},
+		DoNothing: true,
+	}).Create(session)
+
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *storyRepository) DeleteSession(ctx context.Context, userID, chapterID uuid.UUID, slot int) (bool, error) {
+	result := postgresql.Conn(ctx, r.db).
+		Where("user_id = ? AND chapter_id = ? AND slot = ?", userID, chapterID, slot).
+		Delete(&entity.UserStorySession{})
+
+	return result.RowsAffected > 0, result.Error
+}
+
+// UpdateSession writes the session only if nobody changed it since it was read,
+// reporting false when the version no longer matches.
+func (r *storyRepository) UpdateSession(ctx context.Context, session *entity.UserStorySession) (bool, error) {
+	result := postgresql.Conn(ctx, r.db).
+		Model(&entity.UserStorySession{}).
+		Where("id = ? AND version = ?", session.ID, session.Version).
+		Updates(map[string]interface{}{
+			"current_slide_id":     session.CurrentSlideID,
+			"current_hearts":       session.CurrentHearts,
+			"is_game_over":         session.IsGameOver,
+			"is_completed":         session.IsCompleted,
+			"variables":            session.Variables,
+			"ending_id":            session.EndingID,
+			"checkpoint_slide_id":  session.CheckpointSlideID,
+			"checkpoint_hearts":    session.CheckpointHearts,
+			"checkpoint_variables": session.CheckpointVariables,
+			"version":              session.Version + 1,
+			"updated_at":           session.UpdatedAt,
+		})
+
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+
+	session.Version++
+	return true, nil
+}
+
+func (r *storyRepository) SaveAction(ctx context.Context, action *entity.UserStoryAction) error {
+	return postgresql.Conn(ctx, r.db).Create(action).Error
+}
+
+func (r *storyRepository) FindAction(ctx context.Context, sessionID uuid.UUID, version int) (*entity.UserStoryAction, error) {
+	var action entity.UserStoryAction
+	err := postgresql.Conn(ctx, r.db).
+		Where("session_id = ? AND version = ?", sessionID, version).
+		First(&action).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &action, nil
+}
+
+// AppendEvents numbers the events after the last one of the session and
+// stores them. It runs after the session update of an action, whose row lock
+// keeps concurrent appends to the same session apart.
+func (r *storyRepository) AppendEvents(ctx context.Context, sessionID uuid.UUID, events []entity.StoryEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	db := postgresql.Conn(ctx, r.db)
+
+	var last int
+	err := db.Model(&entity.StoryEvent{}).
+		Where("session_id = ?", sessionID).
+		Select("COALESCE(MAX(sequence), 0)").
+		Scan(&last).Error
+	if err != nil {
+		return err
+	}
+
+	for i := range events {
+		events[i].SessionID = sessionID
+		events[i].Sequence = last + i + 1
+	}
+
+	return db.Create(&events).Error
+}
+
+// ListEvents returns the events of the session's current run, newest first.
+func (r *storyRepository) ListEvents(ctx context.Context, sessionID uuid.UUID, after, limit, offset int) ([]entity.StoryEvent, int64, error) {
+	var events []entity.StoryEvent
+	var total int64
+	query := postgresql.Conn(ctx, r.db).Model(&entity.StoryEvent{}).
+		Where("session_id = ? AND sequence > ?", sessionID, after)
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.
+		Order("sequence DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&events).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return events, total, nil
+}
+
+// GetReachedSlideIDs returns every slide the session has acted on, over all of
+// the slot's runs.
+func (r *storyRepository) GetReachedSlideIDs(ctx context.Context, sessionID uuid.UUID) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := postgresql.Conn(ctx, r.db).Model(&entity.StoryEvent{}).
+		Where("session_id = ? AND slide_id IS NOT NULL", sessionID).
+		Distinct().
+		Pluck("slide_id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// CopyEvents copies the current run's events of one session to the start of
+// another session's history.
+func (r *storyRepository) CopyEvents(ctx context.Context, fromSessionID uuid.UUID, after int, toSessionID uuid.UUID) error {
+	db := postgresql.Conn(ctx, r.db)
+
+	var events []entity.StoryEvent
+	err := db.Where("session_id = ? AND sequence > ?", fromSessionID, after).
+		Order("sequence ASC").
+		Find(&events).Error
+	if err != nil || len(events) == 0 {
+		return err
+	}
+
+	for i := range events {
+		events[i].ID = uuid.Nil
+		events[i].SessionID = toSessionID
+		events[i].Sequence = i + 1
+	}
+
+	return db.Create(&events).Error
+}
+
+func (r *storyRepository) GetUserVariables(ctx context.Context, userID uuid.UUID) (types.Variables, error) {
+	var uv entity.UserStoryVariables
+	err := postgresql.Conn(ctx, r.db).Where("user_id = ?", userID).First(&uv).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return types.Variables{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return uv.Variables, nil
+}
+
+// LockUserVariables reads the user variables and locks the row until the
+// surrounding transaction ends, creating it when missing.
+func (r *storyRepository) LockUserVariables(ctx context.Context, userID uuid.UUID) (types.Variables, error) {
+	db := postgresql.Conn(ctx, r.db)
+	err := db.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&entity.UserStoryVariables{UserID: userID, Variables: types.Variables{}}).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var uv entity.UserStoryVariables
+	err = db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("user_id = ?", userID).
+		First(&uv).Error
+	if err != nil {
+		return nil, err
+	}
+	return uv.Variables, nil
+}
+
+func (r *storyRepository) SaveUserVariables(ctx context.Context, userID uuid.UUID, vars types.Variables) error {
+	return postgresql.Conn(ctx, r.db).Model(&entity.UserStoryVariables{}).
+		Where("user_id = ?", userID).
+		Updates(map[string]interface{}{
+			"variables":  vars,
+			"updated_at": time.Now(),
+		}).Error
 }
 
 func (r *storyRepository) UnlockVocabularies(ctx context.Context, userID uuid.UUID, vocabIDs []uuid.UUID) (int64, error) {
@@ -111,15 +447,273 @@ func (r *storyRepository) UnlockVocabularies(ctx context.Context, userID uuid.UU
 		}
 	}
 
-	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+	result := postgresql.Conn(ctx, r.db).Clauses(clause.OnConflict{
 		DoNothing: true,
 	}).Create(&userVocabs)
 
 	return result.RowsAffected, result.Error
 }
 
+// GetUnlockedWords reports which of the given krama words the user has
+// unlocked, keyed by the lower cased word.
+func (r *storyRepository) GetUnlockedWords(ctx context.Context, userID uuid.UUID, words []string) (map[string]bool, error) {
+	unlocked := make(map[string]bool, len(words))
+	if len(words) == 0 {
+		return unlocked, nil
+	}
+
+	lower := make([]string, len(words))
+	for i, w := range words {
+		lower[i] = strings.ToLower(w)
+	}
+
+	var found []string
+	err := postgresql.Conn(ctx, r.db).Table("dictionaries AS d").
+		Joins("JOIN user_vocabularies uv ON d.id = uv.dictionary_id AND uv.user_id = ?", userID).
+		Where("LOWER(d.word_krama) IN ?", lower).
+		Pluck("LOWER(d.word_krama)", &found).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, w := range found {
+		unlocked[w] = true
+	}
+	return unlocked, nil
+}
+
+func (r *storyRepository) GetWordsByIDs(ctx context.Context, ids []uuid.UUID) ([]entity.Dictionary, error) {
+	var words []entity.Dictionary
+	if len(ids) == 0 {
+		return words, nil
+	}
+
+	err := postgresql.Conn(ctx, r.db).Where("id IN ?", ids).Find(&words).Error
+	if err != nil {
+		return nil, err
+	}
+	return words, nil
+}
+
+// RecordWordResult counts a quiz answer for the word.
+func (r *storyRepository) RecordWordResult(ctx context.Context, userID, dictionaryID uuid.UUID, correct bool) error {
+	result := entity.UserWordResult{
+		UserID:         userID,
+		DictionaryID:   dictionaryID,
+		LastAnsweredAt: time.Now(),
+	}
+	column := "wrong_count"
+	if correct {
+		result.CorrectCount = 1
+		column = "correct_count"
+	} else {
+		result.WrongCount = 1
+	}
+
+	return postgresql.Conn(ctx, r.db).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}, {Name: "dictionary_id"}},
+		DoUpdates: clause.Set{
+			{Column: clause.Column{Name: column}, Value: gorm.Expr("user_word_results." + column + " + 1")},
+			{Column: clause.Column{Name: "last_answered_at"}, Value: result.LastAnsweredAt},
+		},
+	}).Create(&result).Error
+}
+
+// CountChapters counts the published chapters.
 func (r *storyRepository) CountChapters(ctx context.Context) (int64, error) {
 	var count int64
-	err := r.db.WithContext(ctx).Model(&entity.Chapter{}).Count(&count).Error
+	err := postgresql.Conn(ctx, r.db).Model(&entity.Chapter{}).
+		Where("published_version_id IS NOT NULL").
+		Count(&count).Error
 	return count, err
 }
+
+type storyCount struct {
+	StoryID uuid.UUID
+	Total   int
+}
+
+// CountChaptersByStory counts the published chapters of each story.
+func (r *storyRepository) CountChaptersByStory(ctx context.Context) (map[uuid.UUID]int, error) {
+	var rows []storyCount
+	err := postgresql.Conn(ctx, r.db).Model(&entity.Chapter{}).
+		Where("published_version_id IS NOT NULL").
+		Select("story_id, COUNT(*) AS total").
+		Group("story_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[uuid.UUID]int, len(rows))
+	for _, row := range rows {
+		counts[row.StoryID] = row.Total
+	}
+	return counts, nil
+}
+
+// CountCompletedChaptersByStory counts the published chapters of each story
+// the user has completed.
+func (r *storyRepository) CountCompletedChaptersByStory(ctx context.Context, userID uuid.UUID) (map[uuid.UUID]int, error) {
+	var rows []storyCount
+	err := postgresql.Conn(ctx, r.db).Table("user_chapter_completions AS ucc").
+		Joins("JOIN chapters c ON c.id = ucc.chapter_id").
+		Where("ucc.user_id = ? AND c.published_version_id IS NOT NULL", userID).
+		Select("c.story_id, COUNT(*) AS total").
+		Group("c.story_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[uuid.UUID]int, len(rows))
+	for _, row := range rows {
+		counts[row.StoryID] = row.Total
+	}
+	return counts, nil
+}
+
+// RecordEnding marks the ending as reached by the user, reporting whether it
+// is the first time.
+func (r *storyRepository) RecordEnding(ctx context.Context, userID, endingID uuid.UUID) (bool, error) {
+	result := postgresql.Conn(ctx, r.db).Clauses(clause.OnConflict{
+		DoNothing: true,
+	}).Create(&entity.UserEnding{
+		UserID:   userID,
+		EndingID: endingID,
+	})
+
+	return result.RowsAffected > 0, result.Error
+}
+
+// GetReachedEndings reports which of the endings the user has reached.
+func (r *storyRepository) GetReachedEndings(ctx context.Context, userID uuid.UUID, endingIDs []uuid.UUID) (map[uuid.UUID]bool, error) {
+	reached := make(map[uuid.UUID]bool)
+	if len(endingIDs) == 0 {
+		return reached, nil
+	}
+
+	var ids []uuid.UUID
+	err := postgresql.Conn(ctx, r.db).Model(&entity.UserEnding{}).
+		Where("user_id = ? AND ending_id IN ?", userID, endingIDs).
+		Pluck("ending_id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range ids {
+		reached[id] = true
+	}
+	return reached, nil
+}
+
+type endingCount struct {
+	ChapterID uuid.UUID
+	Total     int
+}
+
+// CountEndingsByChapter counts the endings of each chapter's published version.
+func (r *storyRepository) CountEndingsByChapter(ctx context.Context) (map[uuid.UUID]int, error) {
+	var rows []endingCount
+	err := postgresql.Conn(ctx, r.db).Table("chapters AS c").
+		Joins("JOIN chapter_versions cv ON cv.id = c.published_version_id").
+		Select("c.id AS chapter_id, jsonb_array_length(cv.content->'endings') AS total").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return toCountMap(rows), nil
+}
+
+// CountUserEndingsByChapter counts the endings of each chapter's published
+// version the user has reached, so it never disagrees with CountEndingsByChapter
+// while a draft is being edited.
+func (r *storyRepository) CountUserEndingsByChapter(ctx context.Context, userID uuid.UUID) (map[uuid.UUID]int, error) {
+	var rows []endingCount
+	err := postgresql.Conn(ctx, r.db).Table("chapters AS c").
+		Joins("JOIN chapter_versions cv ON cv.id = c.published_version_id").
+		Joins("CROSS JOIN LATERAL jsonb_array_elements(cv.content->'endings') AS e").
+		Joins("JOIN user_endings ue ON ue.ending_id = e->>'id' AND ue.user_id = ?", userID).
+		Select("c.id AS chapter_id, COUNT(*) AS total").
+		Group("c.id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return toCountMap(rows), nil
+}
+
+func toCountMap(rows []endingCount) map[uuid.UUID]int {
+	counts := make(map[uuid.UUID]int, len(rows))
+	for _, row := range rows {
+		counts[row.ChapterID] = row.Total
+	}
+	return counts
+}
+
+func (r *storyRepository) CreateAttempt(ctx context.Context, attempt *entity.StoryAttempt) error {
+	return postgresql.Conn(ctx, r.db).Create(attempt).Error
+}
+
+// AdvanceAttempt adds an action to the attempt in progress in the save slot.
+// Attempts that already ended are never touched, and a slot without an
+// attempt in progress is left alone.
+func (r *storyRepository) AdvanceAttempt(ctx context.Context, userID, chapterID uuid.UUID, slot int, step entity.AttemptStep) error {
+	updates := map[string]interface{}{
+		"hearts_lost": gorm.Expr("hearts_lost + ?", step.HeartsLost),
+	}
+	if step.Choice != nil {
+		choice, err := json.Marshal([]entity.AttemptChoice{*step.Choice})
+		if err != nil {
+			return err
+		}
+		updates["choices"] = gorm.Expr("choices || ?::jsonb", string(choice))
+	}
+	if step.Outcome != "" {
+		updates["outcome"] = step.Outcome
+		updates["ending_id"] = step.EndingID
+		updates["ended_at"] = time.Now()
+	}
+
+	return postgresql.Conn(ctx, r.db).
+		Model(&entity.StoryAttempt{}).
+		Where("user_id = ? AND chapter_id = ? AND slot = ? AND ended_at IS NULL", userID, chapterID, slot).
+		Updates(updates).Error
+}
+
+func (r *storyRepository) ListAttempts(ctx context.Context, userID, chapterID uuid.UUID, limit, offset int) ([]entity.StoryAttempt, int64, error) {
+	var attempts []entity.StoryAttempt
+	var total int64
+	query := postgresql.Conn(ctx, r.db).Model(&entity.StoryAttempt{}).
+		Where("user_id = ? AND chapter_id = ?", userID, chapterID)
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.
+		Preload("Ending").
+		Order("started_at DESC, id DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&attempts).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return attempts, total, nil
+}
+
+func (r *storyRepository) GetAttemptStats(ctx context.Context, userID uuid.UUID) (*entity.AttemptStats, error) {
+	var stats entity.AttemptStats
+	err := postgresql.Conn(ctx, r.db).Model(&entity.StoryAttempt{}).
+		Where("user_id = ?", userID).
+		Select("COUNT(*) AS total, "+
+			"COUNT(*) FILTER (WHERE outcome = ?) AS completed, "+
+			"COUNT(*) FILTER (WHERE outcome = ?) AS game_over",
+			entity.AttemptCompleted, entity.AttemptGameOver).
+		Scan(&stats).Error
+	if err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
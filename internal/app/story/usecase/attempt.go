@@ -0,0 +1,114 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"math"
+
+	"github.com/Ablebil/lathi-be/internal/domain/dto"
+	"github.com/Ablebil/lathi-be/internal/domain/entity"
+	"github.com/Ablebil/lathi-be/pkg/i18n"
+	"github.com/Ablebil/lathi-be/pkg/response"
+	"github.com/google/uuid"
+)
+
+func (uc *storyUsecase) ListAttempts(ctx context.Context, userID, chapterID uuid.UUID, req *dto.AttemptListRequest) (*dto.AttemptListResponse, *response.APIError) {
+	page := req.Page
+	if page < 0 {
+		return nil, response.ErrBadRequest(i18n.CommonInvalidPage)
+	} else if page < 1 {
+		page = 1
+	}
+
+	limit := req.Limit
+	if limit < 0 {
+		return nil, response.ErrBadRequest(i18n.CommonInvalidLimit)
+	} else if limit < 1 {
+		limit = uc.env.DefaultPageLimit
+	} else if limit > uc.env.MaxPageLimit {
+		limit = uc.env.MaxPageLimit
+	}
+
+	offset := (page - 1) * limit
+
+	attempts, total, err := uc.storyRepo.ListAttempts(ctx, userID, chapterID, limit, offset)
+	if err != nil {
+		slog.Error("failed to list attempts", "error", err)
+		return nil, response.ErrInternal(i18n.CommonTryAgain)
+	}
+
+	items := make([]dto.AttemptResponse, 0, len(attempts))
+	for i := range attempts {
+		item, err := toAttemptResponse(&attempts[i])
+		if err != nil {
+			slog.Error("failed to parse attempt choices", "error", err, "attempt_id", attempts[i].ID)
+			return nil, response.ErrInternal(i18n.CommonTryAgain)
+		}
+		items = append(items, item)
+	}
+
+	return &dto.AttemptListResponse{
+		Items: items,
+		Pagination: dto.PaginationMeta{
+			CurrentPage:  page,
+			TotalPage:    int(math.Ceil(float64(total) / float64(limit))),
+			TotalItems:   total,
+			ItemsPerPage: limit,
+		},
+	}, nil
+}
+
+// startAttempt opens a new attempt in the save slot. An attempt still in
+// progress there was cut short by the restart and is closed as abandoned.
+// Callers run it in the same transaction as the session write.
+func (uc *storyUsecase) startAttempt(ctx context.Context, userID, chapterID uuid.UUID, slot int, origin entity.AttemptOrigin) error {
+	if err := uc.storyRepo.AdvanceAttempt(ctx, userID, chapterID, slot, entity.AttemptStep{Outcome: entity.AttemptAbandoned}); err != nil {
+		return err
+	}
+
+	return uc.storyRepo.CreateAttempt(ctx, &entity.StoryAttempt{
+		UserID:    userID,
+		ChapterID: chapterID,
+		Slot:      slot,
+		Origin:    origin,
+		Outcome:   entity.AttemptInProgress,
+		Choices:   []byte("[]"),
+	})
+}
+
+func toAttemptResponse(attempt *entity.StoryAttempt) (dto.AttemptResponse, error) {
+	var choices []entity.AttemptChoice
+	if len(attempt.Choices) > 0 {
+		if err := json.Unmarshal(attempt.Choices, &choices); err != nil {
+			return dto.AttemptResponse{}, err
+		}
+	}
+
+	resp := dto.AttemptResponse{
+		ID:         attempt.ID,
+		Slot:       attempt.Slot,
+		Origin:     string(attempt.Origin),
+		Outcome:    string(attempt.Outcome),
+		HeartsLost: attempt.HeartsLost,
+		Choices:    make([]dto.AttemptChoiceResponse, 0, len(choices)),
+		StartedAt:  attempt.StartedAt,
+		EndedAt:    attempt.EndedAt,
+	}
+	for _, c := range choices {
+		resp.Choices = append(resp.Choices, dto.AttemptChoiceResponse{
+			SlideID:     c.SlideID,
+			ChoiceIndex: c.ChoiceIndex,
+			Text:        c.Text,
+		})
+	}
+	if attempt.Ending != nil {
+		resp.Ending = &dto.AttemptEndingResponse{
+			ID:    attempt.Ending.ID,
+			Title: attempt.Ending.Title,
+			Kind:  string(attempt.Ending.Kind),
+		}
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,147 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+	"math"
+
+	"github.com/Ablebil/lathi-be/internal/domain/dto"
+	"github.com/Ablebil/lathi-be/internal/domain/entity"
+	"github.com/Ablebil/lathi-be/pkg/i18n"
+	"github.com/Ablebil/lathi-be/pkg/response"
+	"github.com/google/uuid"
+)
+
+// library is the user's view of every published story and chapter, what the
+// story and chapter lists are built from.
+type library struct {
+	stories      []entity.Story
+	chapters     []entity.Chapter // story by story
+	progress     map[uuid.UUID]entity.UserStoryProgress
+	completed    map[uuid.UUID]bool
+	locks        map[uuid.UUID]*chapterLock
+	totalEndings map[uuid.UUID]int
+	foundEndings map[uuid.UUID]int
+}
+
+func (uc *storyUsecase) loadLibrary(ctx context.Context, userID uuid.UUID) (*library, error) {
+	lib := &library{}
+
+	var err error
+	if lib.stories, err = uc.storyRepo.GetStories(ctx); err != nil {
+		return nil, err
+	}
+	if lib.chapters, err = uc.storyRepo.GetAllChapters(ctx); err != nil {
+		return nil, err
+	}
+	if lib.progress, err = uc.storyRepo.GetStoryProgress(ctx, userID); err != nil {
+		return nil, err
+	}
+	if lib.completed, err = uc.storyRepo.GetCompletedChapters(ctx, userID); err != nil {
+		return nil, err
+	}
+	if lib.totalEndings, err = uc.storyRepo.CountEndingsByChapter(ctx); err != nil {
+		return nil, err
+	}
+	if lib.foundEndings, err = uc.storyRepo.CountUserEndingsByChapter(ctx, userID); err != nil {
+		return nil, err
+	}
+	if lib.locks, err = uc.chapterLocks(ctx, userID, lib.chapters, lib.completed); err != nil {
+		return nil, err
+	}
+	return lib, nil
+}
+
+func (uc *storyUsecase) GetStoryList(ctx context.Context, userID uuid.UUID) ([]dto.StoryListResponse, *response.APIError) {
+	lib, err := uc.loadLibrary(ctx, userID)
+	if err != nil {
+		slog.Error("failed to load stories", "error", err)
+		return nil, response.ErrInternal(i18n.CommonTryAgain)
+	}
+
+	resp := make([]dto.StoryListResponse, 0, len(lib.stories))
+	for i := range lib.stories {
+		resp = append(resp, uc.toStoryItem(lib, &lib.stories[i]))
+	}
+	return resp, nil
+}
+
+func (uc *storyUsecase) GetStoryDetail(ctx context.Context, userID, storyID uuid.UUID) (*dto.StoryDetailResponse, *response.APIError) {
+	lib, err := uc.loadLibrary(ctx, userID)
+	if err != nil {
+		slog.Error("failed to load stories", "error", err)
+		return nil, response.ErrInternal(i18n.CommonTryAgain)
+	}
+
+	for i := range lib.stories {
+		if lib.stories[i].ID == storyID {
+			return &dto.StoryDetailResponse{
+				StoryListResponse: uc.toStoryItem(lib, &lib.stories[i]),
+				Chapters:          uc.toChapterItems(lib, storyID),
+			}, nil
+		}
+	}
+	return nil, response.ErrNotFound(i18n.StoryBookNotFound)
+}
+
+func (uc *storyUsecase) toStoryItem(lib *library, story *entity.Story) dto.StoryListResponse {
+	resp := dto.StoryListResponse{
+		ID:            story.ID,
+		Slug:          story.Slug,
+		Title:         story.Title,
+		Description:   story.Description,
+		CoverImageURL: uc.storage.GetObjectURL(story.CoverImageURL),
+		OrderIndex:    story.OrderIndex,
+		IsMain:        story.IsMain(),
+		PlayerTitle:   string(entity.Cantrik),
+	}
+	if p, ok := lib.progress[story.ID]; ok {
+		resp.PlayerTitle = string(p.Title)
+	}
+
+	for _, ch := range lib.chapters {
+		if ch.StoryID != story.ID {
+			continue
+		}
+		if resp.TotalChapters == 0 {
+			resp.IsLocked = lib.locks[ch.ID].isLocked()
+		}
+		resp.TotalChapters++
+		if lib.completed[ch.ID] {
+			resp.CompletedChapters++
+		}
+	}
+
+	if resp.TotalChapters > 0 {
+		resp.IsCompleted = resp.CompletedChapters == resp.TotalChapters
+		progress := (float64(resp.CompletedChapters) / float64(resp.TotalChapters)) * 100
+		resp.ProgressPercent = math.Round(progress*100) / 100
+	}
+	return resp
+}
+
+func (uc *storyUsecase) toChapterItems(lib *library, storyID uuid.UUID) []dto.ChapterListReponse {
+	var resp []dto.ChapterListReponse
+	for _, ch := range lib.chapters {
+		if ch.StoryID != storyID {
+			continue
+		}
+
+		lock := lib.locks[ch.ID]
+		resp = append(resp, dto.ChapterListReponse{
+			ID:            ch.ID,
+			StoryID:       ch.StoryID,
+			Title:         ch.Title,
+			Description:   ch.Description,
+			CoverImageURL: uc.storage.GetObjectURL(ch.CoverImageURL),
+			OrderIndex:    ch.OrderIndex,
+			IsLocked:      lock.isLocked(),
+			IsCompleted:   lib.completed[ch.ID],
+			EndingsFound:  lib.foundEndings[ch.ID],
+			TotalEndings:  lib.totalEndings[ch.ID],
+			AvailableAt:   lock.availableAt,
+			Requirements:  toRequirementResponse(lock),
+		})
+	}
+	return resp
+}
@@ -0,0 +1,137 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/Ablebil/lathi-be/internal/domain/dto"
+	"github.com/Ablebil/lathi-be/internal/domain/entity"
+	"github.com/Ablebil/lathi-be/internal/domain/types"
+	"github.com/google/uuid"
+)
+
+// choice the player can see, keeping its index in the slide so it can be submitted
+type choiceOption struct {
+	Index   int
+	Choice  entity.Choice
+	Enabled bool
+}
+
+// conditionState loads the player state conditions in the chapter are
+// evaluated against. A nil session stands for a chapter that hasn't been
+// started yet.
+func (uc *storyUsecase) conditionState(ctx context.Context, userID, chapterID uuid.UUID, session *entity.UserStorySession, words []string) (*entity.ConditionState, error) {
+	st := &entity.ConditionState{
+		Hearts:    3,
+		Variables: types.Variables{},
+	}
+	if session != nil {
+		st.Hearts = session.CurrentHearts
+		st.Variables = session.Variables
+	}
+
+	var err error
+	if st.UserVariables, err = uc.storyRepo.GetUserVariables(ctx, userID); err != nil {
+		return nil, err
+	}
+	if st.UnlockedWords, err = uc.storyRepo.GetUnlockedWords(ctx, userID, words); err != nil {
+		return nil, err
+	}
+	if st.CompletedChapters, err = uc.storyRepo.GetCompletedOrderIndexes(ctx, userID, chapterID); err != nil {
+		return nil, err
+	}
+
+	return st, nil
+}
+
+// conditionWords collects the vocabulary words referenced by conditions on the slides.
+func conditionWords(slides ...entity.Slide) ([]string, error) {
+	var words []string
+	for _, s := range slides {
+		choices, err := s.GetChoices()
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range choices {
+			words = append(words, c.ShowIf.Words()...)
+			words = append(words, c.EnableIf.Words()...)
+		}
+
+		routes, err := s.GetRoutes()
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range routes {
+			words = append(words, r.If.Words()...)
+		}
+	}
+	return words, nil
+}
+
+// visibleChoices drops choices whose show_if does not hold and marks the ones
+// whose enable_if does not hold as disabled.
+func visibleChoices(choices []entity.Choice, st *entity.ConditionState) []choiceOption {
+	var options []choiceOption
+	for i, c := range choices {
+		if !c.ShowIf.Eval(st) {
+			continue
+		}
+		options = append(options, choiceOption{
+			Index:   i,
+			Choice:  c,
+			Enabled: c.EnableIf.Eval(st),
+		})
+	}
+	return options
+}
+
+func selectableChoices(options []choiceOption) []choiceOption {
+	var res []choiceOption
+	for _, o := range options {
+		if o.Enabled {
+			res = append(res, o)
+		}
+	}
+	return res
+}
+
+// resolveNext picks the first route whose condition holds, falling back to the
+// slide's plain next slide. Routes without a fallback, and choices none of
+// which can be picked without one, are an error rather than the end of the
+// chapter.
+func resolveNext(slide *entity.Slide, st *entity.ConditionState) (*uuid.UUID, error) {
+	routes, err := slide.GetRoutes()
+	if err != nil {
+		return nil, err
+	}
+
+	choices, err := slide.GetChoices()
+	if err != nil {
+		return nil, err
+	}
+	noChoice := len(choices) > 0 && len(selectableChoices(visibleChoices(choices, st))) == 0
+
+	for _, r := range routes {
+		if r.If.Eval(st) {
+			next := r.NextSlideID
+			return &next, nil
+		}
+	}
+
+	if (len(routes) > 0 || noChoice) && slide.NextSlideID == nil {
+		return nil, errNoRoute
+	}
+	return slide.NextSlideID, nil
+}
+
+func toChoiceItems(options []choiceOption, v viewer) []dto.ChoiceItemResponse {
+	var items []dto.ChoiceItemResponse
+	for _, o := range options {
+		items = append(items, dto.ChoiceItemResponse{
+			Index:      o.Index,
+			Text:       plainText(o.Choice.Text, v.player),
+			Subtitle:   v.subtitleOf(o.Choice.Subtitles),
+			IsDisabled: !o.Enabled,
+		})
+	}
+	return items
+}
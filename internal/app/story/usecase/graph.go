@@ -0,0 +1,49 @@
+package usecase
+
+import (
+	"github.com/Ablebil/lathi-be/internal/app/story/graph"
+	"github.com/Ablebil/lathi-be/internal/domain/entity"
+	"github.com/google/uuid"
+)
+
+// orderSlides returns the chapter slides in story order, starting from the
+// chapter start slide instead of primary key order.
+func orderSlides(chapter *entity.Chapter) ([]entity.Slide, error) {
+	g, err := graph.FromChapter(chapter)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[string]entity.Slide, len(chapter.Slides))
+	for _, s := range chapter.Slides {
+		byID[s.ID.String()] = s
+	}
+
+	ordered := make([]entity.Slide, 0, len(chapter.Slides))
+	for _, id := range graph.Order(g) {
+		ordered = append(ordered, byID[id])
+	}
+
+	return ordered, nil
+}
+
+func hasSlide(chapter *entity.Chapter, slideID *uuid.UUID) bool {
+	if slideID == nil {
+		return false
+	}
+	for _, s := range chapter.Slides {
+		if s.ID == *slideID {
+			return true
+		}
+	}
+	return false
+}
+
+func findSlide(chapter *entity.Chapter, slideID uuid.UUID) *entity.Slide {
+	for i := range chapter.Slides {
+		if chapter.Slides[i].ID == slideID {
+			return &chapter.Slides[i]
+		}
+	}
+	return nil
+}
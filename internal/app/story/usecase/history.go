@@ -0,0 +1,78 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+	"math"
+
+	"github.com/Ablebil/lathi-be/internal/domain/dto"
+	"github.com/Ablebil/lathi-be/internal/domain/entity"
+	"github.com/Ablebil/lathi-be/pkg/i18n"
+	"github.com/Ablebil/lathi-be/pkg/response"
+	"github.com/google/uuid"
+)
+
+// GetHistory pages through the dialogue history of the slot's current run,
+// newest entries first. A slot that was never played has an empty history.
+func (uc *storyUsecase) GetHistory(ctx context.Context, userID, chapterID uuid.UUID, slot int, req *dto.HistoryListRequest) (*dto.HistoryListResponse, *response.APIError) {
+	page := req.Page
+	if page < 0 {
+		return nil, response.ErrBadRequest(i18n.CommonInvalidPage)
+	} else if page < 1 {
+		page = 1
+	}
+
+	limit := req.Limit
+	if limit < 0 {
+		return nil, response.ErrBadRequest(i18n.CommonInvalidLimit)
+	} else if limit < 1 {
+		limit = uc.env.DefaultPageLimit
+	} else if limit > uc.env.MaxPageLimit {
+		limit = uc.env.MaxPageLimit
+	}
+
+	offset := (page - 1) * limit
+
+	session, err := uc.storyRepo.FindSession(ctx, userID, chapterID, slot)
+	if err != nil {
+		slog.Error("failed to get session", "error", err)
+		return nil, response.ErrInternal(i18n.CommonTryAgain)
+	}
+
+	var events []entity.StoryEvent
+	var total int64
+	if session != nil {
+		if events, total, err = uc.storyRepo.ListEvents(ctx, session.ID, session.HistoryFrom, limit, offset); err != nil {
+			slog.Error("failed to list story events", "error", err)
+			return nil, response.ErrInternal(i18n.CommonTryAgain)
+		}
+	}
+
+	return &dto.HistoryListResponse{
+		Items: toHistoryEntries(events),
+		Pagination: dto.PaginationMeta{
+			CurrentPage:  page,
+			TotalPage:    int(math.Ceil(float64(total) / float64(limit))),
+			TotalItems:   total,
+			ItemsPerPage: limit,
+		},
+	}, nil
+}
+
+// toHistoryEntries keeps the stored sequence so clients can order and dedupe
+// entries coming from actions and history pages.
+func toHistoryEntries(events []entity.StoryEvent) []dto.HistoryEntry {
+	entries := make([]dto.HistoryEntry, 0, len(events))
+	for _, e := range events {
+		entries = append(entries, dto.HistoryEntry{
+			Sequence:    e.Sequence,
+			Speaker:     e.Speaker,
+			Text:        e.Text,
+			IsUser:      e.IsUser,
+			ChoiceIndex: e.ChoiceIndex,
+			SlideID:     e.SlideID,
+			Timestamp:   e.CreatedAt,
+		})
+	}
+	return entries
+}
@@ -0,0 +1,66 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/Ablebil/lathi-be/internal/domain/dto"
+	"github.com/Ablebil/lathi-be/internal/domain/entity"
+	"github.com/google/uuid"
+)
+
+// viewer is how this player sees a chapter.
+type viewer struct {
+	player   entity.Protagonist
+	subtitle string // subtitle language, empty shows none
+}
+
+// viewer names the chapter's protagonist after the player's display name when
+// they set one. Subtitles follow the requested language, falling back to the
+// player's preference.
+func (uc *storyUsecase) viewer(ctx context.Context, userID uuid.UUID, chapter *entity.Chapter, subtitle string) (viewer, error) {
+	p, err := chapter.Protagonist()
+	if err != nil {
+		return viewer{}, err
+	}
+
+	user, err := uc.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return viewer{}, err
+	}
+	if user != nil {
+		p = p.WithDisplayName(user.DisplayName)
+		if subtitle == "" {
+			subtitle = user.SubtitleLanguage
+		}
+	}
+
+	return viewer{player: p, subtitle: subtitle}, nil
+}
+
+// subtitleOf picks the viewer's subtitle, empty when there is none in their language.
+func (v viewer) subtitleOf(subs entity.Subtitles) string {
+	if v.subtitle == "" {
+		return ""
+	}
+	return v.player.Fill(subs[v.subtitle])
+}
+
+// speaker is the name shown for a slide, with the narrator standing in for
+// slides nobody speaks.
+func speaker(slide *entity.Slide, p entity.Protagonist) string {
+	if slide.SpeakerName == "" {
+		return entity.NarratorName
+	}
+	return p.Fill(slide.SpeakerName)
+}
+
+func (uc *storyUsecase) toProtagonistResponse(p entity.Protagonist) dto.ProtagonistResponse {
+	resp := dto.ProtagonistResponse{
+		Name:    p.Name,
+		Sprites: make(map[string]string, len(p.Sprites)),
+	}
+	for key, img := range p.Sprites {
+		resp.Sprites[key] = uc.storage.GetObjectURL(img)
+	}
+	return resp
+}
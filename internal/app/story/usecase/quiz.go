@@ -0,0 +1,122 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strings"
+
+	"github.com/Ablebil/lathi-be/internal/domain/dto"
+	"github.com/Ablebil/lathi-be/internal/domain/entity"
+	"github.com/google/uuid"
+)
+
+type quizOption struct {
+	Text    string
+	Correct bool
+	order   uint64
+}
+
+// quizWords loads the dictionary words used by the quiz slides, keyed by id.
+func (uc *storyUsecase) quizWords(ctx context.Context, slides ...entity.Slide) (map[uuid.UUID]entity.Dictionary, error) {
+	var ids []uuid.UUID
+	for _, s := range slides {
+		quiz, err := s.GetQuiz()
+		if err != nil {
+			return nil, err
+		}
+		if quiz != nil {
+			ids = append(ids, quiz.WordID)
+			ids = append(ids, quiz.DistractorIDs...)
+		}
+	}
+
+	words, err := uc.storyRepo.GetWordsByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[uuid.UUID]entity.Dictionary, len(words))
+	for _, w := range words {
+		byID[w.ID] = w
+	}
+	return byID, nil
+}
+
+// quizOptions lists the answer options of a quiz. Options with the same text
+// are merged so a distractor can't also be a right answer, and the order is
+// derived from the slide id so every request sees the same options.
+func quizOptions(slideID uuid.UUID, quiz *entity.Quiz, words map[uuid.UUID]entity.Dictionary) (entity.Dictionary, []quizOption, error) {
+	word, ok := words[quiz.WordID]
+	if !ok {
+		return word, nil, fmt.Errorf("quiz word %s not found", quiz.WordID)
+	}
+
+	answer := word.Form(quiz.Answer)
+	options := []quizOption{{Text: answer, Correct: true}}
+	seen := map[string]bool{strings.ToLower(answer): true}
+	for _, id := range quiz.DistractorIDs {
+		d, ok := words[id]
+		if !ok {
+			continue
+		}
+		text := d.Form(quiz.Answer)
+		if text == "" || seen[strings.ToLower(text)] {
+			continue
+		}
+		seen[strings.ToLower(text)] = true
+		options = append(options, quizOption{Text: text})
+	}
+
+	for i := range options {
+		h := fnv.New64a()
+		h.Write(slideID[:])
+		h.Write([]byte(options[i].Text))
+		options[i].order = h.Sum64()
+	}
+	sort.Slice(options, func(i, j int) bool {
+		return options[i].order < options[j].order
+	})
+
+	return word, options, nil
+}
+
+func toQuizResponse(word entity.Dictionary, quiz *entity.Quiz, options []quizOption) *dto.QuizResponse {
+	resp := &dto.QuizResponse{
+		WordID: word.ID,
+		Prompt: word.Form(quiz.Ask),
+		Ask:    string(quiz.Ask),
+		Answer: string(quiz.Answer),
+	}
+	for i, o := range options {
+		resp.Options = append(resp.Options, dto.QuizOptionResponse{
+			Index: i,
+			Text:  o.Text,
+		})
+	}
+	return resp
+}
+
+// gradeQuiz checks the picked option, returning the result and the picked text.
+func gradeQuiz(word entity.Dictionary, quiz *entity.Quiz, options []quizOption, picked int) (*dto.QuizResultResponse, string, bool) {
+	if picked < 0 || picked >= len(options) {
+		return nil, "", false
+	}
+
+	res := &dto.QuizResultResponse{
+		WordID:    word.ID,
+		IsCorrect: options[picked].Correct,
+	}
+	for i, o := range options {
+		if o.Correct {
+			res.CorrectIndex = i
+			res.CorrectAnswer = o.Text
+		}
+	}
+	if !res.IsCorrect {
+		res.HeartsLost = quiz.HeartPenalty
+	}
+
+	return res, options[picked].Text, true
+}
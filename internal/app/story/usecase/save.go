@@ -0,0 +1,176 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"github.com/Ablebil/lathi-be/internal/domain/dto"
+	"github.com/Ablebil/lathi-be/internal/domain/entity"
+	"github.com/Ablebil/lathi-be/pkg/i18n"
+	"github.com/Ablebil/lathi-be/pkg/response"
+	"github.com/google/uuid"
+)
+
+func (uc *storyUsecase) ListSaves(ctx context.Context, userID, chapterID uuid.UUID) ([]dto.SaveSlotResponse, *response.APIError) {
+	sessions, err := uc.storyRepo.ListSessions(ctx, userID, chapterID)
+	if err != nil {
+		slog.Error("failed to list sessions", "error", err)
+		return nil, response.ErrInternal(i18n.CommonTryAgain)
+	}
+
+	resp := make([]dto.SaveSlotResponse, 0, len(sessions))
+	for i := range sessions {
+		resp = append(resp, toSaveSlotResponse(&sessions[i]))
+	}
+
+	return resp, nil
+}
+
+// CreateSave starts a new run in a free slot, or copies the progress of
+// another slot so the player can explore a different branch from there.
+func (uc *storyUsecase) CreateSave(ctx context.Context, userID, chapterID uuid.UUID, req *dto.CreateSaveRequest) (*dto.SaveSlotResponse, *response.APIError) {
+	sessions, err := uc.storyRepo.ListSessions(ctx, userID, chapterID)
+	if err != nil {
+		slog.Error("failed to list sessions", "error", err)
+		return nil, response.ErrInternal(i18n.CommonTryAgain)
+	}
+
+	taken := make(map[int]*entity.UserStorySession, len(sessions))
+	for i := range sessions {
+		taken[sessions[i].Slot] = &sessions[i]
+	}
+
+	slot := 0
+	if req.Slot != nil {
+		slot = *req.Slot
+	} else {
+		for s := 1; s <= entity.MaxSaveSlots; s++ {
+			if taken[s] == nil {
+				slot = s
+				break
+			}
+		}
+		if slot == 0 {
+			return nil, response.ErrConflict(i18n.SaveSlotsFull)
+		}
+	}
+	if taken[slot] != nil {
+		return nil, response.ErrConflict(i18n.SaveSlotTaken)
+	}
+
+	var session, from *entity.UserStorySession
+	origin := entity.OriginStart
+	if req.FromSlot != nil {
+		if from = taken[*req.FromSlot]; from == nil {
+			return nil, response.ErrNotFound(i18n.SaveSourceNotFound)
+		}
+		session = copySession(from, slot)
+		origin = entity.OriginCopy
+	} else {
+		var apiErr *response.APIError
+		if session, apiErr = uc.newSession(ctx, userID, chapterID, slot); apiErr != nil {
+			return nil, apiErr
+		}
+	}
+	session.Name = strings.TrimSpace(req.Name)
+
+	created := false
+	err = uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		if created, err = uc.storyRepo.InsertSession(ctx, session); err != nil || !created {
+			return err
+		}
+		if from != nil {
+			if err := uc.storyRepo.CopyEvents(ctx, from.ID, from.HistoryFrom, session.ID); err != nil {
+				return err
+			}
+		}
+		// a copy of a finished run has nothing left to attempt
+		if session.IsGameOver || session.IsCompleted {
+			return nil
+		}
+		return uc.startAttempt(ctx, userID, chapterID, slot, origin)
+	})
+	if err != nil {
+		slog.Error("failed to create session", "error", err)
+		return nil, response.ErrInternal(i18n.CommonTryAgain)
+	}
+	if !created {
+		return nil, response.ErrConflict(i18n.SaveSlotTaken)
+	}
+
+	resp := toSaveSlotResponse(session)
+	return &resp, nil
+}
+
+func (uc *storyUsecase) LoadSave(ctx context.Context, userID, chapterID uuid.UUID, slot int) (*dto.UserSessionResponse, *response.APIError) {
+	resp, apiErr := uc.GetUserSession(ctx, userID, chapterID, slot)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	if resp == nil {
+		return nil, response.ErrNotFound(i18n.SaveNotFound)
+	}
+	return resp, nil
+}
+
+func (uc *storyUsecase) DeleteSave(ctx context.Context, userID, chapterID uuid.UUID, slot int) *response.APIError {
+	deleted := false
+	err := uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		if deleted, err = uc.storyRepo.DeleteSession(ctx, userID, chapterID, slot); err != nil || !deleted {
+			return err
+		}
+		return uc.storyRepo.AdvanceAttempt(ctx, userID, chapterID, slot, entity.AttemptStep{Outcome: entity.AttemptAbandoned})
+	})
+	if err != nil {
+		slog.Error("failed to delete session", "error", err)
+		return response.ErrInternal(i18n.CommonTryAgain)
+	}
+	if !deleted {
+		return response.ErrNotFound(i18n.SaveNotFound)
+	}
+	return nil
+}
+
+// copySession clones the progress of a run into another slot. Applied actions
+// stay with the original session, so the copy starts a fresh version history.
+func copySession(from *entity.UserStorySession, slot int) *entity.UserStorySession {
+	return &entity.UserStorySession{
+		UserID:              from.UserID,
+		ChapterID:           from.ChapterID,
+		Slot:                slot,
+		CurrentSlideID:      from.CurrentSlideID,
+		CurrentHearts:       from.CurrentHearts,
+		IsGameOver:          from.IsGameOver,
+		IsCompleted:         from.IsCompleted,
+		Variables:           from.Variables.Clone(),
+		EndingID:            from.EndingID,
+		ChapterVersionID:    from.ChapterVersionID,
+		CheckpointSlideID:   from.CheckpointSlideID,
+		CheckpointHearts:    from.CheckpointHearts,
+		CheckpointVariables: from.CheckpointVariables.Clone(),
+	}
+}
+
+// saveSlot defaults an optional slot to the main run.
+func saveSlot(slot *int) int {
+	if slot == nil {
+		return entity.DefaultSaveSlot
+	}
+	return *slot
+}
+
+func toSaveSlotResponse(session *entity.UserStorySession) dto.SaveSlotResponse {
+	return dto.SaveSlotResponse{
+		Slot:           session.Slot,
+		Name:           session.Name,
+		SessionID:      session.ID,
+		CurrentSlideID: session.CurrentSlideID,
+		CurrentHearts:  session.CurrentHearts,
+		IsGameOver:     session.IsGameOver,
+		IsCompleted:    session.IsCompleted,
+		UpdatedAt:      session.UpdatedAt,
+	}
+}
@@ -0,0 +1,76 @@
+package usecase
+
+import (
+	"log/slog"
+	"strings"
+
+	"github.com/Ablebil/lathi-be/internal/domain/dto"
+	"github.com/Ablebil/lathi-be/internal/domain/entity"
+)
+
+// toSegments splits slide content for the client. Vocab segments point to the
+// slide's dictionary entry and stay locked until the player collected the
+// word. Content that doesn't parse, which bundle validation rules out, is sent
+// as a single text segment.
+func toSegments(slide *entity.Slide, unlocked map[string]bool, player entity.Protagonist) []dto.ContentSegmentResponse {
+	segs, err := entity.ParseSegments(slide.Content)
+	if err != nil {
+		slog.Error("failed to parse slide content", "error", err, "slide_id", slide.ID)
+		return []dto.ContentSegmentResponse{{Type: string(entity.SegmentText), Text: player.Fill(slide.Content)}}
+	}
+
+	vocabs := make(map[string]*entity.Dictionary, len(slide.Vocabularies))
+	for i := range slide.Vocabularies {
+		vocabs[strings.ToLower(slide.Vocabularies[i].WordKrama)] = &slide.Vocabularies[i]
+	}
+
+	res := make([]dto.ContentSegmentResponse, 0, len(segs))
+	for _, seg := range segs {
+		item := dto.ContentSegmentResponse{Type: string(seg.Type), Text: seg.Text}
+		switch seg.Type {
+		case entity.SegmentPlayer:
+			item.Text = player.Name
+		case entity.SegmentVocab:
+			word := strings.ToLower(strings.TrimSpace(seg.Text))
+			if v, ok := vocabs[word]; ok {
+				item.VocabID = &v.ID
+				item.IsLocked = !unlocked[word]
+			} else {
+				// marker without a dictionary entry on the slide
+				item.Type = string(entity.SegmentText)
+			}
+		}
+		res = append(res, item)
+	}
+	return res
+}
+
+// plainText is story text as it reads on screen, with markers replaced by
+// their word and the player's name.
+func plainText(s string, player entity.Protagonist) string {
+	segs, err := entity.ParseSegments(s)
+	if err != nil {
+		return player.Fill(s)
+	}
+
+	var b strings.Builder
+	for _, seg := range segs {
+		if seg.Type == entity.SegmentPlayer {
+			b.WriteString(player.Name)
+		} else {
+			b.WriteString(seg.Text)
+		}
+	}
+	return b.String()
+}
+
+// vocabWords collects the krama words attached to the slides.
+func vocabWords(slides []entity.Slide) []string {
+	var words []string
+	for _, s := range slides {
+		for _, v := range s.Vocabularies {
+			words = append(words, v.WordKrama)
+		}
+	}
+	return words
+}
@@ -0,0 +1,226 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"github.com/Ablebil/lathi-be/internal/domain/dto"
+	"github.com/Ablebil/lathi-be/internal/domain/entity"
+	"github.com/google/uuid"
+)
+
+const (
+	defaultPrefetchDepth = 2
+	maxPrefetchDepth     = 5
+)
+
+// slideView is what slides are rendered against for one player.
+type slideView struct {
+	st        *entity.ConditionState
+	quizWords map[uuid.UUID]entity.Dictionary
+	viewer    viewer
+}
+
+// loadSlideView loads the player state the slides of the chapter are rendered
+// against. A nil session stands for a chapter that hasn't been started yet.
+func (uc *storyUsecase) loadSlideView(ctx context.Context, userID, chapterID uuid.UUID, session *entity.UserStorySession, slides []entity.Slide, v viewer) (*slideView, error) {
+	words, err := conditionWords(slides...)
+	if err != nil {
+		return nil, err
+	}
+	// content segments show whether the player collected the slide's words
+	words = append(words, vocabWords(slides)...)
+
+	st, err := uc.conditionState(ctx, userID, chapterID, session, words)
+	if err != nil {
+		return nil, err
+	}
+
+	quizWords, err := uc.quizWords(ctx, slides...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &slideView{st: st, quizWords: quizWords, viewer: v}, nil
+}
+
+func (uc *storyUsecase) toSlideItem(slide *entity.Slide, sv *slideView) dto.SlideItemResponse {
+	player := sv.viewer.player
+
+	var vocabsResp []dto.VocabItemResponse
+	for _, v := range slide.Vocabularies {
+		vocabsResp = append(vocabsResp, dto.VocabItemResponse{
+			ID:        v.ID,
+			WordKrama: v.WordKrama,
+			WordNgoko: v.WordNgoko,
+			WordIndo:  v.WordIndo,
+		})
+	}
+
+	var choicesResp []dto.ChoiceItemResponse
+	if choices, err := slide.GetChoices(); err == nil {
+		choicesResp = toChoiceItems(visibleChoices(choices, sv.st), sv.viewer)
+	}
+
+	var translationResp *dto.TranslationResponse
+	if t, err := slide.GetTranslation(); err == nil && t != nil {
+		translationResp = &dto.TranslationResponse{Prompt: t.Prompt}
+	}
+
+	var quizResp *dto.QuizResponse
+	if quiz, err := slide.GetQuiz(); err == nil && quiz != nil {
+		if word, options, err := quizOptions(slide.ID, quiz, sv.quizWords); err == nil {
+			quizResp = toQuizResponse(word, quiz, options)
+		} else {
+			slog.Error("failed to build quiz", "error", err, "slide_id", slide.ID)
+		}
+	}
+
+	routes, _ := slide.GetRoutes()
+	nextSlideID, _ := resolveNext(slide, sv.st)
+
+	var subtitleResp string
+	if subs, err := slide.GetSubtitles(); err == nil {
+		subtitleResp = sv.viewer.subtitleOf(subs)
+	}
+
+	speakerName := player.Fill(slide.SpeakerName)
+
+	var charsOnScreen []dto.CharacterOnScreen
+	if len(slide.Characters) > 0 {
+		if rawChars, err := slide.GetCharacters(); err == nil {
+			for _, rc := range rawChars {
+				rc = player.Character(rc)
+				isActive := strings.EqualFold(rc.Name, speakerName)
+
+				charsOnScreen = append(charsOnScreen, dto.CharacterOnScreen{
+					Name:     rc.Name,
+					ImageURL: uc.storage.GetObjectURL(rc.ImageURL),
+					IsActive: isActive,
+				})
+			}
+		}
+	}
+
+	return dto.SlideItemResponse{
+		ID:                 slide.ID,
+		Type:               string(slide.Type),
+		BackgroundImageURL: uc.storage.GetObjectURL(slide.BackgroundImageURL),
+		Characters:         charsOnScreen,
+		SpeakerName:        speakerName,
+		Content:            plainText(slide.Content, player),
+		Segments:           toSegments(slide, sv.st.UnlockedWords, player),
+		Subtitle:           subtitleResp,
+		NextSlideID:        nextSlideID,
+		IsCheckpoint:       slide.IsCheckpoint,
+		IsRouted:           len(routes) > 0,
+		Vocabularies:       vocabsResp,
+		Choices:            choicesResp,
+		Quiz:               quizResp,
+		Translation:        translationResp,
+	}
+}
+
+// reachableSlides walks from the slide through every branch the player can see,
+// up to depth steps away, and returns the slides in the order they are reached.
+// Choices are filtered with the current state, so branches that open up later
+// are fetched once the player gets closer.
+func reachableSlides(chapter *entity.Chapter, from uuid.UUID, depth int, st *entity.ConditionState) ([]entity.Slide, error) {
+	byID := make(map[uuid.UUID]*entity.Slide, len(chapter.Slides))
+	for i := range chapter.Slides {
+		byID[chapter.Slides[i].ID] = &chapter.Slides[i]
+	}
+
+	start, ok := byID[from]
+	if !ok {
+		return nil, nil
+	}
+
+	seen := map[uuid.UUID]bool{from: true}
+	res := []entity.Slide{*start}
+	level := []*entity.Slide{start}
+	for d := 0; d < depth && len(level) > 0; d++ {
+		var next []*entity.Slide
+		for _, s := range level {
+			targets, err := slideTargets(s, st)
+			if err != nil {
+				return nil, err
+			}
+			for _, id := range targets {
+				t, ok := byID[id]
+				if !ok || seen[id] {
+					continue
+				}
+				seen[id] = true
+				res = append(res, *t)
+				next = append(next, t)
+			}
+		}
+		level = next
+	}
+
+	return res, nil
+}
+
+// slideTargets mirrors how SubmitAction moves on: the visible choices when
+// one of them can be picked, otherwise every route and the plain next slide.
+func slideTargets(slide *entity.Slide, st *entity.ConditionState) ([]uuid.UUID, error) {
+	choices, err := slide.GetChoices()
+	if err != nil {
+		return nil, err
+	}
+	if options := visibleChoices(choices, st); len(selectableChoices(options)) > 0 {
+		var targets []uuid.UUID
+		for _, o := range options {
+			targets = append(targets, o.Choice.NextSlideID)
+		}
+		return targets, nil
+	}
+
+	routes, err := slide.GetRoutes()
+	if err != nil {
+		return nil, err
+	}
+	var targets []uuid.UUID
+	for _, r := range routes {
+		targets = append(targets, r.NextSlideID)
+	}
+	if slide.NextSlideID != nil {
+		targets = append(targets, *slide.NextSlideID)
+	}
+	return targets, nil
+}
+
+// upcomingSlides renders the session's current slide followed by the slides
+// reachable from it within depth steps.
+func (uc *storyUsecase) upcomingSlides(ctx context.Context, userID uuid.UUID, chapter *entity.Chapter, session *entity.UserStorySession, depth int, v viewer) ([]dto.SlideItemResponse, error) {
+	sv, err := uc.loadSlideView(ctx, userID, chapter.ID, session, chapter.Slides, v)
+	if err != nil {
+		return nil, err
+	}
+
+	slides, err := reachableSlides(chapter, session.CurrentSlideID, depth, sv.st)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]dto.SlideItemResponse, 0, len(slides))
+	for i := range slides {
+		items = append(items, uc.toSlideItem(&slides[i], sv))
+	}
+	return items, nil
+}
+
+func prefetchDepth(depth *int) int {
+	if depth == nil {
+		return defaultPrefetchDepth
+	}
+	return min(*depth, maxPrefetchDepth)
+}
+
+// prefetchNext renders the slides an action moved the session to, so the
+// client can continue without fetching them.
+func (uc *storyUsecase) prefetchNext(ctx context.Context, userID uuid.UUID, chapter *entity.Chapter, session *entity.UserStorySession, depth int, v viewer) ([]dto.SlideItemResponse, error) {
+	return uc.upcomingSlides(ctx, userID, chapter, session, prefetchDepth(&depth), v)
+}
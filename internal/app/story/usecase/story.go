@@ -3,6 +3,7 @@ package usecase
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"strings"
@@ -14,292 +15,526 @@ import (
 	"github.com/Ablebil/lathi-be/internal/domain/entity"
 	"github.com/Ablebil/lathi-be/internal/domain/types"
 	"github.com/Ablebil/lathi-be/internal/infra/minio"
+	"github.com/Ablebil/lathi-be/internal/infra/postgresql"
+	"github.com/Ablebil/lathi-be/pkg/i18n"
 	"github.com/Ablebil/lathi-be/pkg/response"
 	"github.com/google/uuid"
 )
 
+var (
+	// returned inside the action transaction when the session moved on concurrently
+	errSessionConflict = errors.New("session was updated concurrently")
+	// routes that don't match or choices that can't be picked with no fallback, the chapter isn't over there
+	errNoRoute = errors.New("no route or choice applies and the slide has no next slide")
+)
+
 type storyUsecase struct {
-	storyRepo contract.StoryRepositoryItf
-	userRepo  contract.UserRepositoryItf
-	lbRepo    contract.LeaderboardRepositoryItf
-	storage   minio.MinioItf
-	env       *config.Env
+	storyRepo  contract.StoryRepositoryItf
+	userRepo   contract.UserRepositoryItf
+	outboxRepo contract.OutboxRepositoryItf
+	tx         postgresql.TransactorItf
+	storage    minio.MinioItf
+	env        *config.Env
 }
 
-func NewStoryUsecase(storyRepo contract.StoryRepositoryItf, userRepo contract.UserRepositoryItf, lbRepo contract.LeaderboardRepositoryItf, storage minio.MinioItf, env *config.Env) contract.StoryUsecaseItf {
+func NewStoryUsecase(storyRepo contract.StoryRepositoryItf, userRepo contract.UserRepositoryItf, outboxRepo contract.OutboxRepositoryItf, tx postgresql.TransactorItf, storage minio.MinioItf, env *config.Env) contract.StoryUsecaseItf {
 	return &storyUsecase{
-		storyRepo: storyRepo,
-		userRepo:  userRepo,
-		lbRepo:    lbRepo,
-		storage:   storage,
-		env:       env,
+		storyRepo:  storyRepo,
+		userRepo:   userRepo,
+		outboxRepo: outboxRepo,
+		tx:         tx,
+		storage:    storage,
+		env:        env,
 	}
 }
 
+// GetChapterList lists the chapters of the main story, side stories are
+// listed through their story.
 func (uc *storyUsecase) GetChapterList(ctx context.Context, userID uuid.UUID) ([]dto.ChapterListReponse, *response.APIError) {
-	chapters, err := uc.storyRepo.GetAllChapters(ctx)
-	if err != nil {
-		slog.Error("failed to get chapters", "error", err)
-		return nil, response.ErrInternal("Coba lagi nanti ya!")
-	}
-
-	lastCompletedOrder, err := uc.userRepo.GetUserLastCompletedChapter(ctx, userID)
+	lib, err := uc.loadLibrary(ctx, userID)
 	if err != nil {
-		slog.Error("failed to get user progress", "error", err)
-		return nil, response.ErrInternal("Coba lagi nanti ya!")
+		slog.Error("failed to load stories", "error", err)
+		return nil, response.ErrInternal(i18n.CommonTryAgain)
 	}
 
 	var resp []dto.ChapterListReponse
-	for _, ch := range chapters {
-		isLocked := ch.OrderIndex > (lastCompletedOrder + 1)
-		isCompleted := ch.OrderIndex <= lastCompletedOrder
-
-		resp = append(resp, dto.ChapterListReponse{
-			ID:            ch.ID,
-			Title:         ch.Title,
-			Description:   ch.Description,
-			CoverImageURL: uc.storage.GetObjectURL(ch.CoverImageURL),
-			OrderIndex:    ch.OrderIndex,
-			IsLocked:      isLocked,
-			IsCompleted:   isCompleted,
-		})
+	for i := range lib.stories {
+		if lib.stories[i].IsMain() {
+			resp = uc.toChapterItems(lib, lib.stories[i].ID)
+		}
 	}
-
 	return resp, nil
 }
 
-func (uc *storyUsecase) GetChapterContent(ctx context.Context, userID uuid.UUID, chapterID uuid.UUID) (*dto.ChapterContentResponse, *response.APIError) {
-	chapter, err := uc.storyRepo.GetChapterByID(ctx, chapterID)
+// GetChapterContent returns the slides the player has already reached in the
+// slot, for rebuilding a scene or a replay. Slides ahead of the player are
+// only served by GetUpcomingSlides, so branches aren't spoiled. Locked
+// chapters are refused like when starting them.
+func (uc *storyUsecase) GetChapterContent(ctx context.Context, userID uuid.UUID, chapterID uuid.UUID, slot int, subtitle string) (*dto.ChapterContentResponse, *response.APIError) {
+	if apiErr := uc.checkUnlocked(ctx, userID, chapterID); apiErr != nil {
+		return nil, apiErr
+	}
+
+	// choices and routes reflect the player's current state
+	session, err := uc.storyRepo.FindSession(ctx, userID, chapterID, slot)
+	if err != nil {
+		slog.Error("failed to get session", "error", err)
+		return nil, response.ErrInternal(i18n.CommonTryAgain)
+	}
+
+	chapter, err := uc.sessionChapter(ctx, chapterID, session)
 	if err != nil {
 		slog.Error("failed to get chapter", "error", err)
-		return nil, response.ErrInternal("Coba lagi nanti ya!")
+		return nil, response.ErrInternal(i18n.CommonTryAgain)
 	}
 	if chapter == nil {
-		return nil, response.ErrNotFound("Chapter ini ga ketemu")
+		return nil, response.ErrNotFound(i18n.StoryChapterNotFound)
 	}
 
-	var slidesResp []dto.SlideItemResponse
-
-	for _, slide := range chapter.Slides {
-		var vocabsResp []dto.VocabItemResponse
-		for _, v := range slide.Vocabularies {
-			vocabsResp = append(vocabsResp, dto.VocabItemResponse{
-				ID:        v.ID,
-				WordKrama: v.WordKrama,
-				WordNgoko: v.WordNgoko,
-				WordIndo:  v.WordIndo,
-			})
-		}
-
-		var choicesResp []dto.ChoiceItemResponse
-		if len(slide.Choices) > 0 {
-			var rawChoice []struct {
-				Text string `json:"text"`
-			}
+	slides, err := orderSlides(chapter)
+	if err != nil {
+		slog.Error("failed to order chapter slides", "error", err, "chapter_id", chapter.ID)
+		return nil, response.ErrInternal(i18n.CommonTryAgain)
+	}
 
-			if err := json.Unmarshal(slide.Choices, &rawChoice); err == nil {
-				for i, rc := range rawChoice {
-					choicesResp = append(choicesResp, dto.ChoiceItemResponse{
-						Index: i,
-						Text:  rc.Text,
-					})
-				}
-			}
-		}
+	reached, err := uc.reachedSlides(ctx, session)
+	if err != nil {
+		slog.Error("failed to get reached slides", "error", err, "chapter_id", chapter.ID)
+		return nil, response.ErrInternal(i18n.CommonTryAgain)
+	}
 
-		var charsOnScreen []dto.CharacterOnScreen
-		if len(slide.Characters) > 0 {
-			var rawChars []struct {
-				Name     string `json:"name"`
-				ImageURL string `json:"image_url"`
-				IsActive bool   `json:"is_active"`
-			}
+	v, err := uc.viewer(ctx, userID, chapter, subtitle)
+	if err != nil {
+		slog.Error("failed to load protagonist", "error", err, "chapter_id", chapter.ID)
+		return nil, response.ErrInternal(i18n.CommonTryAgain)
+	}
 
-			if err := json.Unmarshal(slide.Characters, &rawChars); err == nil {
-				for _, rc := range rawChars {
-					isActive := strings.EqualFold(rc.Name, slide.SpeakerName)
+	sv, err := uc.loadSlideView(ctx, userID, chapterID, session, slides, v)
+	if err != nil {
+		slog.Error("failed to load slide state", "error", err, "chapter_id", chapter.ID)
+		return nil, response.ErrInternal(i18n.CommonTryAgain)
+	}
 
-					charsOnScreen = append(charsOnScreen, dto.CharacterOnScreen{
-						Name:     rc.Name,
-						ImageURL: uc.storage.GetObjectURL(rc.ImageURL),
-						IsActive: isActive,
-					})
-				}
-			}
+	slidesResp := []dto.SlideItemResponse{}
+	for i := range slides {
+		if reached[slides[i].ID] {
+			slidesResp = append(slidesResp, uc.toSlideItem(&slides[i], sv))
 		}
-
-		slidesResp = append(slidesResp, dto.SlideItemResponse{
-			ID:                 slide.ID,
-			BackgroundImageURL: uc.storage.GetObjectURL(slide.BackgroundImageURL),
-			Characters:         charsOnScreen,
-			SpeakerName:        slide.SpeakerName,
-			Content:            slide.Content,
-			NextSlideID:        slide.NextSlideID,
-			Vocabularies:       vocabsResp,
-			Choices:            choicesResp,
-		})
 	}
 
 	return &dto.ChapterContentResponse{
-		ChapterID: chapter.ID,
-		Slides:    slidesResp,
+		ChapterID:        chapter.ID,
+		StartSlideID:     chapter.StartSlideID,
+		Protagonist:      uc.toProtagonistResponse(v.player),
+		SubtitleLanguage: v.subtitle,
+		Slides:           slidesResp,
 	}, nil
 }
 
-func (uc *storyUsecase) GetUserSession(ctx context.Context, userID, chapterID uuid.UUID) (*dto.UserSessionResponse, *response.APIError) {
-	session, err := uc.storyRepo.FindSession(ctx, userID, chapterID)
+// reachedSlides is every slide the session has acted on and the one it is on,
+// nothing before the chapter is started.
+func (uc *storyUsecase) reachedSlides(ctx context.Context, session *entity.UserStorySession) (map[uuid.UUID]bool, error) {
+	reached := make(map[uuid.UUID]bool)
+	if session == nil {
+		return reached, nil
+	}
+
+	ids, err := uc.storyRepo.GetReachedSlideIDs(ctx, session.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		reached[id] = true
+	}
+	reached[session.CurrentSlideID] = true
+	return reached, nil
+}
+
+func (uc *storyUsecase) GetUserSession(ctx context.Context, userID, chapterID uuid.UUID, slot int) (*dto.UserSessionResponse, *response.APIError) {
+	session, err := uc.storyRepo.FindSession(ctx, userID, chapterID, slot)
 	if err != nil {
 		slog.Error("failed to get session", "error", err)
-		return nil, response.ErrInternal("Coba lagi nanti ya!")
+		return nil, response.ErrInternal(i18n.CommonTryAgain)
 	}
 	if session == nil {
 		return nil, nil // user hasn't played this chapter yet
 	}
 
-	var history []dto.HistoryEntry
-	if len(session.HistoryLog) > 0 {
-		_ = json.Unmarshal(session.HistoryLog, &history)
+	resp, err := uc.sessionState(ctx, session)
+	if err != nil {
+		slog.Error("failed to get user variables", "error", err)
+		return nil, response.ErrInternal(i18n.CommonTryAgain)
 	}
 
-	return &dto.UserSessionResponse{
-		SessionID:      session.ID,
-		CurrentSlideID: session.CurrentSlideID,
-		CurrentHearts:  session.CurrentHearts,
-		IsGameOver:     session.IsGameOver,
-		IsCompleted:    session.IsCompleted,
-		HistoryLog:     history,
-	}, nil
+	return resp, nil
+}
+
+func (uc *storyUsecase) StartSession(ctx context.Context, userID uuid.UUID, chapterID uuid.UUID, slot int) *response.APIError {
+	session, apiErr := uc.newSession(ctx, userID, chapterID, slot)
+	if apiErr != nil {
+		return apiErr
+	}
+
+	err := uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := uc.storyRepo.CreateSession(ctx, session); err != nil {
+			return err
+		}
+		return uc.startAttempt(ctx, userID, chapterID, slot, entity.OriginStart)
+	})
+	if err != nil {
+		slog.Error("failed to create session", "error", err)
+		return response.ErrInternal(i18n.CommonTryAgain)
+	}
+
+	return nil
 }
 
-func (uc *storyUsecase) StartSession(ctx context.Context, userID uuid.UUID, chapterID uuid.UUID) *response.APIError {
-	chapter, err := uc.storyRepo.GetChapterByID(ctx, chapterID)
+// newSession builds a fresh run of the published chapter at its start slide.
+// Chapters that aren't released yet or whose unlock rules the player doesn't
+// meet are refused.
+func (uc *storyUsecase) newSession(ctx context.Context, userID, chapterID uuid.UUID, slot int) (*entity.UserStorySession, *response.APIError) {
+	chapter, versionID, err := uc.publishedChapter(ctx, chapterID)
 	if err != nil {
 		slog.Error("failed to get chapter info", "error", err)
-		return response.ErrInternal("Coba lagi nanti ya!")
+		return nil, response.ErrInternal(i18n.CommonTryAgain)
 	}
 	if chapter == nil {
-		return response.ErrNotFound("Chapter ini ga ketemu")
+		return nil, response.ErrNotFound(i18n.StoryChapterNotFound)
+	}
+
+	if apiErr := uc.checkUnlocked(ctx, userID, chapterID); apiErr != nil {
+		return nil, apiErr
 	}
 	if len(chapter.Slides) == 0 {
-		return response.ErrInternal("Chapter ini belum punya konten")
+		return nil, response.ErrInternal(i18n.StoryChapterEmpty)
+	}
+	if !hasSlide(chapter, chapter.StartSlideID) {
+		slog.Error("chapter has no valid start slide", "chapter_id", chapter.ID, "start_slide_id", chapter.StartSlideID)
+		return nil, response.ErrInternal(i18n.StoryChapterNotReady)
 	}
 
 	session := &entity.UserStorySession{
-		UserID:         userID,
-		ChapterID:      chapterID,
-		CurrentSlideID: chapter.Slides[0].ID,
-		CurrentHearts:  3,
-		IsGameOver:     false,
-		IsCompleted:    false,
-		HistoryLog:     []byte("[]"),
+		UserID:           userID,
+		ChapterID:        chapterID,
+		Slot:             slot,
+		CurrentSlideID:   *chapter.StartSlideID,
+		CurrentHearts:    3,
+		IsGameOver:       false,
+		IsCompleted:      false,
+		Variables:        types.Variables{},
+		ChapterVersionID: &versionID,
+	}
+	for _, s := range chapter.Slides {
+		if s.ID == session.CurrentSlideID && s.IsCheckpoint {
+			session.SaveCheckpoint(s.ID)
+		}
 	}
 
-	if err := uc.storyRepo.CreateSession(ctx, session); err != nil {
-		slog.Error("failed to create session", "error", err)
-		return response.ErrInternal("Coba lagi nanti ya!")
+	return session, nil
+}
+
+// ResumeFromCheckpoint restores a game over session to the state saved at the
+// last checkpoint it passed. Cross-chapter variables are not rolled back.
+func (uc *storyUsecase) ResumeFromCheckpoint(ctx context.Context, userID, chapterID uuid.UUID, slot int) (*dto.UserSessionResponse, *response.APIError) {
+	session, err := uc.storyRepo.FindSession(ctx, userID, chapterID, slot)
+	if err != nil {
+		slog.Error("failed to get session", "error", err)
+		return nil, response.ErrInternal(i18n.CommonTryAgain)
+	}
+	if session == nil {
+		return nil, response.ErrBadRequest(i18n.StoryNotStarted)
+	}
+	if !session.IsGameOver {
+		return nil, response.ErrBadRequest(i18n.StoryNotFinished)
 	}
 
-	return nil
+	chapter, err := uc.sessionChapter(ctx, chapterID, session)
+	if err != nil || chapter == nil {
+		slog.Error("failed to get chapter version", "error", err, "session_id", session.ID)
+		return nil, response.ErrInternal(i18n.CommonTryAgain)
+	}
+
+	var checkpoint *entity.Slide
+	if session.CheckpointSlideID != nil {
+		checkpoint = findSlide(chapter, *session.CheckpointSlideID)
+	}
+	if checkpoint == nil {
+		return nil, response.ErrBadRequest(i18n.StoryNoCheckpoint)
+	}
+
+	session.CurrentSlideID = checkpoint.ID
+	session.CurrentHearts = session.CheckpointHearts
+	session.Variables = session.CheckpointVariables.Clone()
+	session.IsGameOver = false
+	session.UpdatedAt = time.Now()
+
+	// the game over attempt stays as it ended, the resumed run is a new attempt
+	err = uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		updated, err := uc.storyRepo.UpdateSession(ctx, session)
+		if err != nil {
+			return err
+		}
+		if !updated {
+			return errSessionConflict
+		}
+		return uc.startAttempt(ctx, userID, chapterID, slot, entity.OriginCheckpoint)
+	})
+	if errors.Is(err, errSessionConflict) {
+		// resumed or restarted concurrently, report the state that won
+		current, err := uc.storyRepo.FindSession(ctx, userID, chapterID, slot)
+		if err != nil || current == nil {
+			slog.Error("failed to reload session", "error", err)
+			return nil, response.ErrInternal(i18n.CommonTryAgain)
+		}
+		return nil, uc.errOutOfSync(ctx, current)
+	}
+	if err != nil {
+		slog.Error("failed to resume session", "error", err)
+		return nil, response.ErrInternal(i18n.CommonTryAgain)
+	}
+
+	resp, err := uc.sessionState(ctx, session)
+	if err != nil {
+		slog.Error("failed to get user variables", "error", err)
+		return nil, response.ErrInternal(i18n.CommonTryAgain)
+	}
+
+	return resp, nil
 }
 
 func (uc *storyUsecase) SubmitAction(ctx context.Context, userID uuid.UUID, req *dto.StoryActionRequest) (*dto.StoryActionResponse, *response.APIError) {
-	session, err := uc.storyRepo.FindSession(ctx, userID, req.ChapterID)
+	session, err := uc.storyRepo.FindSession(ctx, userID, req.ChapterID, saveSlot(req.Slot))
 	if err != nil {
 		slog.Error("failed to get session", "error", err)
-		return nil, response.ErrInternal("Coba lagi nanti ya!")
+		return nil, response.ErrInternal(i18n.CommonTryAgain)
 	}
 	if session == nil {
-		return nil, response.ErrBadRequest("Kamu belum memulai chapter ini, yuk mulai dulu ya!")
+		return nil, response.ErrBadRequest(i18n.StoryNotStarted)
+	}
+
+	// a retry of an action that was already applied gets the original result back,
+	// only the version tells a retry apart from a new action
+	if req.Version == nil {
+		return nil, response.NewFieldValidationError("version", "required")
 	}
+	if *req.Version > session.Version {
+		return nil, uc.errOutOfSync(ctx, session)
+	}
+	if *req.Version < session.Version {
+		if resp, apiErr := uc.replayAction(ctx, session.ID, *req.Version, req); resp != nil || apiErr != nil {
+			return resp, apiErr
+		}
+		return nil, uc.errOutOfSync(ctx, session)
+	}
+
 	if session.IsGameOver || session.IsCompleted {
-		return nil, response.ErrBadRequest("Permainan udah selesai, coba mulai lagi ya!")
+		return nil, response.ErrBadRequest(i18n.StoryFinished)
+	}
+
+	// the run plays the version it was started on
+	chapter, err := uc.sessionChapter(ctx, req.ChapterID, session)
+	if err != nil || chapter == nil {
+		slog.Error("failed to get chapter version", "error", err, "session_id", session.ID)
+		return nil, response.ErrInternal(i18n.CommonTryAgain)
 	}
 
-	currentSlide, err := uc.storyRepo.GetSlideByID(ctx, req.SlideID)
-	if err != nil || currentSlide == nil {
-		return nil, response.ErrNotFound("Slide ga ketemu")
+	currentSlide := findSlide(chapter, req.SlideID)
+	if currentSlide == nil {
+		return nil, response.ErrBadRequest(i18n.StorySlideNotInChapter)
 	}
 
-	// append to history log
-	var history []dto.HistoryEntry
-	if len(session.HistoryLog) > 0 {
-		_ = json.Unmarshal(session.HistoryLog, &history)
+	// the session is the source of truth, actions only advance from its current slide
+	if currentSlide.ID != session.CurrentSlideID {
+		return nil, uc.errOutOfSync(ctx, session)
 	}
 
-	speakerName := currentSlide.SpeakerName
-	if speakerName == "" {
-		speakerName = "Narator"
+	v, err := uc.viewer(ctx, userID, chapter, "")
+	if err != nil {
+		slog.Error("failed to load protagonist", "error", err, "chapter_id", chapter.ID)
+		return nil, response.ErrInternal(i18n.CommonTryAgain)
 	}
+	player := v.player
+
+	// history entries this action adds, stored as new events of the session
+	var events []entity.StoryEvent
 
-	history = append(history, dto.HistoryEntry{
-		Speaker:   speakerName,
-		Text:      currentSlide.Content,
-		IsUser:    false,
-		Timestamp: time.Now(),
+	events = append(events, entity.StoryEvent{
+		Speaker: speaker(currentSlide, player),
+		Text:    plainText(currentSlide.Content, player),
+		IsUser:  false,
+		SlideID: &currentSlide.ID,
 	})
 
-	var nextSlideID *uuid.UUID = currentSlide.NextSlideID
+	choices, err := currentSlide.GetChoices()
+	if err != nil {
+		slog.Error("failed to parse slide choices", "error", err, "slide_id", currentSlide.ID)
+		return nil, response.ErrInternal(i18n.CommonTryAgain)
+	}
+
+	// conditions are evaluated against the state before this action
+	words, err := conditionWords(*currentSlide)
+	if err != nil {
+		slog.Error("failed to parse slide conditions", "error", err, "slide_id", currentSlide.ID)
+		return nil, response.ErrInternal(i18n.CommonTryAgain)
+	}
+	st, err := uc.conditionState(ctx, userID, session.ChapterID, session, words)
+	if err != nil {
+		slog.Error("failed to load condition state", "error", err)
+		return nil, response.ErrInternal(i18n.CommonTryAgain)
+	}
+
+	nextSlideID, err := resolveNext(currentSlide, st)
+	if err != nil {
+		slog.Error("failed to resolve next slide", "error", err, "slide_id", currentSlide.ID)
+		return nil, response.ErrInternal(i18n.CommonTryAgain)
+	}
 	moodImpact := 0
+	var userEffects []entity.Effect
+	var takenChoice *entity.AttemptChoice
 
-	var choices []struct {
-		Text        string    `json:"text"`
-		NextSlideID uuid.UUID `json:"next_slide_id"`
-		MoodImpact  int       `json:"mood_impact"`
+	quiz, err := currentSlide.GetQuiz()
+	if err != nil {
+		slog.Error("failed to parse slide quiz", "error", err, "slide_id", currentSlide.ID)
+		return nil, response.ErrInternal(i18n.CommonTryAgain)
 	}
 
-	hasChoice := false
-	if len(currentSlide.Choices) > 0 {
-		if err := json.Unmarshal(currentSlide.Choices, &choices); err == nil {
-			if len(choices) > 0 {
-				hasChoice = true
-			}
+	// quiz slides are graded here and move on like a plain slide whatever the answer
+	var quizResult *dto.QuizResultResponse
+	if quiz != nil {
+		if req.ChoiceIndex == nil {
+			return nil, response.ErrBadRequest(i18n.StoryQuizRequired)
+		}
+
+		words, err := uc.quizWords(ctx, *currentSlide)
+		if err != nil {
+			slog.Error("failed to load quiz words", "error", err, "slide_id", currentSlide.ID)
+			return nil, response.ErrInternal(i18n.CommonTryAgain)
+		}
+		word, options, err := quizOptions(currentSlide.ID, quiz, words)
+		if err != nil {
+			slog.Error("failed to build quiz", "error", err, "slide_id", currentSlide.ID)
+			return nil, response.ErrInternal(i18n.CommonTryAgain)
+		}
+
+		result, picked, ok := gradeQuiz(word, quiz, options, *req.ChoiceIndex)
+		if !ok {
+			return nil, response.ErrBadRequest(i18n.StoryInvalidAnswer)
 		}
+		quizResult = result
+		moodImpact = -result.HeartsLost
+
+		events = append(events, entity.StoryEvent{
+			Speaker:     player.Name,
+			Text:        picked,
+			IsUser:      true,
+			ChoiceIndex: req.ChoiceIndex,
+			SlideID:     &currentSlide.ID,
+		})
+	}
+
+	translation, err := currentSlide.GetTranslation()
+	if err != nil {
+		slog.Error("failed to parse slide translation", "error", err, "slide_id", currentSlide.ID)
+		return nil, response.ErrInternal(i18n.CommonTryAgain)
+	}
+
+	// translation slides grade the typed answer and move on like quiz slides
+	var translationResult *dto.TranslationResult
+	if translation != nil {
+		if req.Answer == nil || strings.TrimSpace(*req.Answer) == "" {
+			return nil, response.ErrBadRequest(i18n.StoryTranslationRequired)
+		}
+		if req.ChoiceIndex != nil {
+			return nil, response.ErrBadRequest(i18n.StoryNoChoices)
+		}
+
+		translationResult = gradeTranslation(translation, *req.Answer)
+		moodImpact = -translationResult.HeartsLost
+
+		events = append(events, entity.StoryEvent{
+			Speaker: player.Name,
+			Text:    strings.TrimSpace(*req.Answer),
+			IsUser:  true,
+			SlideID: &currentSlide.ID,
+		})
+	} else if req.Answer != nil {
+		return nil, response.ErrBadRequest(i18n.StoryNoTypedAnswer)
 	}
 
+	// hidden and disabled choices can't be picked, a slide without selectable choices just moves on
+	selectable := selectableChoices(visibleChoices(choices, st))
+	hasChoice := len(selectable) > 0
+
 	if hasChoice && req.ChoiceIndex == nil {
-		return nil, response.ErrBadRequest("Kamu harus milih salah satu pilihan yang ada")
+		return nil, response.ErrBadRequest(i18n.StoryChoiceRequired)
 	}
 
-	if !hasChoice && req.ChoiceIndex != nil {
-		return nil, response.ErrBadRequest("Slide ini ga punya pilihan buat dipilih")
+	if !hasChoice && req.ChoiceIndex != nil && quiz == nil {
+		return nil, response.ErrBadRequest(i18n.StoryNoChoices)
 	}
 
 	// process choice if any
 	if req.ChoiceIndex != nil && hasChoice {
-		idx := *req.ChoiceIndex
-		if idx < 0 || idx >= len(choices) {
-			return nil, response.ErrBadRequest("Pilihanmu ga valid")
+		var selected *entity.Choice
+		for i := range selectable {
+			if selectable[i].Index == *req.ChoiceIndex {
+				selected = &selectable[i].Choice
+				break
+			}
+		}
+		if selected == nil {
+			return nil, response.ErrBadRequest(i18n.StoryInvalidChoice)
 		}
 
-		selected := choices[idx]
 		nextSlideID = &selected.NextSlideID
 		moodImpact = selected.MoodImpact
+		takenChoice = &entity.AttemptChoice{
+			SlideID:     currentSlide.ID,
+			ChoiceIndex: *req.ChoiceIndex,
+			Text:        selected.Text,
+		}
+
+		// session effects apply right away, user effects inside the action transaction
+		if session.Variables == nil {
+			session.Variables = types.Variables{}
+		}
+		for _, e := range selected.Effects {
+			if e.IsUserScope() {
+				userEffects = append(userEffects, e)
+				continue
+			}
+			if err := e.Apply(session.Variables); err != nil {
+				slog.Error("failed to apply choice effect", "error", err, "slide_id", currentSlide.ID)
+				return nil, response.ErrInternal(i18n.CommonTryAgain)
+			}
+		}
 
-		history = append(history, dto.HistoryEntry{
-			Speaker:   "Andi",
-			Text:      selected.Text,
-			IsUser:    true,
-			Timestamp: time.Now(),
+		events = append(events, entity.StoryEvent{
+			Speaker:     player.Name,
+			Text:        plainText(selected.Text, player),
+			IsUser:      true,
+			ChoiceIndex: req.ChoiceIndex,
+			SlideID:     &currentSlide.ID,
 		})
 	}
 
-	newHistoryJSON, _ := json.Marshal(history)
-	session.HistoryLog = types.JSONB(newHistoryJSON)
-
 	// update game state
+	heartsBefore := session.CurrentHearts
 	session.CurrentHearts += moodImpact
 	isGameOver := false
 	message := ""
+	lang := i18n.FromContext(ctx)
 
-	feedbackSpeaker := currentSlide.SpeakerName
-	if feedbackSpeaker == "" {
-		feedbackSpeaker = "Panjenenganipun"
+	// the protagonist can't be disappointed in themselves
+	feedbackSpeaker := player.Fill(currentSlide.SpeakerName)
+	if feedbackSpeaker == "" || feedbackSpeaker == player.Name {
+		feedbackSpeaker = entity.UnknownSpeaker
 	}
 
 	if session.CurrentHearts <= 0 {
 		session.CurrentHearts = 0
 		isGameOver = true
-		message = fmt.Sprintf("%s kuciwo karo omonganmu. Coba maneh ya!", feedbackSpeaker)
+		message = i18n.T(lang, i18n.StoryGameOver, feedbackSpeaker, player.Name)
 	}
 
 	if session.CurrentHearts > 3 {
@@ -307,88 +542,404 @@ func (uc *storyUsecase) SubmitAction(ctx context.Context, userID uuid.UUID, req
 	}
 
 	session.IsGameOver = isGameOver
+	var nextSlide *entity.Slide
+	checkpointSaved := false
 	if !isGameOver && nextSlideID != nil {
 		session.CurrentSlideID = *nextSlideID
+
+		// arriving at a checkpoint saves the state a game over resumes from
+		nextSlide = findSlide(chapter, *nextSlideID)
+		if nextSlide != nil && nextSlide.IsCheckpoint {
+			session.SaveCheckpoint(nextSlide.ID)
+			checkpointSaved = true
+		}
 	}
 
 	isCompleted := false
 	if !isGameOver && nextSlideID == nil {
 		isCompleted = true
 		session.IsCompleted = true
-		message = "Sugeng! Sampeyan wis rampung crita iki."
-
-		chapter, _ := uc.storyRepo.GetChapterByID(ctx, req.ChapterID)
-		if chapter != nil {
-			if err := uc.userRepo.UpdateUserLastCompletedChapter(ctx, userID, chapter.OrderIndex); err == nil {
-				totalChapters, _ := uc.storyRepo.CountChapters(ctx)
-				if totalChapters > 0 {
-					progress := (float64(chapter.OrderIndex) / float64(totalChapters)) * 100
-
-					var newTitle entity.Title
-					if progress <= 30 {
-						newTitle = entity.Cantrik
-					} else if progress <= 70 {
-						newTitle = entity.Abdi
-					} else {
-						newTitle = entity.Priyayi
-					}
-
-					_ = uc.userRepo.UpdateUserTitle(ctx, userID, newTitle)
-				}
+		session.EndingID = currentSlide.EndingID
+		message = i18n.T(lang, i18n.StoryCompleted)
+	}
+
+	baseVersion := session.Version
+	session.UpdatedAt = time.Now()
+
+	step := entity.AttemptStep{
+		HeartsLost: max(heartsBefore-session.CurrentHearts, 0),
+		Choice:     takenChoice,
+	}
+	if isGameOver {
+		step.Outcome = entity.AttemptGameOver
+	} else if isCompleted {
+		step.Outcome = entity.AttemptCompleted
+		step.EndingID = session.EndingID
+	}
 
-				_ = uc.lbRepo.UpdateUserScore(ctx, userID)
+	resp := &dto.StoryActionResponse{
+		IsGameOver:      isGameOver,
+		IsCompleted:     isCompleted,
+		Message:         message,
+		RemainingHearts: session.CurrentHearts,
+		NextSlideID:     nextSlideID,
+		CheckpointSaved: checkpointSaved,
+		Quiz:            quizResult,
+		Translation:     translationResult,
+		Version:         baseVersion + 1,
+		Variables:       session.Variables,
+	}
+
+	// every postgres write of the action commits together, redis is updated later through the outbox
+	err = uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		updated, err := uc.storyRepo.UpdateSession(ctx, session)
+		if err != nil {
+			return err
+		}
+		if !updated {
+			return errSessionConflict
+		}
 
-				// badge 1
-				if chapter.OrderIndex == 1 {
-					_ = uc.userRepo.AssignBadge(ctx, userID, "ch1_completion")
+		if err := uc.storyRepo.AdvanceAttempt(ctx, userID, req.ChapterID, session.Slot, step); err != nil {
+			return err
+		}
+
+		if err := uc.storyRepo.AppendEvents(ctx, session.ID, events); err != nil {
+			return err
+		}
+		resp.NewEntries = toHistoryEntries(events)
+
+		if len(userEffects) > 0 {
+			userVars, err := uc.storyRepo.LockUserVariables(ctx, userID)
+			if err != nil {
+				return err
+			}
+			for _, e := range userEffects {
+				if err := e.Apply(userVars); err != nil {
+					return err
 				}
+			}
+			if err := uc.storyRepo.SaveUserVariables(ctx, userID, userVars); err != nil {
+				return err
+			}
+			resp.UserVariables = userVars
+		}
 
-				if int64(chapter.OrderIndex) == totalChapters {
-					_ = uc.userRepo.AssignBadge(ctx, userID, "all_chapters_completion")
+		if quizResult != nil {
+			if err := uc.storyRepo.RecordWordResult(ctx, userID, quizResult.WordID, quizResult.IsCorrect); err != nil {
+				return err
+			}
+		}
+
+		scoreChanged := false
+		if isCompleted {
+			if err := uc.rewardCompletion(ctx, userID, req.ChapterID, session.CurrentHearts); err != nil {
+				return err
+			}
+			scoreChanged = true
+
+			if session.EndingID != nil {
+				ending, err := uc.reachEnding(ctx, userID, chapter, *session.EndingID)
+				if err != nil {
+					return err
 				}
+				resp.Ending = ending
+			}
+		}
 
-				if session.CurrentHearts == 3 {
-					_ = uc.userRepo.AssignBadge(ctx, userID, "perfect_heart")
+		newWords, err := uc.unlockVocabularies(ctx, userID, currentSlide.Vocabularies)
+		if err != nil {
+			return err
+		}
+		if newWords > 0 {
+			scoreChanged = true
+		}
+
+		if scoreChanged {
+			if err := uc.outboxRepo.Enqueue(ctx, entity.OutboxTopicLeaderboardScore, entity.LeaderboardScorePayload{UserID: userID}); err != nil {
+				return err
+			}
+		}
+
+		if !isGameOver && !isCompleted {
+			if resp.NextChoices, err = uc.nextChoices(ctx, userID, session, nextSlide, v); err != nil {
+				return err
+			}
+			if req.Prefetch != nil {
+				if resp.NextSlides, err = uc.prefetchNext(ctx, userID, chapter, session, *req.Prefetch, v); err != nil {
+					return err
 				}
 			}
 		}
+
+		respJSON, _ := json.Marshal(resp)
+		return uc.storyRepo.SaveAction(ctx, &entity.UserStoryAction{
+			SessionID:   session.ID,
+			Version:     baseVersion,
+			SlideID:     currentSlide.ID,
+			ChoiceIndex: req.ChoiceIndex,
+			Answer:      req.Answer,
+			Response:    types.JSONB(respJSON),
+		})
+	})
+
+	if errors.Is(err, errSessionConflict) {
+		// another request advanced the session first, most likely a double tap
+		return uc.resolveConflict(ctx, userID, req, baseVersion)
+	}
+	if err != nil {
+		slog.Error("failed to apply story action", "error", err)
+		return nil, response.ErrInternal(i18n.CommonTryAgain)
 	}
 
-	session.UpdatedAt = time.Now()
-	if err := uc.storyRepo.UpdateSession(ctx, session); err != nil {
-		slog.Error("failed to update session", "error", err)
-		return nil, response.ErrInternal("Coba lagi nanti ya!")
+	return resp, nil
+}
+
+// nextChoices evaluates the choices of the session's new current slide against
+// the state after the action, since conditions may have changed.
+func (uc *storyUsecase) nextChoices(ctx context.Context, userID uuid.UUID, session *entity.UserStorySession, slide *entity.Slide, v viewer) ([]dto.ChoiceItemResponse, error) {
+	if slide == nil {
+		return nil, nil
+	}
+
+	choices, err := slide.GetChoices()
+	if err != nil || len(choices) == 0 {
+		return nil, err
+	}
+
+	words, err := conditionWords(*slide)
+	if err != nil {
+		return nil, err
+	}
+	st, err := uc.conditionState(ctx, userID, session.ChapterID, session, words)
+	if err != nil {
+		return nil, err
+	}
+
+	return toChoiceItems(visibleChoices(choices, st), v), nil
+}
+
+// rewardCompletion records the chapter as completed and counts the chapters
+// the user completed in its story and over every story, for the titles and
+// badges that come with them.
+func (uc *storyUsecase) rewardCompletion(ctx context.Context, userID, chapterID uuid.UUID, hearts int) error {
+	chapter, err := uc.storyRepo.GetChapterMeta(ctx, chapterID)
+	if err != nil {
+		return err
+	}
+	if chapter == nil {
+		return fmt.Errorf("chapter %s not found", chapterID)
+	}
+
+	story, err := uc.storyRepo.GetStory(ctx, chapter.StoryID)
+	if err != nil {
+		return err
+	}
+	if story == nil {
+		return fmt.Errorf("story %s not found", chapter.StoryID)
+	}
+
+	if _, err := uc.storyRepo.RecordChapterCompletion(ctx, userID, chapter.ID); err != nil {
+		return err
+	}
+
+	storyCompleted, err := uc.storyRepo.CountCompletedChaptersByStory(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	completed, err := uc.userRepo.SyncCompletedChapters(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	storyChapters, err := uc.storyRepo.CountChaptersByStory(ctx)
+	if err != nil {
+		return err
+	}
+
+	totalChapters := 0
+	for _, n := range storyChapters {
+		totalChapters += n
+	}
+
+	if total := storyChapters[story.ID]; total > 0 {
+		if err := uc.storyRepo.UpdateStoryTitle(ctx, userID, story.ID, entity.TitleFor(storyCompleted[story.ID], total)); err != nil {
+			return err
+		}
+	}
+	if totalChapters > 0 {
+		if err := uc.userRepo.UpdateUserTitle(ctx, userID, entity.TitleFor(completed, totalChapters)); err != nil {
+			return err
+		}
+	}
+
+	var badges []string
+	// badge 1
+	if story.IsMain() && chapter.OrderIndex == 1 {
+		badges = append(badges, "ch1_completion")
+	}
+
+	if story.BadgeCode != "" && storyCompleted[story.ID] >= storyChapters[story.ID] {
+		badges = append(badges, story.BadgeCode)
+	}
+
+	if completed >= totalChapters {
+		badges = append(badges, "all_chapters_completion")
+	}
+
+	if hearts == 3 {
+		badges = append(badges, "perfect_heart")
 	}
 
-	// unlock vocabs if any
-	if len(currentSlide.Vocabularies) > 0 {
-		var vocabIDs []uuid.UUID
-		for _, v := range currentSlide.Vocabularies {
-			vocabIDs = append(vocabIDs, v.ID)
+	for _, code := range badges {
+		if err := uc.userRepo.AssignBadge(ctx, userID, code); err != nil {
+			return err
 		}
+	}
 
-		newWordsCount, err := uc.storyRepo.UnlockVocabularies(ctx, userID, vocabIDs)
-		if err != nil {
-			slog.Error("failed to unlock vocabs", "error", err)
-		} else if newWordsCount > 0 {
-			_ = uc.userRepo.IncrementUserWordCount(ctx, userID, int(newWordsCount))
-			_ = uc.lbRepo.UpdateUserScore(ctx, userID)
-
-			user, err := uc.userRepo.GetUserByID(ctx, userID)
-			if err == nil && user != nil {
-				if user.TotalWordsCollected >= 30 {
-					_ = uc.userRepo.AssignBadge(ctx, userID, "vocab_collector_1")
-				}
+	return nil
+}
+
+// reachEnding records the ending of the chapter version for the user. The
+// first time an ending is reached its badge and score bonus are awarded.
+func (uc *storyUsecase) reachEnding(ctx context.Context, userID uuid.UUID, chapter *entity.Chapter, endingID uuid.UUID) (*dto.EndingResponse, error) {
+	ending := findEnding(chapter, endingID)
+	if ending == nil {
+		return nil, fmt.Errorf("ending %s not found", endingID)
+	}
+
+	isNew, err := uc.storyRepo.RecordEnding(ctx, userID, ending.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if isNew {
+		if ending.BadgeCode != "" {
+			if err := uc.userRepo.AssignBadge(ctx, userID, ending.BadgeCode); err != nil {
+				return nil, err
+			}
+		}
+		if ending.ScoreBonus != 0 {
+			if err := uc.userRepo.AddBonusScore(ctx, userID, ending.ScoreBonus); err != nil {
+				return nil, err
 			}
 		}
 	}
 
-	return &dto.StoryActionResponse{
-		IsGameOver:      isGameOver,
-		IsCompleted:     isCompleted,
-		Message:         message,
-		RemainingHearts: session.CurrentHearts,
-		NextSlideID:     nextSlideID,
-		HistoryLog:      history,
+	return &dto.EndingResponse{
+		ID:    ending.ID,
+		Title: ending.Title,
+		Kind:  string(ending.Kind),
+		IsNew: isNew,
 	}, nil
 }
+
+// unlockVocabularies unlocks the slide vocabularies for the user and returns
+// how many words were new.
+func (uc *storyUsecase) unlockVocabularies(ctx context.Context, userID uuid.UUID, vocabs []entity.Dictionary) (int64, error) {
+	if len(vocabs) == 0 {
+		return 0, nil
+	}
+
+	var vocabIDs []uuid.UUID
+	for _, v := range vocabs {
+		vocabIDs = append(vocabIDs, v.ID)
+	}
+
+	newWordsCount, err := uc.storyRepo.UnlockVocabularies(ctx, userID, vocabIDs)
+	if err != nil || newWordsCount == 0 {
+		return 0, err
+	}
+
+	if err := uc.userRepo.IncrementUserWordCount(ctx, userID, int(newWordsCount)); err != nil {
+		return 0, err
+	}
+
+	user, err := uc.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+	if user != nil && user.TotalWordsCollected >= 30 {
+		if err := uc.userRepo.AssignBadge(ctx, userID, "vocab_collector_1"); err != nil {
+			return 0, err
+		}
+	}
+
+	return newWordsCount, nil
+}
+
+// replayAction returns the stored result of the action applied at version,
+// or nil when no action or a different one was applied there.
+func (uc *storyUsecase) replayAction(ctx context.Context, sessionID uuid.UUID, version int, req *dto.StoryActionRequest) (*dto.StoryActionResponse, *response.APIError) {
+	action, err := uc.storyRepo.FindAction(ctx, sessionID, version)
+	if err != nil {
+		slog.Error("failed to get story action", "error", err)
+		return nil, response.ErrInternal(i18n.CommonTryAgain)
+	}
+	if action == nil || !action.Matches(req.SlideID, req.ChoiceIndex, req.Answer) {
+		return nil, nil
+	}
+
+	var resp dto.StoryActionResponse
+	if err := json.Unmarshal(action.Response, &resp); err != nil {
+		slog.Error("failed to parse stored story action", "error", err)
+		return nil, response.ErrInternal(i18n.CommonTryAgain)
+	}
+
+	return &resp, nil
+}
+
+func (uc *storyUsecase) resolveConflict(ctx context.Context, userID uuid.UUID, req *dto.StoryActionRequest, baseVersion int) (*dto.StoryActionResponse, *response.APIError) {
+	session, err := uc.storyRepo.FindSession(ctx, userID, req.ChapterID, saveSlot(req.Slot))
+	if err != nil || session == nil {
+		slog.Error("failed to reload session", "error", err)
+		return nil, response.ErrInternal(i18n.CommonTryAgain)
+	}
+
+	if resp, apiErr := uc.replayAction(ctx, session.ID, baseVersion, req); resp != nil || apiErr != nil {
+		return resp, apiErr
+	}
+
+	return nil, uc.errOutOfSync(ctx, session)
+}
+
+// sessionState is the authoritative session state including the variables
+// carried across chapters.
+func (uc *storyUsecase) sessionState(ctx context.Context, session *entity.UserStorySession) (*dto.UserSessionResponse, error) {
+	resp := toSessionResponse(session)
+
+	userVars, err := uc.storyRepo.GetUserVariables(ctx, session.UserID)
+	if err != nil {
+		return nil, err
+	}
+	resp.UserVariables = userVars
+
+	return resp, nil
+}
+
+func (uc *storyUsecase) errOutOfSync(ctx context.Context, session *entity.UserStorySession) *response.APIError {
+	state, err := uc.sessionState(ctx, session)
+	if err != nil {
+		slog.Error("failed to get user variables", "error", err)
+		state = toSessionResponse(session)
+	}
+	return response.ErrOutOfSync(i18n.StoryOutOfSync, state)
+}
+
+func toSessionResponse(session *entity.UserStorySession) *dto.UserSessionResponse {
+	return &dto.UserSessionResponse{
+		SessionID:      session.ID,
+		Slot:           session.Slot,
+		Name:           session.Name,
+		CurrentSlideID: session.CurrentSlideID,
+		CurrentHearts:  session.CurrentHearts,
+		IsGameOver:     session.IsGameOver,
+		IsCompleted:    session.IsCompleted,
+		Version:        session.Version,
+		EndingID:       session.EndingID,
+		CheckpointID:   session.CheckpointSlideID,
+		ChapterVersion: session.ChapterVersionID,
+		Variables:      session.Variables,
+	}
+}
@@ -0,0 +1,448 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/Ablebil/lathi-be/internal/domain/contract"
+	"github.com/Ablebil/lathi-be/internal/domain/dto"
+	"github.com/Ablebil/lathi-be/internal/domain/entity"
+	"github.com/Ablebil/lathi-be/internal/domain/types"
+	"github.com/Ablebil/lathi-be/pkg/i18n"
+	"github.com/google/uuid"
+)
+
+// fakeStoryRepo keeps one session and the chapter version it plays in memory.
+// Methods a test doesn't expect are left to the nil interface and panic.
+type fakeStoryRepo struct {
+	contract.StoryRepositoryItf
+
+	session  entity.UserStorySession
+	version  *entity.ChapterVersion
+	actions  map[int]*entity.UserStoryAction
+	conflict func(r *fakeStoryRepo) // another request applied right before the update
+}
+
+func (r *fakeStoryRepo) FindSession(ctx context.Context, userID, chapterID uuid.UUID, slot int) (*entity.UserStorySession, error) {
+	session := r.session
+	session.Variables = types.Variables{}
+	for k, v := range r.session.Variables {
+		session.Variables[k] = v
+	}
+	return &session, nil
+}
+
+func (r *fakeStoryRepo) UpdateSession(ctx context.Context, session *entity.UserStorySession) (bool, error) {
+	if r.conflict != nil {
+		r.conflict(r)
+		r.conflict = nil
+	}
+	if session.Version != r.session.Version {
+		return false, nil
+	}
+	r.session = *session
+	r.session.Version++
+	return true, nil
+}
+
+func (r *fakeStoryRepo) GetPublishedVersion(ctx context.Context, chapterID uuid.UUID) (*entity.ChapterVersion, error) {
+	return r.version, nil
+}
+
+func (r *fakeStoryRepo) GetChapterVersion(ctx context.Context, id uuid.UUID) (*entity.ChapterVersion, error) {
+	return r.version, nil
+}
+
+func (r *fakeStoryRepo) GetUserVariables(ctx context.Context, userID uuid.UUID) (types.Variables, error) {
+	return types.Variables{}, nil
+}
+
+func (r *fakeStoryRepo) GetUnlockedWords(ctx context.Context, userID uuid.UUID, words []string) (map[string]bool, error) {
+	return map[string]bool{}, nil
+}
+
+func (r *fakeStoryRepo) GetCompletedOrderIndexes(ctx context.Context, userID, chapterID uuid.UUID) (map[int]bool, error) {
+	return map[int]bool{}, nil
+}
+
+func (r *fakeStoryRepo) AdvanceAttempt(ctx context.Context, userID, chapterID uuid.UUID, slot int, step entity.AttemptStep) error {
+	return nil
+}
+
+func (r *fakeStoryRepo) AppendEvents(ctx context.Context, sessionID uuid.UUID, events []entity.StoryEvent) error {
+	return nil
+}
+
+func (r *fakeStoryRepo) SaveAction(ctx context.Context, action *entity.UserStoryAction) error {
+	if r.actions == nil {
+		r.actions = make(map[int]*entity.UserStoryAction)
+	}
+	r.actions[action.Version] = action
+	return nil
+}
+
+func (r *fakeStoryRepo) FindAction(ctx context.Context, sessionID uuid.UUID, version int) (*entity.UserStoryAction, error) {
+	return r.actions[version], nil
+}
+
+type fakeUserRepo struct {
+	contract.UserRepositoryItf
+}
+
+func (r *fakeUserRepo) GetUserByID(ctx context.Context, id uuid.UUID) (*entity.User, error) {
+	return nil, nil
+}
+
+type fakeTx struct{}
+
+func (fakeTx) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+type fakeStorage struct{}
+
+func (fakeStorage) GetObjectURL(object string) string {
+	return object
+}
+
+// testSlide is a slide of the test chapter, pointing to other slides by key.
+type testSlide struct {
+	key     string
+	next    string
+	choices []testChoice
+	routes  []testRoute
+}
+
+type testChoice struct {
+	text     string
+	next     string
+	showIf   *entity.Condition
+	enableIf *entity.Condition
+}
+
+type testRoute struct {
+	cond entity.Condition
+	next string
+}
+
+// newFakeChapter publishes the slides as one chapter version with the session
+// on the first slide, and returns the slide IDs by key.
+func newFakeChapter(t *testing.T, slides ...testSlide) (*fakeStoryRepo, map[string]uuid.UUID) {
+	t.Helper()
+
+	ids := make(map[string]uuid.UUID, len(slides))
+	for _, s := range slides {
+		ids[s.key] = uuid.New()
+	}
+
+	chapter := &entity.Chapter{ID: uuid.New(), Title: "Test"}
+	for _, s := range slides {
+		slide := entity.Slide{ID: ids[s.key], ChapterID: chapter.ID, Key: s.key, Content: s.key}
+		if s.next != "" {
+			next := ids[s.next]
+			slide.NextSlideID = &next
+		}
+
+		var choices []entity.Choice
+		for _, c := range s.choices {
+			choices = append(choices, entity.Choice{Text: c.text, NextSlideID: ids[c.next], ShowIf: c.showIf, EnableIf: c.enableIf})
+		}
+		var routes []entity.Route
+		for _, r := range s.routes {
+			routes = append(routes, entity.Route{If: r.cond, NextSlideID: ids[r.next]})
+		}
+		choicesJSON, err := json.Marshal(choices)
+		if err != nil {
+			t.Fatal(err)
+		}
+		routesJSON, err := json.Marshal(routes)
+		if err != nil {
+			t.Fatal(err)
+		}
+		slide.Choices = types.JSONB(choicesJSON)
+		slide.Routes = types.JSONB(routesJSON)
+		chapter.Slides = append(chapter.Slides, slide)
+	}
+	chapter.StartSlideID = &chapter.Slides[0].ID
+
+	version, err := entity.NewChapterVersion(chapter, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	version.ID = uuid.New()
+
+	repo := &fakeStoryRepo{
+		version: version,
+		session: entity.UserStorySession{
+			ID:               uuid.New(),
+			ChapterID:        chapter.ID,
+			Slot:             1,
+			CurrentSlideID:   chapter.Slides[0].ID,
+			CurrentHearts:    3,
+			Variables:        types.Variables{},
+			ChapterVersionID: &version.ID,
+		},
+	}
+	return repo, ids
+}
+
+func newTestUsecase(repo *fakeStoryRepo) *storyUsecase {
+	return &storyUsecase{
+		storyRepo: repo,
+		userRepo:  &fakeUserRepo{},
+		tx:        fakeTx{},
+		storage:   fakeStorage{},
+	}
+}
+
+func intRef(n int) *int { return &n }
+
+func TestSubmitActionConditions(t *testing.T) {
+	met := &entity.Condition{Flag: "met_sekar"}
+
+	tests := []struct {
+		name     string
+		start    testSlide
+		vars     types.Variables
+		choice   *int
+		wantNext string
+		wantErr  string
+	}{
+		{
+			name: "visible choice is taken",
+			start: testSlide{key: "start", choices: []testChoice{
+				{text: "greet", next: "greet", showIf: met},
+			}},
+			vars:     types.Variables{"met_sekar": true},
+			choice:   intRef(0),
+			wantNext: "greet",
+		},
+		{
+			name: "hidden choice can't be picked",
+			start: testSlide{key: "start", next: "leave", choices: []testChoice{
+				{text: "greet", next: "greet", showIf: met},
+			}},
+			choice:  intRef(0),
+			wantErr: i18n.StoryNoChoices,
+		},
+		{
+			name: "disabled choice can't be picked",
+			start: testSlide{key: "start", choices: []testChoice{
+				{text: "greet", next: "greet", enableIf: met},
+				{text: "leave", next: "leave"},
+			}},
+			choice:  intRef(0),
+			wantErr: i18n.StoryInvalidChoice,
+		},
+		{
+			name: "no selectable choice falls back to next",
+			start: testSlide{key: "start", next: "leave", choices: []testChoice{
+				{text: "greet", next: "greet", showIf: met},
+				{text: "bow", next: "greet", enableIf: met},
+			}},
+			wantNext: "leave",
+		},
+		{
+			name: "no selectable choice without fallback",
+			start: testSlide{key: "start", choices: []testChoice{
+				{text: "greet", next: "greet", showIf: met},
+			}},
+			wantErr: i18n.CommonTryAgain,
+		},
+		{
+			name: "route taken when its condition holds",
+			start: testSlide{key: "start", next: "leave", routes: []testRoute{
+				{cond: *met, next: "greet"},
+			}},
+			vars:     types.Variables{"met_sekar": true},
+			wantNext: "greet",
+		},
+		{
+			name: "route falls back to next",
+			start: testSlide{key: "start", next: "leave", routes: []testRoute{
+				{cond: *met, next: "greet"},
+			}},
+			wantNext: "leave",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, ids := newFakeChapter(t, tt.start, testSlide{key: "greet"}, testSlide{key: "leave"})
+			if tt.vars != nil {
+				repo.session.Variables = tt.vars
+			}
+
+			resp, apiErr := newTestUsecase(repo).SubmitAction(context.Background(), uuid.New(), &dto.StoryActionRequest{
+				ChapterID:   repo.session.ChapterID,
+				SlideID:     ids["start"],
+				ChoiceIndex: tt.choice,
+				Version:     intRef(0),
+			})
+
+			if tt.wantErr != "" {
+				if apiErr == nil || apiErr.Code != tt.wantErr {
+					t.Fatalf("SubmitAction() error = %v, want %s", apiErr, tt.wantErr)
+				}
+				if repo.session.IsCompleted || repo.session.Version != 0 {
+					t.Errorf("session was advanced: completed %v, version %d", repo.session.IsCompleted, repo.session.Version)
+				}
+				return
+			}
+			if apiErr != nil {
+				t.Fatalf("SubmitAction() error = %v", apiErr)
+			}
+			if resp.IsCompleted {
+				t.Error("chapter completed")
+			}
+			if resp.NextSlideID == nil || *resp.NextSlideID != ids[tt.wantNext] {
+				t.Errorf("NextSlideID = %v, want %s", resp.NextSlideID, tt.wantNext)
+			}
+			if repo.session.CurrentSlideID != ids[tt.wantNext] {
+				t.Errorf("session slide = %v, want %s", repo.session.CurrentSlideID, tt.wantNext)
+			}
+		})
+	}
+}
+
+func TestSubmitActionVersions(t *testing.T) {
+	stored := func(choice *int, answer *string, next string) *entity.UserStoryAction {
+		resp, _ := json.Marshal(dto.StoryActionResponse{Version: 1, Message: next})
+		return &entity.UserStoryAction{Version: 0, ChoiceIndex: choice, Answer: answer, Response: types.JSONB(resp)}
+	}
+	kula, aku := "kula", "aku"
+
+	tests := []struct {
+		name        string
+		version     *int
+		choice      *int
+		answer      *string
+		applied     *entity.UserStoryAction // action already applied at version 0
+		concurrent  *entity.UserStoryAction // action applied by another request while this one runs
+		wantReplay  string
+		wantErr     string
+		wantVersion int
+	}{
+		{
+			name:        "current version is applied",
+			version:     intRef(0),
+			choice:      intRef(1),
+			wantVersion: 1,
+		},
+		{
+			name:        "missing version",
+			choice:      intRef(1),
+			wantErr:     i18n.CommonInvalidFields,
+			wantVersion: 0,
+		},
+		{
+			name:        "version ahead of the session",
+			version:     intRef(1),
+			choice:      intRef(1),
+			wantErr:     i18n.StoryOutOfSync,
+			wantVersion: 0,
+		},
+		{
+			name:        "retry replays the applied action",
+			version:     intRef(0),
+			choice:      intRef(1),
+			applied:     stored(intRef(1), nil, "first"),
+			wantReplay:  "first",
+			wantVersion: 1,
+		},
+		{
+			name:        "different choice at an applied version",
+			version:     intRef(0),
+			choice:      intRef(0),
+			applied:     stored(intRef(1), nil, "first"),
+			wantErr:     i18n.StoryOutOfSync,
+			wantVersion: 1,
+		},
+		{
+			name:        "retry replays the graded answer",
+			version:     intRef(0),
+			answer:      &kula,
+			applied:     stored(nil, &kula, "graded"),
+			wantReplay:  "graded",
+			wantVersion: 1,
+		},
+		{
+			name:        "different answer at an applied version",
+			version:     intRef(0),
+			answer:      &aku,
+			applied:     stored(nil, &kula, "graded"),
+			wantErr:     i18n.StoryOutOfSync,
+			wantVersion: 1,
+		},
+		{
+			name:        "concurrent double tap replays the winner",
+			version:     intRef(0),
+			choice:      intRef(1),
+			concurrent:  stored(intRef(1), nil, "winner"),
+			wantReplay:  "winner",
+			wantVersion: 1,
+		},
+		{
+			name:        "concurrent different action",
+			version:     intRef(0),
+			choice:      intRef(1),
+			concurrent:  stored(intRef(0), nil, "winner"),
+			wantErr:     i18n.StoryOutOfSync,
+			wantVersion: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, ids := newFakeChapter(t,
+				testSlide{key: "start", choices: []testChoice{
+					{text: "left", next: "left"},
+					{text: "right", next: "right"},
+				}},
+				testSlide{key: "left"},
+				testSlide{key: "right"},
+			)
+			if tt.applied != nil {
+				tt.applied.SlideID = ids["start"]
+				repo.actions = map[int]*entity.UserStoryAction{0: tt.applied}
+				repo.session.Version = 1
+				repo.session.CurrentSlideID = ids["right"]
+			}
+			if tt.concurrent != nil {
+				tt.concurrent.SlideID = ids["start"]
+				repo.conflict = func(r *fakeStoryRepo) {
+					r.actions = map[int]*entity.UserStoryAction{0: tt.concurrent}
+					r.session.Version = 1
+				}
+			}
+
+			resp, apiErr := newTestUsecase(repo).SubmitAction(context.Background(), uuid.New(), &dto.StoryActionRequest{
+				ChapterID:   repo.session.ChapterID,
+				SlideID:     ids["start"],
+				ChoiceIndex: tt.choice,
+				Answer:      tt.answer,
+				Version:     tt.version,
+			})
+
+			switch {
+			case tt.wantErr != "":
+				if apiErr == nil || apiErr.Code != tt.wantErr {
+					t.Errorf("SubmitAction() error = %v, want %s", apiErr, tt.wantErr)
+				}
+			case apiErr != nil:
+				t.Errorf("SubmitAction() error = %v", apiErr)
+			case tt.wantReplay != "":
+				if resp.Message != tt.wantReplay {
+					t.Errorf("Message = %q, want the replayed %q", resp.Message, tt.wantReplay)
+				}
+			case resp.NextSlideID == nil || *resp.NextSlideID != ids["right"]:
+				t.Errorf("NextSlideID = %v, want right", resp.NextSlideID)
+			}
+
+			if repo.session.Version != tt.wantVersion {
+				t.Errorf("session version = %d, want %d", repo.session.Version, tt.wantVersion)
+			}
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package usecase
+
+import (
+	"github.com/Ablebil/lathi-be/internal/app/story/grading"
+	"github.com/Ablebil/lathi-be/internal/domain/dto"
+	"github.com/Ablebil/lathi-be/internal/domain/entity"
+)
+
+// gradeTranslation grades a typed answer against the accepted translations.
+func gradeTranslation(t *entity.Translation, answer string) *dto.TranslationResult {
+	graded := grading.Grade(answer, t.Accepted)
+
+	res := &dto.TranslationResult{
+		IsCorrect: graded.Correct,
+		Expected:  graded.Expected,
+	}
+	for _, tok := range graded.Tokens {
+		res.Tokens = append(res.Tokens, dto.TokenFeedback{
+			Text:     tok.Text,
+			Expected: tok.Expected,
+			Status:   tok.Status,
+		})
+	}
+	if !res.IsCorrect {
+		res.HeartsLost = t.HeartPenalty
+	}
+
+	return res
+}
@@ -0,0 +1,139 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/Ablebil/lathi-be/internal/domain/dto"
+	"github.com/Ablebil/lathi-be/internal/domain/entity"
+	"github.com/Ablebil/lathi-be/pkg/i18n"
+	"github.com/Ablebil/lathi-be/pkg/response"
+	"github.com/google/uuid"
+)
+
+// chapterLock is what keeps a player from starting a chapter.
+type chapterLock struct {
+	availableAt *time.Time         // publish time while it hasn't passed
+	unmet       entity.UnlockRules // rules the player doesn't meet yet
+	words       int                // words the player collected, for min_vocab
+}
+
+func (l *chapterLock) isLocked() bool {
+	return l.availableAt != nil || !l.unmet.IsEmpty()
+}
+
+// chapterLocks evaluates the publish time and unlock rules of the published
+// chapters for the user. Chapters without rules need the chapter before them
+// in their story, so a story still unlocks one chapter after another and
+// starts open. completed holds the IDs of the chapters the user completed.
+func (uc *storyUsecase) chapterLocks(ctx context.Context, userID uuid.UUID, chapters []entity.Chapter, completed map[uuid.UUID]bool) (map[uuid.UUID]*chapterLock, error) {
+	st := &entity.UnlockState{
+		CompletedChapters: completed,
+	}
+
+	rules := make([]*entity.UnlockRules, len(chapters))
+	var endingIDs []uuid.UUID
+	needWords := false
+	for i := range chapters {
+		r, err := chapters[i].GetUnlockRules()
+		if err != nil {
+			return nil, err
+		}
+		if r.IsEmpty() && i > 0 && chapters[i-1].StoryID == chapters[i].StoryID {
+			r = &entity.UnlockRules{ChaptersCompleted: []uuid.UUID{chapters[i-1].ID}}
+		}
+
+		rules[i] = r
+		endingIDs = append(endingIDs, r.EndingsReached...)
+		needWords = needWords || r.MinVocab > 0
+	}
+
+	var err error
+	if st.ReachedEndings, err = uc.storyRepo.GetReachedEndings(ctx, userID, endingIDs); err != nil {
+		return nil, err
+	}
+	if needWords {
+		user, err := uc.userRepo.GetUserByID(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		if user != nil {
+			st.WordsCollected = user.TotalWordsCollected
+		}
+	}
+
+	now := time.Now()
+	locks := make(map[uuid.UUID]*chapterLock, len(chapters))
+	for i, ch := range chapters {
+		lock := &chapterLock{
+			unmet: rules[i].Unmet(st),
+			words: st.WordsCollected,
+		}
+		if !ch.IsReleased(now) {
+			lock.availableAt = ch.PublishAt
+		}
+		locks[ch.ID] = lock
+	}
+	return locks, nil
+}
+
+// chapterLockOf evaluates the lock of a single published chapter, nil when
+// the chapter isn't published.
+func (uc *storyUsecase) chapterLockOf(ctx context.Context, userID, chapterID uuid.UUID) (*chapterLock, error) {
+	chapters, err := uc.storyRepo.GetAllChapters(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	completed, err := uc.storyRepo.GetCompletedChapters(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	locks, err := uc.chapterLocks(ctx, userID, chapters, completed)
+	if err != nil {
+		return nil, err
+	}
+	return locks[chapterID], nil
+}
+
+// checkUnlocked refuses chapters that aren't published, aren't released yet
+// or whose unlock rules the user doesn't meet.
+func (uc *storyUsecase) checkUnlocked(ctx context.Context, userID, chapterID uuid.UUID) *response.APIError {
+	lock, err := uc.chapterLockOf(ctx, userID, chapterID)
+	if err != nil {
+		slog.Error("failed to check chapter unlock", "error", err)
+		return response.ErrInternal(i18n.CommonTryAgain)
+	}
+	if lock == nil {
+		return response.ErrNotFound(i18n.StoryChapterNotFound)
+	}
+	if lock.availableAt != nil {
+		return response.ErrForbidden(i18n.StoryChapterNotReleased)
+	}
+	if lock.isLocked() {
+		return response.ErrForbidden(i18n.StoryChapterLocked)
+	}
+	return nil
+}
+
+func toRequirementResponse(lock *chapterLock) *dto.ChapterRequirementResponse {
+	if lock.unmet.IsEmpty() {
+		return nil
+	}
+
+	resp := &dto.ChapterRequirementResponse{
+		ChaptersCompleted: lock.unmet.ChaptersCompleted,
+		MinVocab:          lock.unmet.MinVocab,
+		WordsCollected:    lock.words,
+		EndingsReached:    lock.unmet.EndingsReached,
+	}
+	if resp.ChaptersCompleted == nil {
+		resp.ChaptersCompleted = []uuid.UUID{}
+	}
+	if resp.EndingsReached == nil {
+		resp.EndingsReached = []uuid.UUID{}
+	}
+	return resp
+}
@@ -0,0 +1,64 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/Ablebil/lathi-be/internal/domain/dto"
+	"github.com/Ablebil/lathi-be/pkg/i18n"
+	"github.com/Ablebil/lathi-be/pkg/response"
+	"github.com/google/uuid"
+)
+
+// GetUpcomingSlides returns the slot's current slide and the slides the player
+// can reach from it, so the client never needs the whole chapter. Locked
+// chapters are refused like when starting them.
+func (uc *storyUsecase) GetUpcomingSlides(ctx context.Context, userID, chapterID uuid.UUID, slot int, req *dto.UpcomingSlidesRequest) (*dto.UpcomingSlidesResponse, *response.APIError) {
+	if req.Depth != nil && *req.Depth < 0 {
+		return nil, response.ErrBadRequest(i18n.StoryInvalidDepth)
+	}
+	depth := prefetchDepth(req.Depth)
+
+	if apiErr := uc.checkUnlocked(ctx, userID, chapterID); apiErr != nil {
+		return nil, apiErr
+	}
+
+	session, err := uc.storyRepo.FindSession(ctx, userID, chapterID, slot)
+	if err != nil {
+		slog.Error("failed to get session", "error", err)
+		return nil, response.ErrInternal(i18n.CommonTryAgain)
+	}
+
+	chapter, err := uc.sessionChapter(ctx, chapterID, session)
+	if err != nil {
+		slog.Error("failed to get chapter", "error", err)
+		return nil, response.ErrInternal(i18n.CommonTryAgain)
+	}
+	if chapter == nil {
+		return nil, response.ErrNotFound(i18n.StoryChapterNotFound)
+	}
+	if session == nil {
+		return nil, response.ErrBadRequest(i18n.StoryNotStarted)
+	}
+
+	v, err := uc.viewer(ctx, userID, chapter, req.Subtitle)
+	if err != nil {
+		slog.Error("failed to load protagonist", "error", err, "chapter_id", chapter.ID)
+		return nil, response.ErrInternal(i18n.CommonTryAgain)
+	}
+
+	slides, err := uc.upcomingSlides(ctx, userID, chapter, session, depth, v)
+	if err != nil {
+		slog.Error("failed to load upcoming slides", "error", err, "chapter_id", chapter.ID)
+		return nil, response.ErrInternal(i18n.CommonTryAgain)
+	}
+
+	return &dto.UpcomingSlidesResponse{
+		ChapterID:        chapter.ID,
+		CurrentSlideID:   session.CurrentSlideID,
+		Depth:            depth,
+		Protagonist:      uc.toProtagonistResponse(v.player),
+		SubtitleLanguage: v.subtitle,
+		Slides:           slides,
+	}, nil
+}
@@ -0,0 +1,49 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/Ablebil/lathi-be/internal/domain/entity"
+	"github.com/google/uuid"
+)
+
+// publishedChapter loads the chapter as new runs get it, with the id of the
+// version it comes from. The chapter is nil when it was never published.
+func (uc *storyUsecase) publishedChapter(ctx context.Context, chapterID uuid.UUID) (*entity.Chapter, uuid.UUID, error) {
+	version, err := uc.storyRepo.GetPublishedVersion(ctx, chapterID)
+	if err != nil || version == nil {
+		return nil, uuid.Nil, err
+	}
+
+	chapter, err := version.GetChapter()
+	if err != nil {
+		return nil, uuid.Nil, err
+	}
+	return chapter, version.ID, nil
+}
+
+// sessionChapter loads the chapter version the session was started on, so a
+// publish never changes the story under a run in progress. Without a session,
+// or for sessions from before versioning, it is the published version.
+func (uc *storyUsecase) sessionChapter(ctx context.Context, chapterID uuid.UUID, session *entity.UserStorySession) (*entity.Chapter, error) {
+	if session == nil || session.ChapterVersionID == nil {
+		chapter, _, err := uc.publishedChapter(ctx, chapterID)
+		return chapter, err
+	}
+
+	version, err := uc.storyRepo.GetChapterVersion(ctx, *session.ChapterVersionID)
+	if err != nil || version == nil {
+		return nil, err
+	}
+	return version.GetChapter()
+}
+
+// findEnding looks the ending up in the chapter version.
+func findEnding(chapter *entity.Chapter, endingID uuid.UUID) *entity.ChapterEnding {
+	for i := range chapter.Endings {
+		if chapter.Endings[i].ID == endingID {
+			return &chapter.Endings[i]
+		}
+	}
+	return nil
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/Ablebil/lathi-be/internal/domain/contract"
 	"github.com/Ablebil/lathi-be/internal/domain/dto"
 	"github.com/Ablebil/lathi-be/internal/middleware"
+	"github.com/Ablebil/lathi-be/pkg/i18n"
 	"github.com/Ablebil/lathi-be/pkg/response"
 	"github.com/Ablebil/lathi-be/pkg/validator"
 	"github.com/gofiber/fiber/v2"
@@ -35,7 +36,7 @@ func NewUserHandler(router fiber.Router, validator validator.ValidatorItf, env *
 func (h *userHandler) getProfile(ctx *fiber.Ctx) error {
 	userIDStr, ok := ctx.Locals("user_id").(string)
 	if !ok {
-		return response.Error(ctx, response.ErrUnauthorized("Kamu belum login, yuk login dulu"), nil)
+		return response.Error(ctx, response.ErrUnauthorized(i18n.AuthNotLoggedIn), nil)
 	}
 	userID, _ := uuid.Parse(userIDStr)
 
@@ -44,19 +45,19 @@ func (h *userHandler) getProfile(ctx *fiber.Ctx) error {
 		return response.Error(ctx, apiErr, nil)
 	}
 
-	return response.Success(ctx, fiber.StatusOK, "Profilmu berhasil dimuat", resp)
+	return response.Success(ctx, fiber.StatusOK, i18n.UserProfileLoaded, resp)
 }
 
 func (h *userHandler) editProfile(ctx *fiber.Ctx) error {
 	userIDStr, ok := ctx.Locals("user_id").(string)
 	if !ok {
-		return response.Error(ctx, response.ErrUnauthorized("Kamu belum login, yuk login dulu"), nil)
+		return response.Error(ctx, response.ErrUnauthorized(i18n.AuthNotLoggedIn), nil)
 	}
 	userID, _ := uuid.Parse(userIDStr)
 
 	req := new(dto.EditUserProfileRequest)
 	if err := ctx.BodyParser(&req); err != nil {
-		return response.Error(ctx, response.ErrBadRequest("Data yang kamu kirim belum pas, coba cek lagi ya"), err)
+		return response.Error(ctx, response.ErrBadRequest(i18n.CommonInvalidBody), err)
 	}
 
 	if err := h.val.ValidateStruct(req); err != nil {
@@ -68,13 +69,13 @@ func (h *userHandler) editProfile(ctx *fiber.Ctx) error {
 		return response.Error(ctx, apiErr, nil)
 	}
 
-	return response.Success(ctx, fiber.StatusOK, "Profilmu berhasil diperbarui", resp)
+	return response.Success(ctx, fiber.StatusOK, i18n.UserProfileUpdated, resp)
 }
 
 func (h *userHandler) deleteAccount(ctx *fiber.Ctx) error {
 	userIDStr, ok := ctx.Locals("user_id").(string)
 	if !ok {
-		return response.Error(ctx, response.ErrUnauthorized("Kamu belum login, yuk login dulu"), nil)
+		return response.Error(ctx, response.ErrUnauthorized(i18n.AuthNotLoggedIn), nil)
 	}
 	userID, _ := uuid.Parse(userIDStr)
 
@@ -7,6 +7,7 @@ import (
 
 	"github.com/Ablebil/lathi-be/internal/domain/contract"
 	"github.com/Ablebil/lathi-be/internal/domain/entity"
+	"github.com/Ablebil/lathi-be/internal/infra/postgresql"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
@@ -24,7 +25,7 @@ func NewUserRepository(db *gorm.DB) contract.UserRepositoryItf {
 
 func (r *userRepository) GetUserByEmail(ctx context.Context, email string) (*entity.User, error) {
 	var user entity.User
-	err := r.db.WithContext(ctx).Where("email = ?", email).First(&user).Error
+	err := postgresql.Conn(ctx, r.db).Where("email = ?", email).First(&user).Error
 
 	if errors.Is(err, gorm.ErrRecordNotFound) {
 		return nil, nil
@@ -39,7 +40,7 @@ func (r *userRepository) GetUserByEmail(ctx context.Context, email string) (*ent
 
 func (r *userRepository) GetUserByUsername(ctx context.Context, username string) (*entity.User, error) {
 	var user entity.User
-	err := r.db.WithContext(ctx).Where("username = ?", username).First(&user).Error
+	err := postgresql.Conn(ctx, r.db).Where("username = ?", username).First(&user).Error
 
 	if errors.Is(err, gorm.ErrRecordNotFound) {
 		return nil, nil
@@ -54,7 +55,7 @@ func (r *userRepository) GetUserByUsername(ctx context.Context, username string)
 
 func (r *userRepository) GetUserByID(ctx context.Context, id uuid.UUID) (*entity.User, error) {
 	var user entity.User
-	err := r.db.WithContext(ctx).Where("id = ?", id).First(&user).Error
+	err := postgresql.Conn(ctx, r.db).Where("id = ?", id).First(&user).Error
 
 	if errors.Is(err, gorm.ErrRecordNotFound) {
 		return nil, nil
@@ -69,7 +70,7 @@ func (r *userRepository) GetUserByID(ctx context.Context, id uuid.UUID) (*entity
 
 func (r *userRepository) GetUserWithBadges(ctx context.Context, id uuid.UUID) (*entity.User, error) {
 	var user entity.User
-	err := r.db.WithContext(ctx).Preload("UserBadges.Badge").Where("id = ?", id).First(&user).Error
+	err := postgresql.Conn(ctx, r.db).Preload("UserBadges.Badge").Where("id = ?", id).First(&user).Error
 
 	if errors.Is(err, gorm.ErrRecordNotFound) {
 		return nil, nil
@@ -83,43 +84,56 @@ func (r *userRepository) GetUserWithBadges(ctx context.Context, id uuid.UUID) (*
 }
 
 func (r *userRepository) CreateUser(ctx context.Context, user *entity.User) error {
-	return r.db.WithContext(ctx).Create(user).Error
+	return postgresql.Conn(ctx, r.db).Create(user).Error
 }
 
 func (r *userRepository) UpdateUser(ctx context.Context, user *entity.User) error {
-	return r.db.WithContext(ctx).Save(user).Error
+	return postgresql.Conn(ctx, r.db).Save(user).Error
 }
 
-func (r *userRepository) GetUserLastCompletedChapter(ctx context.Context, userID uuid.UUID) (int, error) {
-	var user entity.User
-	if err := r.db.WithContext(ctx).Select("last_chapter_completed").First(&user, userID).Error; err != nil {
-		return 0, err
-	}
-	return user.LastChapterCompleted, nil
-}
-
-func (r *userRepository) UpdateUserLastCompletedChapter(ctx context.Context, userID uuid.UUID, orderIndex int) error {
-	// udpate only if new orderIndex > current value
-	return r.db.WithContext(ctx).Model(&entity.User{}).
-		Where("id = ? AND last_chapter_completed < ?", userID, orderIndex).
-		Update("last_chapter_completed", orderIndex).Error
+// SyncCompletedChapters sets the user's completed chapters to the published
+// chapters they completed in every story and returns it.
+func (r *userRepository) SyncCompletedChapters(ctx context.Context, userID uuid.UUID) (int, error) {
+	var completed int
+	err := postgresql.Conn(ctx, r.db).Raw(`
+		UPDATE users SET chapters_completed = (
+			SELECT COUNT(*) FROM user_chapter_completions ucc
+			JOIN chapters c ON c.id = ucc.chapter_id AND c.published_version_id IS NOT NULL
+			WHERE ucc.user_id = users.id
+		)
+		WHERE id = ?
+		RETURNING chapters_completed`, userID).
+		Scan(&completed).Error
+	return completed, err
 }
 
 func (r *userRepository) IncrementUserWordCount(ctx context.Context, userID uuid.UUID, amount int) error {
-	return r.db.WithContext(ctx).Model(&entity.User{}).
+	return postgresql.Conn(ctx, r.db).Model(&entity.User{}).
 		Where("id = ?", userID).
 		UpdateColumn("total_words_collected", gorm.Expr("total_words_collected + ?", amount)).Error
 }
 
+func (r *userRepository) AddBonusScore(ctx context.Context, userID uuid.UUID, amount int) error {
+	return postgresql.Conn(ctx, r.db).Model(&entity.User{}).
+		Where("id = ?", userID).
+		UpdateColumn("bonus_score", gorm.Expr("bonus_score + ?", amount)).Error
+}
+
 func (r *userRepository) UpdateUserTitle(ctx context.Context, userID uuid.UUID, title entity.Title) error {
-	return r.db.WithContext(ctx).Model(&entity.User{}).
+	return postgresql.Conn(ctx, r.db).Model(&entity.User{}).
 		Where("id = ?", userID).
 		Update("current_title", title).Error
 }
 
+func (r *userRepository) UpdateUserRole(ctx context.Context, userID uuid.UUID, role entity.Role) error {
+	return postgresql.Conn(ctx, r.db).Model(&entity.User{}).
+		Where("id = ?", userID).
+		Update("role", role).Error
+}
+
 func (r *userRepository) AssignBadge(ctx context.Context, userID uuid.UUID, badgeCode string) error {
 	var badge entity.Badge
-	if err := r.db.WithContext(ctx).Where("code = ?", badgeCode).First(&badge).Error; err != nil {
+	if err := postgresql.Conn(ctx, r.db).Where("code = ?", badgeCode).First(&badge).Error; err != nil {
 		return err
 	}
 
@@ -129,13 +143,13 @@ func (r *userRepository) AssignBadge(ctx context.Context, userID uuid.UUID, badg
 		EarnedAt: time.Now(),
 	}
 
-	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+	return postgresql.Conn(ctx, r.db).Clauses(clause.OnConflict{
 		DoNothing: true,
 	}).Create(&userBadge).Error
 }
 
 func (r *userRepository) DeleteUnverifiedUsers(ctx context.Context, threshold time.Time) (int64, error) {
-	result := r.db.WithContext(ctx).
+	result := postgresql.Conn(ctx, r.db).
 		Where("is_verified = ?", false).
 		Where("created_at < ?", threshold).
 		Delete(&entity.User{})
@@ -148,5 +162,5 @@ func (r *userRepository) DeleteUnverifiedUsers(ctx context.Context, threshold ti
 }
 
 func (r *userRepository) DeleteUser(ctx context.Context, userID uuid.UUID) error {
-	return r.db.WithContext(ctx).Delete(&entity.User{}, userID).Error
+	return postgresql.Conn(ctx, r.db).Delete(&entity.User{}, userID).Error
 }
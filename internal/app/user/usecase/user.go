@@ -5,12 +5,15 @@ import (
 	"fmt"
 	"log/slog"
 	"math"
+	"strings"
 
 	"github.com/Ablebil/lathi-be/internal/config"
 	"github.com/Ablebil/lathi-be/internal/domain/contract"
 	"github.com/Ablebil/lathi-be/internal/domain/dto"
+	"github.com/Ablebil/lathi-be/internal/domain/entity"
 	"github.com/Ablebil/lathi-be/internal/infra/minio"
 	"github.com/Ablebil/lathi-be/internal/infra/redis"
+	"github.com/Ablebil/lathi-be/pkg/i18n"
 	"github.com/Ablebil/lathi-be/pkg/response"
 	"github.com/google/uuid"
 )
@@ -40,29 +43,34 @@ func NewUserUsecase(userRepo contract.UserRepositoryItf, storyRepo contract.Stor
 func (uc *userUsecase) GetUserProfile(ctx context.Context, userID uuid.UUID) (*dto.UserProfileResponse, *response.APIError) {
 	user, err := uc.userRepo.GetUserWithBadges(ctx, userID)
 	if err != nil {
-		return nil, response.ErrNotFound("Akun ga ditemukan, coba daftar dulu ya")
+		return nil, response.ErrNotFound(i18n.UserNotFound)
 	}
 
 	totalChapters, err := uc.storyRepo.CountChapters(ctx)
 	if err != nil {
 		slog.Error("failed to count chapters", "error", err)
-		return nil, response.ErrInternal("Coba lagi nanti ya!")
+		return nil, response.ErrInternal(i18n.CommonTryAgain)
 	}
 
 	totalVocabs, err := uc.dictRepo.CountTotalVocabs(ctx)
 	if err != nil {
 		slog.Error("failed to count vocabs", "error", err)
-		return nil, response.ErrInternal("Coba lagi nanti ya!")
+		return nil, response.ErrInternal(i18n.CommonTryAgain)
 	}
 
-	progressPercent := 0.0
-	if totalChapters > 0 {
-		progressPercent = (float64(user.LastChapterCompleted) / float64(totalChapters)) * 100
-		if progressPercent > 100 {
-			progressPercent = 100
-		}
+	attempts, err := uc.storyRepo.GetAttemptStats(ctx, userID)
+	if err != nil {
+		slog.Error("failed to get attempt stats", "error", err)
+		return nil, response.ErrInternal(i18n.CommonTryAgain)
+	}
+
+	storyStats, err := uc.storyStats(ctx, userID)
+	if err != nil {
+		slog.Error("failed to get story progress", "error", err)
+		return nil, response.ErrInternal(i18n.CommonTryAgain)
 	}
-	progressPercent = math.Round(progressPercent*100) / 100
+
+	progressPercent := progressOf(user.ChaptersCompleted, int(totalChapters))
 
 	var badgeResponses []dto.UserBadgeResponse
 	for _, ub := range user.UserBadges {
@@ -86,47 +94,128 @@ func (uc *userUsecase) GetUserProfile(ctx context.Context, userID uuid.UUID) (*d
 	}
 
 	return &dto.UserProfileResponse{
-		ID:           user.ID,
-		Username:     user.Username,
-		Email:        user.Email,
-		AvatarURL:    uc.storage.GetObjectURL(user.AvatarURL),
-		CurrentTitle: string(user.CurrentTitle),
+		ID:               user.ID,
+		Username:         user.Username,
+		DisplayName:      user.DisplayName,
+		Language:         user.Language,
+		SubtitleLanguage: user.SubtitleLanguage,
+		Email:            user.Email,
+		Role:             string(user.Role),
+		AvatarURL:        uc.storage.GetObjectURL(user.AvatarURL),
+		CurrentTitle:     string(user.CurrentTitle),
 		Stats: dto.UserStatsResponse{
 			TotalChapters:     totalChapters,
-			CompletedChapters: user.LastChapterCompleted,
+			CompletedChapters: user.ChaptersCompleted,
 			ProgressPercent:   progressPercent,
 			TotalVocabs:       totalVocabs,
 			CollectedVocabs:   user.TotalWordsCollected,
+			TotalAttempts:     attempts.Total,
+			CompletedAttempts: attempts.Completed,
+			GameOvers:         attempts.GameOver,
+			Stories:           storyStats,
 		},
 		Badges:          badgeResponses,
 		LeaderboardInfo: lbInfo,
 	}, nil
 }
 
+// storyStats is the user's progress in each story that has published chapters.
+func (uc *userUsecase) storyStats(ctx context.Context, userID uuid.UUID) ([]dto.UserStoryStatsResponse, error) {
+	stories, err := uc.storyRepo.GetStories(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	totals, err := uc.storyRepo.CountChaptersByStory(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	progress, err := uc.storyRepo.GetStoryProgress(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	done, err := uc.storyRepo.CountCompletedChaptersByStory(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := make([]dto.UserStoryStatsResponse, 0, len(stories))
+	for _, story := range stories {
+		p, ok := progress[story.ID]
+		if !ok {
+			p.Title = entity.Cantrik
+		}
+
+		total := totals[story.ID]
+		completed := done[story.ID]
+		stats = append(stats, dto.UserStoryStatsResponse{
+			StoryID:           story.ID,
+			Title:             story.Title,
+			TotalChapters:     total,
+			CompletedChapters: completed,
+			ProgressPercent:   progressOf(completed, total),
+			PlayerTitle:       string(p.Title),
+		})
+	}
+	return stats, nil
+}
+
+// progressOf is the completed share of total as a percentage, rounded to two
+// decimals and capped at 100.
+func progressOf(completed, total int) float64 {
+	if total <= 0 {
+		return 0
+	}
+
+	progress := min((float64(completed)/float64(total))*100, 100)
+	return math.Round(progress*100) / 100
+}
+
 func (uc *userUsecase) EditUserProfile(ctx context.Context, userID uuid.UUID, req *dto.EditUserProfileRequest) (*dto.UserProfileResponse, *response.APIError) {
 	user, err := uc.userRepo.GetUserByID(ctx, userID)
 	if err != nil {
 		slog.Error("failed to get user", "error", err)
-		return nil, response.ErrInternal("Coba lagi nanti ya!")
+		return nil, response.ErrInternal(i18n.CommonTryAgain)
 	}
 
-	if user.Username == req.Username {
-		return nil, response.ErrBadRequest("Username baru sama dengan yang lama")
+	if req.Username == "" && req.DisplayName == nil && req.Language == nil && req.SubtitleLanguage == nil {
+		return nil, response.ErrBadRequest(i18n.UserNothingToUpdate)
 	}
 
-	existingUser, err := uc.userRepo.GetUserByUsername(ctx, req.Username)
-	if err != nil {
-		slog.Error("failed to get user", "error", err)
-		return nil, response.ErrInternal("Coba lagi nanti ya!")
+	if req.Username != "" {
+		if user.Username == req.Username {
+			return nil, response.ErrBadRequest(i18n.UserSameUsername)
+		}
+
+		existingUser, err := uc.userRepo.GetUserByUsername(ctx, req.Username)
+		if err != nil {
+			slog.Error("failed to get user", "error", err)
+			return nil, response.ErrInternal(i18n.CommonTryAgain)
+		}
+		if existingUser != nil {
+			return nil, response.ErrConflict(i18n.UserUsernameTaken)
+		}
+
+		user.Username = req.Username
 	}
-	if existingUser != nil {
-		return nil, response.ErrConflict("Username ini udah dipake, coba yang lain ya")
+
+	if req.DisplayName != nil {
+		user.DisplayName = strings.TrimSpace(*req.DisplayName)
+	}
+
+	if req.Language != nil {
+		user.Language = *req.Language
+	}
+
+	if req.SubtitleLanguage != nil {
+		user.SubtitleLanguage = *req.SubtitleLanguage
 	}
 
-	user.Username = req.Username
 	if err := uc.userRepo.UpdateUser(ctx, user); err != nil {
 		slog.Error("failed to update user", "error", err)
-		return nil, response.ErrInternal("Coba lagi nanti ya!")
+		return nil, response.ErrInternal(i18n.CommonTryAgain)
 	}
 
 	return uc.GetUserProfile(ctx, userID)
@@ -136,15 +225,15 @@ func (uc *userUsecase) DeleteAccount(ctx context.Context, userID uuid.UUID, refr
 	user, err := uc.userRepo.GetUserByID(ctx, userID)
 	if err != nil {
 		slog.Error("failed to get user", "error", err)
-		return response.ErrInternal("Coba lagi nanti ya!")
+		return response.ErrInternal(i18n.CommonTryAgain)
 	}
 	if user == nil {
-		return response.ErrNotFound("Akun ga ditemukan, coba daftar dulu ya")
+		return response.ErrNotFound(i18n.UserNotFound)
 	}
 
 	if err := uc.userRepo.DeleteUser(ctx, userID); err != nil {
 		slog.Error("failed to delete user", "error", err)
-		return response.ErrInternal("Coba lagi nanti ya!")
+		return response.ErrInternal(i18n.CommonTryAgain)
 	}
 
 	if refreshToken != "" {
@@ -0,0 +1,87 @@
+package contract
+
+import (
+	"context"
+
+	"github.com/Ablebil/lathi-be/internal/domain/dto"
+	"github.com/Ablebil/lathi-be/internal/domain/entity"
+	"github.com/Ablebil/lathi-be/pkg/response"
+	"github.com/google/uuid"
+)
+
+type AdminUsecaseItf interface {
+	ListStories(ctx context.Context) ([]dto.AdminStoryResponse, *response.APIError)
+	CreateStory(ctx context.Context, req *dto.AdminStoryRequest) (*dto.AdminStoryResponse, *response.APIError)
+	UpdateStory(ctx context.Context, storyID uuid.UUID, req *dto.AdminStoryRequest) (*dto.AdminStoryResponse, *response.APIError)
+	ListChapters(ctx context.Context) ([]dto.AdminChapterResponse, *response.APIError)
+	GetChapter(ctx context.Context, chapterID uuid.UUID) (*dto.AdminChapterDetailResponse, *response.APIError)
+	CreateChapter(ctx context.Context, req *dto.AdminChapterRequest) (*dto.AdminChapterResponse, *response.APIError)
+	UpdateChapter(ctx context.Context, chapterID uuid.UUID, req *dto.AdminChapterRequest) (*dto.AdminChapterResponse, *response.APIError)
+	DeleteChapter(ctx context.Context, chapterID uuid.UUID) *response.APIError
+	ReorderChapters(ctx context.Context, req *dto.AdminChapterOrderRequest) ([]dto.AdminChapterResponse, *response.APIError)
+	GetSlide(ctx context.Context, slideID uuid.UUID) (*dto.AdminSlideResponse, *response.APIError)
+	CreateSlide(ctx context.Context, chapterID uuid.UUID, req *dto.AdminSlideRequest) (*dto.AdminSlideResponse, *response.APIError)
+	UpdateSlide(ctx context.Context, slideID uuid.UUID, req *dto.AdminSlideRequest) (*dto.AdminSlideResponse, *response.APIError)
+	DeleteSlide(ctx context.Context, slideID uuid.UUID) *response.APIError
+	AddChoice(ctx context.Context, slideID uuid.UUID, req *dto.AdminChoice) (*dto.AdminSlideResponse, *response.APIError)
+	UpdateChoice(ctx context.Context, slideID uuid.UUID, index int, req *dto.AdminChoice) (*dto.AdminSlideResponse, *response.APIError)
+	DeleteChoice(ctx context.Context, slideID uuid.UUID, index int) (*dto.AdminSlideResponse, *response.APIError)
+	ListWords(ctx context.Context, req *dto.AdminWordListRequest) (*dto.AdminWordListResponse, *response.APIError)
+	CreateWord(ctx context.Context, req *dto.AdminWordRequest) (*dto.VocabItemResponse, *response.APIError)
+	UpdateWord(ctx context.Context, wordID uuid.UUID, req *dto.AdminWordRequest) (*dto.VocabItemResponse, *response.APIError)
+	DeleteWord(ctx context.Context, wordID uuid.UUID) *response.APIError
+	PublishChapter(ctx context.Context, chapterID uuid.UUID) (*dto.AdminChapterVersionResponse, *response.APIError)
+	ListChapterVersions(ctx context.Context, chapterID uuid.UUID) ([]dto.AdminChapterVersionResponse, *response.APIError)
+	RollbackChapter(ctx context.Context, chapterID uuid.UUID, req *dto.AdminRollbackRequest) (*dto.AdminChapterVersionResponse, *response.APIError)
+}
+
+type AdminRepositoryItf interface {
+	ListChapters(ctx context.Context) ([]entity.Chapter, error)
+	CountSlidesByChapter(ctx context.Context) (map[uuid.UUID]int, error)
+	GetChapter(ctx context.Context, id uuid.UUID) (*entity.Chapter, error)
+	LockChapter(ctx context.Context, id uuid.UUID) (*entity.Chapter, error)
+	LockChapters(ctx context.Context, storyID uuid.UUID) ([]entity.Chapter, error)
+	CreateChapter(ctx context.Context, chapter *entity.Chapter) error
+	UpdateChapter(ctx context.Context, chapter *entity.Chapter) error
+	SetStartSlide(ctx context.Context, chapterID, slideID uuid.UUID) error
+	DeleteChapter(ctx context.Context, id uuid.UUID) error
+	DropUnlockChapter(ctx context.Context, id uuid.UUID) error
+	SyncCompletedChapters(ctx context.Context) (bool, error)
+	SyncBonusScores(ctx context.Context) (bool, error)
+	CountPublishedChaptersByStory(ctx context.Context) (map[uuid.UUID]int, error)
+	ListStoryProgress(ctx context.Context, storyID uuid.UUID) ([]entity.UserStoryProgress, map[uuid.UUID]int, error)
+	ListUserTitles(ctx context.Context) ([]entity.User, error)
+	UpdateStoryTitles(ctx context.Context, storyID uuid.UUID, titles map[uuid.UUID]entity.Title) error
+	UpdateUserTitles(ctx context.Context, titles map[uuid.UUID]entity.Title) error
+	CountChaptersByIDs(ctx context.Context, ids []uuid.UUID) (int64, error)
+	CountEndingsByIDs(ctx context.Context, ids []uuid.UUID, exceptChapterID uuid.UUID) (int64, error)
+	SetChapterOrder(ctx context.Context, order map[uuid.UUID]int) error
+	ListStories(ctx context.Context) ([]entity.Story, error)
+	CountChaptersByStory(ctx context.Context) (map[uuid.UUID]int, error)
+	GetStoryBySlug(ctx context.Context, slug string) (*entity.Story, error)
+	GetChapterStory(ctx context.Context, chapterID uuid.UUID) (*entity.Story, error)
+	LockStory(ctx context.Context, id uuid.UUID) (*entity.Story, error)
+	CreateStory(ctx context.Context, story *entity.Story) error
+	UpdateStory(ctx context.Context, story *entity.Story) error
+	BadgeExists(ctx context.Context, code string) (bool, error)
+	GetSlide(ctx context.Context, id uuid.UUID) (*entity.Slide, error)
+	CreateSlide(ctx context.Context, slide *entity.Slide) error
+	UpdateSlide(ctx context.Context, slide *entity.Slide) error
+	UpdateSlideChoices(ctx context.Context, slide *entity.Slide) error
+	ReplaceSlideVocab(ctx context.Context, slide *entity.Slide, vocabIDs []uuid.UUID) error
+	DeleteSlide(ctx context.Context, id uuid.UUID) error
+	ListWords(ctx context.Context, search string, limit, offset int) ([]entity.Dictionary, int64, error)
+	GetWordsByIDs(ctx context.Context, ids []uuid.UUID) ([]entity.Dictionary, error)
+	GetWord(ctx context.Context, id uuid.UUID) (*entity.Dictionary, error)
+	FindWordByKrama(ctx context.Context, krama string) (*entity.Dictionary, error)
+	CreateWord(ctx context.Context, word *entity.Dictionary) error
+	UpdateWord(ctx context.Context, word *entity.Dictionary) error
+	DeleteWord(ctx context.Context, id uuid.UUID) error
+	CountWordUsage(ctx context.Context, id uuid.UUID) (int64, error)
+	ChapterIDsByWord(ctx context.Context, id uuid.UUID) ([]uuid.UUID, error)
+	ListChapterVersions(ctx context.Context, chapterID uuid.UUID) ([]entity.ChapterVersion, error)
+	GetChapterVersion(ctx context.Context, id uuid.UUID) (*entity.ChapterVersion, error)
+	FindChapterVersion(ctx context.Context, chapterID uuid.UUID, number int) (*entity.ChapterVersion, error)
+	PublishChapter(ctx context.Context, chapter *entity.Chapter) (*entity.ChapterVersion, error)
+	SetPublishedVersion(ctx context.Context, chapterID, versionID uuid.UUID) error
+}
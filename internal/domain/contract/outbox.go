@@ -0,0 +1,21 @@
+package contract
+
+import (
+	"context"
+	"time"
+
+	"github.com/Ablebil/lathi-be/internal/domain/entity"
+	"github.com/google/uuid"
+)
+
+type OutboxUsecaseItf interface {
+	Dispatch(ctx context.Context) (int, error)
+}
+
+type OutboxRepositoryItf interface {
+	Enqueue(ctx context.Context, topic string, payload any) error
+	ClaimPending(ctx context.Context, limit, maxAttempts int) ([]entity.OutboxEvent, error)
+	MarkProcessed(ctx context.Context, id uuid.UUID) error
+	MarkFailed(ctx context.Context, id uuid.UUID, reason string, retryAt time.Time) error
+	DeleteProcessed(ctx context.Context, before time.Time) (int64, error)
+}
@@ -5,25 +5,69 @@ import (
 
 	"github.com/Ablebil/lathi-be/internal/domain/dto"
 	"github.com/Ablebil/lathi-be/internal/domain/entity"
+	"github.com/Ablebil/lathi-be/internal/domain/types"
 	"github.com/Ablebil/lathi-be/pkg/response"
 	"github.com/google/uuid"
 )
 
 type StoryUsecaseItf interface {
+	GetStoryList(ctx context.Context, userID uuid.UUID) ([]dto.StoryListResponse, *response.APIError)
+	GetStoryDetail(ctx context.Context, userID, storyID uuid.UUID) (*dto.StoryDetailResponse, *response.APIError)
 	GetChapterList(ctx context.Context, userID uuid.UUID) ([]dto.ChapterListReponse, *response.APIError)
-	GetChapterContent(ctx context.Context, userID, chapterID uuid.UUID) (*dto.ChapterContentResponse, *response.APIError)
-	GetUserSession(ctx context.Context, userID, chapterID uuid.UUID) (*dto.UserSessionResponse, *response.APIError)
-	StartSession(ctx context.Context, userID, chapterID uuid.UUID) *response.APIError
+	GetChapterContent(ctx context.Context, userID, chapterID uuid.UUID, slot int, subtitle string) (*dto.ChapterContentResponse, *response.APIError)
+	GetUserSession(ctx context.Context, userID, chapterID uuid.UUID, slot int) (*dto.UserSessionResponse, *response.APIError)
+	StartSession(ctx context.Context, userID, chapterID uuid.UUID, slot int) *response.APIError
+	ResumeFromCheckpoint(ctx context.Context, userID, chapterID uuid.UUID, slot int) (*dto.UserSessionResponse, *response.APIError)
+	ListSaves(ctx context.Context, userID, chapterID uuid.UUID) ([]dto.SaveSlotResponse, *response.APIError)
+	CreateSave(ctx context.Context, userID, chapterID uuid.UUID, req *dto.CreateSaveRequest) (*dto.SaveSlotResponse, *response.APIError)
+	LoadSave(ctx context.Context, userID, chapterID uuid.UUID, slot int) (*dto.UserSessionResponse, *response.APIError)
+	DeleteSave(ctx context.Context, userID, chapterID uuid.UUID, slot int) *response.APIError
+	ListAttempts(ctx context.Context, userID, chapterID uuid.UUID, req *dto.AttemptListRequest) (*dto.AttemptListResponse, *response.APIError)
+	GetUpcomingSlides(ctx context.Context, userID, chapterID uuid.UUID, slot int, req *dto.UpcomingSlidesRequest) (*dto.UpcomingSlidesResponse, *response.APIError)
+	GetHistory(ctx context.Context, userID, chapterID uuid.UUID, slot int, req *dto.HistoryListRequest) (*dto.HistoryListResponse, *response.APIError)
 	SubmitAction(ctx context.Context, userID uuid.UUID, req *dto.StoryActionRequest) (*dto.StoryActionResponse, *response.APIError)
 }
 
 type StoryRepositoryItf interface {
 	GetAllChapters(ctx context.Context) ([]entity.Chapter, error)
-	GetChapterByID(ctx context.Context, id uuid.UUID) (*entity.Chapter, error)
-	GetSlideByID(ctx context.Context, id uuid.UUID) (*entity.Slide, error)
-	FindSession(ctx context.Context, userID, chapterID uuid.UUID) (*entity.UserStorySession, error)
+	GetStories(ctx context.Context) ([]entity.Story, error)
+	GetStory(ctx context.Context, id uuid.UUID) (*entity.Story, error)
+	GetStoryProgress(ctx context.Context, userID uuid.UUID) (map[uuid.UUID]entity.UserStoryProgress, error)
+	GetCompletedChapters(ctx context.Context, userID uuid.UUID) (map[uuid.UUID]bool, error)
+	GetCompletedOrderIndexes(ctx context.Context, userID, chapterID uuid.UUID) (map[int]bool, error)
+	RecordChapterCompletion(ctx context.Context, userID, chapterID uuid.UUID) (bool, error)
+	UpdateStoryTitle(ctx context.Context, userID, storyID uuid.UUID, title entity.Title) error
+	GetChapterMeta(ctx context.Context, id uuid.UUID) (*entity.Chapter, error)
+	GetPublishedVersion(ctx context.Context, chapterID uuid.UUID) (*entity.ChapterVersion, error)
+	GetChapterVersion(ctx context.Context, id uuid.UUID) (*entity.ChapterVersion, error)
+	FindSession(ctx context.Context, userID, chapterID uuid.UUID, slot int) (*entity.UserStorySession, error)
+	ListSessions(ctx context.Context, userID, chapterID uuid.UUID) ([]entity.UserStorySession, error)
 	CreateSession(ctx context.Context, session *entity.UserStorySession) error
-	UpdateSession(ctx context.Context, session *entity.UserStorySession) error
+	InsertSession(ctx context.Context, session *entity.UserStorySession) (bool, error)
+	DeleteSession(ctx context.Context, userID, chapterID uuid.UUID, slot int) (bool, error)
+	UpdateSession(ctx context.Context, session *entity.UserStorySession) (bool, error)
+	SaveAction(ctx context.Context, action *entity.UserStoryAction) error
+	FindAction(ctx context.Context, sessionID uuid.UUID, version int) (*entity.UserStoryAction, error)
+	AppendEvents(ctx context.Context, sessionID uuid.UUID, events []entity.StoryEvent) error
+	ListEvents(ctx context.Context, sessionID uuid.UUID, after, limit, offset int) ([]entity.StoryEvent, int64, error)
+	GetReachedSlideIDs(ctx context.Context, sessionID uuid.UUID) ([]uuid.UUID, error)
+	CopyEvents(ctx context.Context, fromSessionID uuid.UUID, after int, toSessionID uuid.UUID) error
+	GetUserVariables(ctx context.Context, userID uuid.UUID) (types.Variables, error)
+	LockUserVariables(ctx context.Context, userID uuid.UUID) (types.Variables, error)
+	SaveUserVariables(ctx context.Context, userID uuid.UUID, vars types.Variables) error
 	UnlockVocabularies(ctx context.Context, userID uuid.UUID, vocabIDs []uuid.UUID) (int64, error)
+	GetUnlockedWords(ctx context.Context, userID uuid.UUID, words []string) (map[string]bool, error)
+	GetWordsByIDs(ctx context.Context, ids []uuid.UUID) ([]entity.Dictionary, error)
+	RecordWordResult(ctx context.Context, userID, dictionaryID uuid.UUID, correct bool) error
 	CountChapters(ctx context.Context) (int64, error)
+	CountChaptersByStory(ctx context.Context) (map[uuid.UUID]int, error)
+	CountCompletedChaptersByStory(ctx context.Context, userID uuid.UUID) (map[uuid.UUID]int, error)
+	RecordEnding(ctx context.Context, userID, endingID uuid.UUID) (bool, error)
+	GetReachedEndings(ctx context.Context, userID uuid.UUID, endingIDs []uuid.UUID) (map[uuid.UUID]bool, error)
+	CountEndingsByChapter(ctx context.Context) (map[uuid.UUID]int, error)
+	CountUserEndingsByChapter(ctx context.Context, userID uuid.UUID) (map[uuid.UUID]int, error)
+	CreateAttempt(ctx context.Context, attempt *entity.StoryAttempt) error
+	AdvanceAttempt(ctx context.Context, userID, chapterID uuid.UUID, slot int, step entity.AttemptStep) error
+	ListAttempts(ctx context.Context, userID, chapterID uuid.UUID, limit, offset int) ([]entity.StoryAttempt, int64, error)
+	GetAttemptStats(ctx context.Context, userID uuid.UUID) (*entity.AttemptStats, error)
 }
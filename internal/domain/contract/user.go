@@ -23,10 +23,11 @@ type UserRepositoryItf interface {
 	GetUserWithBadges(ctx context.Context, id uuid.UUID) (*entity.User, error)
 	CreateUser(ctx context.Context, user *entity.User) error
 	UpdateUser(ctx context.Context, user *entity.User) error
-	GetUserLastCompletedChapter(ctx context.Context, userID uuid.UUID) (int, error)
-	UpdateUserLastCompletedChapter(ctx context.Context, userID uuid.UUID, orderIndex int) error
+	SyncCompletedChapters(ctx context.Context, userID uuid.UUID) (int, error)
 	IncrementUserWordCount(ctx context.Context, userID uuid.UUID, amount int) error
+	AddBonusScore(ctx context.Context, userID uuid.UUID, amount int) error
 	UpdateUserTitle(ctx context.Context, userID uuid.UUID, title entity.Title) error
+	UpdateUserRole(ctx context.Context, userID uuid.UUID, role entity.Role) error
 	AssignBadge(ctx context.Context, userID uuid.UUID, badgeCode string) error
 	DeleteUnverifiedUsers(ctx context.Context, threshold time.Time) (int64, error)
 	DeleteUser(ctx context.Context, userID uuid.UUID) error
@@ -0,0 +1,196 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/Ablebil/lathi-be/internal/domain/entity"
+	"github.com/google/uuid"
+)
+
+type AdminChapterRequest struct {
+	StoryID            *uuid.UUID           `json:"story_id"` // story the chapter is added to, defaults to the main story. Chapters can't move to another story
+	Title              string               `json:"title" validate:"required,max=100"`
+	Description        string               `json:"description"`
+	CoverImageURL      string               `json:"cover_image_url" validate:"max=255"`
+	ProtagonistName    string               `json:"protagonist_name" validate:"omitempty,max=100"` // defaults to Andi
+	ProtagonistSprites map[string]string    `json:"protagonist_sprites"`
+	StartSlideID       *uuid.UUID           `json:"start_slide_id"` // ignored on create, the first slide added becomes the start
+	EntryPoints        map[string]uuid.UUID `json:"entry_points"`
+	PublishAt          *time.Time           `json:"publish_at"` // players can start the chapter from then on, null releases it with the first publish
+	Unlock             *entity.UnlockRules  `json:"unlock"`     // defaults to completing the chapter before it
+}
+
+type AdminChapterOrderRequest struct {
+	StoryID    *uuid.UUID  `json:"story_id"`                              // story whose chapters are ordered, defaults to the main story
+	ChapterIDs []uuid.UUID `json:"chapter_ids" validate:"required,min=1"` // every chapter of the story, first one plays first
+}
+
+type AdminStoryRequest struct {
+	Slug          string `json:"slug" validate:"required,max=50"` // lower case words joined by dashes, the main story's can't change
+	Title         string `json:"title" validate:"required,max=100"`
+	Description   string `json:"description"`
+	CoverImageURL string `json:"cover_image_url" validate:"max=255"`
+	OrderIndex    *int   `json:"order_index" validate:"omitempty,min=1"` // position in the story list, defaults to after the last story
+	BadgeCode     string `json:"badge_code" validate:"max=50"`           // awarded once every chapter is completed, empty for none
+}
+
+type AdminStoryResponse struct {
+	ID            uuid.UUID `json:"id"`
+	Slug          string    `json:"slug"`
+	Title         string    `json:"title"`
+	Description   string    `json:"description"`
+	CoverImageURL string    `json:"cover_image_url"`
+	OrderIndex    int       `json:"order_index"`
+	IsMain        bool      `json:"is_main"`
+	BadgeCode     string    `json:"badge_code"`
+	ChapterCount  int       `json:"chapter_count"` // drafts included
+}
+
+type AdminChapterResponse struct {
+	ID                 uuid.UUID            `json:"id"`
+	StoryID            uuid.UUID            `json:"story_id"`
+	Title              string               `json:"title"`
+	Description        string               `json:"description"`
+	CoverImageURL      string               `json:"cover_image_url"`
+	OrderIndex         int                  `json:"order_index"`
+	StartSlideID       *uuid.UUID           `json:"start_slide_id"`
+	EntryPoints        map[string]uuid.UUID `json:"entry_points"`
+	ProtagonistName    string               `json:"protagonist_name"`
+	ProtagonistSprites map[string]string    `json:"protagonist_sprites"`
+	SlideCount         int                  `json:"slide_count"`
+	PublishedVersionID *uuid.UUID           `json:"published_version_id"` // the version players get, null until first published
+	PublishAt          *time.Time           `json:"publish_at"`
+	Unlock             entity.UnlockRules   `json:"unlock"`
+	Issues             []StoryIssueResponse `json:"issues,omitempty"` // warnings left in the story graph
+}
+
+type AdminRollbackRequest struct {
+	Version int `json:"version" validate:"required,min=1"` // number of the version to publish again
+}
+
+type AdminChapterVersionResponse struct {
+	ID          uuid.UUID            `json:"id"`
+	Number      int                  `json:"number"`
+	Title       string               `json:"title"`
+	IsPublished bool                 `json:"is_published"` // the version new runs start on
+	PublishedAt time.Time            `json:"published_at"`
+	Issues      []StoryIssueResponse `json:"issues,omitempty"` // warnings left in the story graph, on publish
+}
+
+type AdminChapterDetailResponse struct {
+	AdminChapterResponse
+	Endings []AdminEndingResponse `json:"endings"`
+	Slides  []AdminSlideResponse  `json:"slides"` // in story order
+}
+
+type AdminEndingResponse struct {
+	ID         uuid.UUID `json:"id"`
+	Key        string    `json:"key"`
+	Title      string    `json:"title"`
+	Kind       string    `json:"kind"`
+	BadgeCode  string    `json:"badge_code"`
+	ScoreBonus int       `json:"score_bonus"`
+}
+
+type StoryIssueResponse struct {
+	Severity string `json:"severity"` // error or warning
+	Slide    string `json:"slide,omitempty"`
+	Code     string `json:"code"`
+	Message  string `json:"message"`
+}
+
+type AdminSlideRequest struct {
+	Key                string            `json:"key" validate:"max=50"`
+	SpeakerName        string            `json:"speaker_name" validate:"max=100"`
+	BackgroundImageURL string            `json:"background_image_url" validate:"max=255"`
+	Characters         []AdminCharacter  `json:"characters" validate:"dive"`
+	Content            string            `json:"content"`
+	Subtitles          entity.Subtitles  `json:"subtitles"`
+	NextSlideID        *uuid.UUID        `json:"next_slide_id"`
+	Routes             []AdminRoute      `json:"routes" validate:"dive"`
+	EndingID           *uuid.UUID        `json:"ending_id"`
+	IsCheckpoint       bool              `json:"is_checkpoint"`
+	Quiz               *AdminQuiz        `json:"quiz"`                                      // turns the slide into a quiz slide
+	Translation        *AdminTranslation `json:"translation" validate:"excluded_with=Quiz"` // turns the slide into a translation slide
+	VocabIDs           []uuid.UUID       `json:"vocab_ids"`                                 // dictionary entries linked to the slide, replaces the current links
+}
+
+type AdminSlideResponse struct {
+	ID                 uuid.UUID            `json:"id"`
+	ChapterID          uuid.UUID            `json:"chapter_id"`
+	Key                string               `json:"key"`
+	Type               string               `json:"type"`
+	SpeakerName        string               `json:"speaker_name"`
+	BackgroundImageURL string               `json:"background_image_url"`
+	Characters         []AdminCharacter     `json:"characters"`
+	Content            string               `json:"content"` // raw, with {word} and {player} markers
+	Subtitles          entity.Subtitles     `json:"subtitles"`
+	NextSlideID        *uuid.UUID           `json:"next_slide_id"`
+	Routes             []AdminRoute         `json:"routes"`
+	Choices            []AdminChoice        `json:"choices"`
+	EndingID           *uuid.UUID           `json:"ending_id"`
+	IsCheckpoint       bool                 `json:"is_checkpoint"`
+	Quiz               *AdminQuiz           `json:"quiz,omitempty"`
+	Translation        *AdminTranslation    `json:"translation,omitempty"`
+	Vocabularies       []VocabItemResponse  `json:"vocabularies"`
+	Issues             []StoryIssueResponse `json:"issues,omitempty"` // warnings left in the chapter's story graph
+}
+
+type AdminCharacter struct {
+	Name     string `json:"name" validate:"required,max=100"`
+	ImageURL string `json:"image_url" validate:"max=255"`
+	IsActive bool   `json:"is_active"`
+}
+
+type AdminRoute struct {
+	If          entity.Condition `json:"if"`
+	NextSlideID uuid.UUID        `json:"next_slide_id" validate:"required"`
+}
+
+type AdminChoice struct {
+	Text        string            `json:"text" validate:"required,max=500"`
+	Subtitles   entity.Subtitles  `json:"subtitles,omitempty"`
+	NextSlideID uuid.UUID         `json:"next_slide_id" validate:"required"`
+	MoodImpact  int               `json:"mood_impact"`
+	Effects     []AdminEffect     `json:"effects,omitempty" validate:"dive"`
+	ShowIf      *entity.Condition `json:"show_if,omitempty"`
+	EnableIf    *entity.Condition `json:"enable_if,omitempty"`
+}
+
+type AdminEffect struct {
+	Var   string `json:"var" validate:"required"`
+	Op    string `json:"op" validate:"required,oneof=set add"`
+	Value any    `json:"value"`
+	Scope string `json:"scope,omitempty" validate:"omitempty,oneof=session user"`
+}
+
+type AdminQuiz struct {
+	WordID        uuid.UUID   `json:"word_id" validate:"required"`
+	Ask           string      `json:"ask" validate:"required,oneof=krama ngoko indo"`
+	Answer        string      `json:"answer" validate:"required,oneof=krama ngoko indo"`
+	DistractorIDs []uuid.UUID `json:"distractor_ids" validate:"required,min=1"`
+	HeartPenalty  int         `json:"heart_penalty" validate:"min=0"`
+}
+
+type AdminTranslation struct {
+	Prompt       string   `json:"prompt" validate:"required"`
+	Accepted     []string `json:"accepted" validate:"required,min=1,dive,required"`
+	HeartPenalty int      `json:"heart_penalty" validate:"min=0"`
+}
+
+type AdminWordListRequest struct {
+	Search string `query:"search"`
+	Page   int    `query:"page"`
+	Limit  int    `query:"limit"`
+}
+
+type AdminWordRequest struct {
+	WordKrama string `json:"word_krama" validate:"required,max=100"`
+	WordNgoko string `json:"word_ngoko" validate:"required,max=100"`
+	WordIndo  string `json:"word_indo" validate:"required,max=100"`
+}
+
+type AdminWordListResponse struct {
+	Items      []VocabItemResponse `json:"items"`
+	Pagination PaginationMeta      `json:"pagination"`
+}
@@ -9,14 +9,45 @@ import (
 type StoryActionRequest struct {
 	ChapterID   uuid.UUID `json:"chapter_id" validate:"required,uuid"`
 	SlideID     uuid.UUID `json:"slide_id" validate:"required,uuid"`
-	ChoiceIndex *int      `json:"choice_index,omitempty"`
+	ChoiceIndex *int      `json:"choice_index,omitempty"`                          // picked choice, or the picked option on quiz slides
+	Answer      *string   `json:"answer,omitempty" validate:"omitempty,max=500"`   // typed answer on translation slides
+	Slot        *int      `json:"slot,omitempty" validate:"omitempty,min=1,max=3"` // save slot, defaults to the main run
+	Version     *int      `json:"version" validate:"required,min=0"`               // session version the action is based on
+	Prefetch    *int      `json:"prefetch,omitempty" validate:"omitempty,min=0"`   // branch depth of next slides to return, none when unset
 }
 
 type HistoryEntry struct {
-	Speaker   string    `json:"speaker"`
-	Text      string    `json:"text"`
-	IsUser    bool      `json:"is_user"`
-	Timestamp time.Time `json:"timestamp"`
+	Sequence    int        `json:"sequence"` // position in the session's history, increases by one per entry
+	Speaker     string     `json:"speaker"`
+	Text        string     `json:"text"`
+	IsUser      bool       `json:"is_user"`
+	ChoiceIndex *int       `json:"choice_index"`
+	SlideID     *uuid.UUID `json:"slide_id"`
+	Timestamp   time.Time  `json:"timestamp"`
+}
+
+type UpcomingSlidesRequest struct {
+	Depth    *int   `query:"depth"` // how many steps of branches to follow, defaults to 2
+	Subtitle string `query:"subtitle"`
+}
+
+type UpcomingSlidesResponse struct {
+	ChapterID        uuid.UUID           `json:"chapter_id"`
+	CurrentSlideID   uuid.UUID           `json:"current_slide_id"`
+	Depth            int                 `json:"depth"` // depth the slides were fetched to, after capping
+	Protagonist      ProtagonistResponse `json:"protagonist"`
+	SubtitleLanguage string              `json:"subtitle_language"`
+	Slides           []SlideItemResponse `json:"slides"` // current slide first, then in the order they are reached
+}
+
+type HistoryListRequest struct {
+	Page  int `query:"page"`
+	Limit int `query:"limit"`
+}
+
+type HistoryListResponse struct {
+	Items      []HistoryEntry `json:"items"` // newest first
+	Pagination PaginationMeta `json:"pagination"`
 }
 
 type CharacterOnScreen struct {
@@ -25,30 +56,85 @@ type CharacterOnScreen struct {
 	IsActive bool   `json:"is_active"`
 }
 
+type StoryListResponse struct {
+	ID                uuid.UUID `json:"id"`
+	Slug              string    `json:"slug"`
+	Title             string    `json:"title"`
+	Description       string    `json:"description"`
+	CoverImageURL     string    `json:"cover_image_url"`
+	OrderIndex        int       `json:"order_index"`
+	IsMain            bool      `json:"is_main"`   // the story every player starts with
+	IsLocked          bool      `json:"is_locked"` // its first chapter can't be started yet
+	IsCompleted       bool      `json:"is_completed"`
+	TotalChapters     int       `json:"total_chapters"`
+	CompletedChapters int       `json:"completed_chapters"`
+	ProgressPercent   float64   `json:"progress_percent"`
+	PlayerTitle       string    `json:"player_title"` // title the user earned in this story
+}
+
+type StoryDetailResponse struct {
+	StoryListResponse
+	Chapters []ChapterListReponse `json:"chapters"`
+}
+
 type ChapterListReponse struct {
-	ID            uuid.UUID `json:"id"`
-	Title         string    `json:"title"`
-	Description   string    `json:"description"`
-	CoverImageURL string    `json:"cover_image_url"`
-	OrderIndex    int       `json:"order_index"`
-	IsLocked      bool      `json:"is_locked"`
-	IsCompleted   bool      `json:"is_completed"`
+	ID            uuid.UUID                   `json:"id"`
+	StoryID       uuid.UUID                   `json:"story_id"`
+	Title         string                      `json:"title"`
+	Description   string                      `json:"description"`
+	CoverImageURL string                      `json:"cover_image_url"`
+	OrderIndex    int                         `json:"order_index"`
+	IsLocked      bool                        `json:"is_locked"`
+	IsCompleted   bool                        `json:"is_completed"`
+	EndingsFound  int                         `json:"endings_found"`
+	TotalEndings  int                         `json:"total_endings"`
+	AvailableAt   *time.Time                  `json:"available_at"`           // set while the chapter isn't released yet
+	Requirements  *ChapterRequirementResponse `json:"requirements,omitempty"` // unlock rules the player doesn't meet yet
+}
+
+type ChapterRequirementResponse struct {
+	ChaptersCompleted []uuid.UUID `json:"chapters_completed"` // chapters still to complete
+	MinVocab          int         `json:"min_vocab"`          // words to collect, 0 when the player has enough
+	WordsCollected    int         `json:"words_collected"`
+	EndingsReached    []uuid.UUID `json:"endings_reached"` // endings still to reach
 }
 
 type ChapterContentResponse struct {
-	ChapterID uuid.UUID           `json:"chapter_id"`
-	Slides    []SlideItemResponse `json:"slides"`
+	ChapterID        uuid.UUID           `json:"chapter_id"`
+	StartSlideID     *uuid.UUID          `json:"start_slide_id"`
+	Protagonist      ProtagonistResponse `json:"protagonist"`
+	SubtitleLanguage string              `json:"subtitle_language"` // language of the subtitles, empty when none are shown
+	Slides           []SlideItemResponse `json:"slides"`
+}
+
+type ProtagonistResponse struct {
+	Name    string            `json:"name"`    // the player's display name when they set one
+	Sprites map[string]string `json:"sprites"` // sprite key to image url
 }
 
 type SlideItemResponse struct {
-	ID                 uuid.UUID            `json:"id"`
-	BackgroundImageURL string               `json:"background_image_url"`
-	Characters         []CharacterOnScreen  `json:"characters"`
-	SpeakerName        string               `json:"speaker_name"`
-	Content            string               `json:"content"`
-	NextSlideID        *uuid.UUID           `json:"next_slide_id"`
-	Vocabularies       []VocabItemResponse  `json:"vocabularies"`
-	Choices            []ChoiceItemResponse `json:"choices"`
+	ID                 uuid.UUID                `json:"id"`
+	Type               string                   `json:"type"` // story or quiz
+	BackgroundImageURL string                   `json:"background_image_url"`
+	Characters         []CharacterOnScreen      `json:"characters"`
+	SpeakerName        string                   `json:"speaker_name"`
+	Content            string                   `json:"content"` // plain text, see segments for the markup
+	Segments           []ContentSegmentResponse `json:"segments"`
+	Subtitle           string                   `json:"subtitle,omitempty"` // translated content, missing when the slide has none
+	NextSlideID        *uuid.UUID               `json:"next_slide_id"`
+	IsCheckpoint       bool                     `json:"is_checkpoint"`
+	IsRouted           bool                     `json:"is_routed"` // next slide depends on conditions and may change, the action response is authoritative
+	Vocabularies       []VocabItemResponse      `json:"vocabularies"`
+	Choices            []ChoiceItemResponse     `json:"choices"`
+	Quiz               *QuizResponse            `json:"quiz,omitempty"`
+	Translation        *TranslationResponse     `json:"translation,omitempty"`
+}
+
+type ContentSegmentResponse struct {
+	Type     string     `json:"type"`               // text, vocab or player
+	Text     string     `json:"text"`               // as shown, the player's name for player segments
+	VocabID  *uuid.UUID `json:"vocab_id,omitempty"` // dictionary entry of vocab segments, listed in the slide's vocabularies
+	IsLocked bool       `json:"is_locked"`          // vocab the player hasn't collected yet
 }
 
 type VocabItemResponse struct {
@@ -59,24 +145,138 @@ type VocabItemResponse struct {
 }
 
 type ChoiceItemResponse struct {
+	Index      int    `json:"index"`
+	Text       string `json:"text"`
+	Subtitle   string `json:"subtitle,omitempty"`
+	IsDisabled bool   `json:"is_disabled"` // shown but its condition doesn't hold yet
+}
+
+type QuizResponse struct {
+	WordID  uuid.UUID            `json:"word_id"`
+	Prompt  string               `json:"prompt"` // the word in the asked form
+	Ask     string               `json:"ask"`
+	Answer  string               `json:"answer"`
+	Options []QuizOptionResponse `json:"options"`
+}
+
+type QuizOptionResponse struct {
 	Index int    `json:"index"`
 	Text  string `json:"text"`
 }
 
+type TranslationResponse struct {
+	Prompt string `json:"prompt"`
+}
+
 type UserSessionResponse struct {
 	SessionID      uuid.UUID      `json:"session_id"`
+	Slot           int            `json:"slot"`
+	Name           string         `json:"name"`
 	CurrentSlideID uuid.UUID      `json:"current_slide_id"`
 	CurrentHearts  int            `json:"current_hearts"`
 	IsGameOver     bool           `json:"is_game_over"`
 	IsCompleted    bool           `json:"is_completed"`
-	HistoryLog     []HistoryEntry `json:"history_log"`
+	Version        int            `json:"version"`
+	EndingID       *uuid.UUID     `json:"ending_id"`           // set once the run reached an ending
+	CheckpointID   *uuid.UUID     `json:"checkpoint_slide_id"` // where a game over can be resumed from
+	ChapterVersion *uuid.UUID     `json:"chapter_version_id"`  // chapter version the run plays until it is restarted
+	Variables      map[string]any `json:"variables"`           // flags and counters of this chapter run
+	UserVariables  map[string]any `json:"user_variables"`      // carried across chapters
 }
 
 type StoryActionResponse struct {
-	IsGameOver      bool           `json:"is_game_over"`
-	IsCompleted     bool           `json:"is_completed"`
-	Message         string         `json:"message"` // msg if gameover/completed
-	RemainingHearts int            `json:"remaining_hearts"`
-	NextSlideID     *uuid.UUID     `json:"next_slide_id"`
-	HistoryLog      []HistoryEntry `json:"history_log"`
+	IsGameOver      bool                 `json:"is_game_over"`
+	IsCompleted     bool                 `json:"is_completed"`
+	Message         string               `json:"message"` // msg if gameover/completed
+	RemainingHearts int                  `json:"remaining_hearts"`
+	NextSlideID     *uuid.UUID           `json:"next_slide_id"`
+	Ending          *EndingResponse      `json:"ending,omitempty"`       // ending reached by this action
+	CheckpointSaved bool                 `json:"checkpoint_saved"`       // the next slide is a checkpoint and the state was saved there
+	Quiz            *QuizResultResponse  `json:"quiz,omitempty"`         // grading of a quiz slide
+	Translation     *TranslationResult   `json:"translation,omitempty"`  // grading of a translation slide
+	NextChoices     []ChoiceItemResponse `json:"next_choices,omitempty"` // choices of the next slide under the new state
+	Version         int                  `json:"version"`                // session version to send with the next action
+	Variables       map[string]any       `json:"variables"`
+	UserVariables   map[string]any       `json:"user_variables,omitempty"` // only set when the action changed them
+	NewEntries      []HistoryEntry       `json:"new_entries"`              // history entries added by this action
+	NextSlides      []SlideItemResponse  `json:"next_slides,omitempty"`    // next slide and the ones reachable from it, when prefetch is set
+}
+
+type EndingResponse struct {
+	ID    uuid.UUID `json:"id"`
+	Title string    `json:"title"`
+	Kind  string    `json:"kind"`
+	IsNew bool      `json:"is_new"` // first time the user reached it
+}
+
+type QuizResultResponse struct {
+	WordID        uuid.UUID `json:"word_id"`
+	IsCorrect     bool      `json:"is_correct"`
+	CorrectIndex  int       `json:"correct_index"`
+	CorrectAnswer string    `json:"correct_answer"`
+	HeartsLost    int       `json:"hearts_lost"`
+}
+
+type TranslationResult struct {
+	IsCorrect  bool            `json:"is_correct"`
+	Expected   string          `json:"expected"` // closest accepted answer
+	Tokens     []TokenFeedback `json:"tokens"`
+	HeartsLost int             `json:"hearts_lost"`
+}
+
+type TokenFeedback struct {
+	Text     string `json:"text"`
+	Expected string `json:"expected,omitempty"`
+	Status   string `json:"status"` // correct, typo, wrong, missing or extra
+}
+
+type CreateSaveRequest struct {
+	Slot     *int   `json:"slot,omitempty" validate:"omitempty,min=1,max=3"` // defaults to the first free slot
+	Name     string `json:"name" validate:"max=50"`
+	FromSlot *int   `json:"from_slot,omitempty" validate:"omitempty,min=1,max=3"` // copy the progress of another slot instead of starting over
+}
+
+type SaveSlotResponse struct {
+	Slot           int       `json:"slot"`
+	Name           string    `json:"name"`
+	SessionID      uuid.UUID `json:"session_id"`
+	CurrentSlideID uuid.UUID `json:"current_slide_id"`
+	CurrentHearts  int       `json:"current_hearts"`
+	IsGameOver     bool      `json:"is_game_over"`
+	IsCompleted    bool      `json:"is_completed"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+type AttemptListRequest struct {
+	Page  int `query:"page"`
+	Limit int `query:"limit"`
+}
+
+type AttemptChoiceResponse struct {
+	SlideID     uuid.UUID `json:"slide_id"`
+	ChoiceIndex int       `json:"choice_index"`
+	Text        string    `json:"text"`
+}
+
+type AttemptEndingResponse struct {
+	ID    uuid.UUID `json:"id"`
+	Title string    `json:"title"`
+	Kind  string    `json:"kind"`
+}
+
+type AttemptResponse struct {
+	ID         uuid.UUID               `json:"id"`
+	Slot       int                     `json:"slot"`
+	Origin     string                  `json:"origin"`
+	Outcome    string                  `json:"outcome"`
+	HeartsLost int                     `json:"hearts_lost"`
+	Choices    []AttemptChoiceResponse `json:"choices"`
+	Ending     *AttemptEndingResponse  `json:"ending"`
+	StartedAt  time.Time               `json:"started_at"`
+	EndedAt    *time.Time              `json:"ended_at"`
+}
+
+type AttemptListResponse struct {
+	Items      []AttemptResponse `json:"items"`
+	Pagination PaginationMeta    `json:"pagination"`
 }
@@ -7,7 +7,10 @@ import (
 )
 
 type EditUserProfileRequest struct {
-	Username string `json:"username" validate:"required,min=3,max=50,alphanum"`
+	Username         string  `json:"username" validate:"omitempty,min=3,max=50,alphanum"`
+	DisplayName      *string `json:"display_name" validate:"omitempty,max=50"`           // empty clears it, the story protagonist's name is used again
+	Language         *string `json:"language" validate:"omitempty,oneof=id jv en"`       // empty clears it, Accept-Language is used again
+	SubtitleLanguage *string `json:"subtitle_language" validate:"omitempty,oneof=id en"` // empty turns subtitles off
 }
 
 type UserBadgeResponse struct {
@@ -17,12 +20,26 @@ type UserBadgeResponse struct {
 	EarnedAt    time.Time `json:"earned_at"`
 }
 
+// chapter counts and progress are over every story, see stories for each one
 type UserStatsResponse struct {
-	TotalChapters     int64   `json:"total_chapters"`
-	CompletedChapters int     `json:"completed_chapters"`
-	ProgressPercent   float64 `json:"progress_percent"`
-	TotalVocabs       int64   `json:"total_vocabs"`
-	CollectedVocabs   int     `json:"collected_vocabs"`
+	TotalChapters     int64                    `json:"total_chapters"`
+	CompletedChapters int                      `json:"completed_chapters"`
+	ProgressPercent   float64                  `json:"progress_percent"`
+	TotalVocabs       int64                    `json:"total_vocabs"`
+	CollectedVocabs   int                      `json:"collected_vocabs"`
+	TotalAttempts     int64                    `json:"total_attempts"`
+	CompletedAttempts int64                    `json:"completed_attempts"`
+	GameOvers         int64                    `json:"game_overs"`
+	Stories           []UserStoryStatsResponse `json:"stories"`
+}
+
+type UserStoryStatsResponse struct {
+	StoryID           uuid.UUID `json:"story_id"`
+	Title             string    `json:"title"`
+	TotalChapters     int       `json:"total_chapters"`
+	CompletedChapters int       `json:"completed_chapters"`
+	ProgressPercent   float64   `json:"progress_percent"`
+	PlayerTitle       string    `json:"player_title"` // title the user earned in this story
 }
 
 type UserLeaderboardInfoResponse struct {
@@ -31,12 +48,16 @@ type UserLeaderboardInfoResponse struct {
 }
 
 type UserProfileResponse struct {
-	ID              uuid.UUID                    `json:"id"`
-	Username        string                       `json:"username"`
-	Email           string                       `json:"email"`
-	AvatarURL       string                       `json:"avatar_url"`
-	CurrentTitle    string                       `json:"current_title"`
-	Stats           UserStatsResponse            `json:"stats"`
-	Badges          []UserBadgeResponse          `json:"badges"`
-	LeaderboardInfo *UserLeaderboardInfoResponse `json:"leaderboard_info"`
+	ID               uuid.UUID                    `json:"id"`
+	Username         string                       `json:"username"`
+	DisplayName      string                       `json:"display_name"`
+	Language         string                       `json:"language"`
+	SubtitleLanguage string                       `json:"subtitle_language"`
+	Email            string                       `json:"email"`
+	Role             string                       `json:"role"`
+	AvatarURL        string                       `json:"avatar_url"`
+	CurrentTitle     string                       `json:"current_title"`
+	Stats            UserStatsResponse            `json:"stats"`
+	Badges           []UserBadgeResponse          `json:"badges"`
+	LeaderboardInfo  *UserLeaderboardInfoResponse `json:"leaderboard_info"`
 }
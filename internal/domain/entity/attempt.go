@@ -0,0 +1,79 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/Ablebil/lathi-be/internal/domain/types"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type AttemptOutcome string
+
+const (
+	AttemptInProgress AttemptOutcome = "in_progress"
+	AttemptCompleted  AttemptOutcome = "completed"
+	AttemptGameOver   AttemptOutcome = "game_over"
+	AttemptAbandoned  AttemptOutcome = "abandoned" // restarted or deleted before it ended
+)
+
+type AttemptOrigin string
+
+const (
+	OriginStart      AttemptOrigin = "start"
+	OriginCheckpoint AttemptOrigin = "checkpoint" // resumed after a game over
+	OriginCopy       AttemptOrigin = "copy"       // branched off another save slot
+)
+
+// StoryAttempt is one run through a chapter. Sessions are overwritten when a
+// run restarts, attempts are kept and never change again once they ended.
+// A save slot has at most one attempt in progress.
+type StoryAttempt struct {
+	ID         uuid.UUID      `json:"id" gorm:"type:char(36);primaryKey;not null"`
+	UserID     uuid.UUID      `json:"user_id" gorm:"type:char(36);not null;index:idx_attempt_user_chapter;uniqueIndex:idx_attempt_open,where:ended_at IS NULL"`
+	ChapterID  uuid.UUID      `json:"chapter_id" gorm:"type:char(36);not null;index:idx_attempt_user_chapter;uniqueIndex:idx_attempt_open"`
+	Slot       int            `json:"slot" gorm:"type:int;default:1;not null;uniqueIndex:idx_attempt_open"`
+	Origin     AttemptOrigin  `json:"origin" gorm:"type:varchar(20);default:'start';not null"`
+	Outcome    AttemptOutcome `json:"outcome" gorm:"type:varchar(20);default:'in_progress';not null"`
+	HeartsLost int            `json:"hearts_lost" gorm:"type:int;default:0;not null"`
+	Choices    types.JSONB    `json:"choices" gorm:"type:jsonb;default:'[]'::jsonb;not null"` // []AttemptChoice in the order they were taken
+	EndingID   *uuid.UUID     `json:"ending_id" gorm:"type:char(36)"`
+	StartedAt  time.Time      `json:"started_at" gorm:"type:timestamp;autoCreateTime;not null"`
+	EndedAt    *time.Time     `json:"ended_at" gorm:"type:timestamp"`
+
+	User    User           `gorm:"foreignKey:UserID;references:ID;constraint:OnDelete:CASCADE"`
+	Chapter Chapter        `gorm:"foreignKey:ChapterID;references:ID;constraint:OnDelete:CASCADE"`
+	Ending  *ChapterEnding `json:"-" gorm:"foreignKey:EndingID;references:ID;constraint:OnDelete:SET NULL"`
+}
+
+func (a *StoryAttempt) BeforeCreate(tx *gorm.DB) error {
+	if a.ID == uuid.Nil {
+		id, err := uuid.NewV7()
+		if err != nil {
+			return err
+		}
+		a.ID = id
+	}
+	return nil
+}
+
+type AttemptChoice struct {
+	SlideID     uuid.UUID `json:"slide_id"`
+	ChoiceIndex int       `json:"choice_index"`
+	Text        string    `json:"text"`
+}
+
+// AttemptStep is what one action adds to the attempt in progress. An empty
+// outcome keeps the attempt running.
+type AttemptStep struct {
+	HeartsLost int
+	Choice     *AttemptChoice
+	Outcome    AttemptOutcome
+	EndingID   *uuid.UUID
+}
+
+type AttemptStats struct {
+	Total     int64
+	Completed int64
+	GameOver  int64
+}
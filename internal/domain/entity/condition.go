@@ -0,0 +1,156 @@
+package entity
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Ablebil/lathi-be/internal/domain/types"
+	"github.com/google/uuid"
+)
+
+// Condition is evaluated against the player state. Exactly one field is set,
+// All/Any/Not combine other conditions. A nil condition always holds.
+type Condition struct {
+	MinHearts        *int          `json:"min_hearts,omitempty" yaml:"min_hearts,omitempty"`
+	MaxHearts        *int          `json:"max_hearts,omitempty" yaml:"max_hearts,omitempty"`
+	Flag             string        `json:"flag,omitempty" yaml:"flag,omitempty"` // variable is true, non zero or non empty
+	VarAtLeast       *VarThreshold `json:"var_at_least,omitempty" yaml:"var_at_least,omitempty"`
+	VocabUnlocked    string        `json:"vocab_unlocked,omitempty" yaml:"vocab_unlocked,omitempty"`       // krama word
+	ChapterCompleted *int          `json:"chapter_completed,omitempty" yaml:"chapter_completed,omitempty"` // chapter order_index in the same story
+	All              []Condition   `json:"all,omitempty" yaml:"all,omitempty"`
+	Any              []Condition   `json:"any,omitempty" yaml:"any,omitempty"`
+	Not              *Condition    `json:"not,omitempty" yaml:"not,omitempty"`
+}
+
+type VarThreshold struct {
+	Var   string `json:"var" yaml:"var"`
+	Value int    `json:"value" yaml:"value"`
+}
+
+// player state conditions are checked against
+type ConditionState struct {
+	Hearts            int
+	Variables         types.Variables // session variables, looked up first
+	UserVariables     types.Variables
+	UnlockedWords     map[string]bool // lower cased krama words
+	CompletedChapters map[int]bool    // order indexes completed in the story of the chapter being played
+}
+
+func (c *Condition) Validate() error {
+	if c == nil {
+		return nil
+	}
+
+	set := 0
+	for _, ok := range []bool{
+		c.MinHearts != nil, c.MaxHearts != nil, c.Flag != "", c.VarAtLeast != nil,
+		c.VocabUnlocked != "", c.ChapterCompleted != nil, len(c.All) > 0, len(c.Any) > 0, c.Not != nil,
+	} {
+		if ok {
+			set++
+		}
+	}
+	if set != 1 {
+		return fmt.Errorf("condition must set exactly one rule, got %d", set)
+	}
+
+	if c.VarAtLeast != nil && strings.TrimSpace(c.VarAtLeast.Var) == "" {
+		return fmt.Errorf("var_at_least without variable name")
+	}
+
+	for i := range c.All {
+		if err := c.All[i].Validate(); err != nil {
+			return err
+		}
+	}
+	for i := range c.Any {
+		if err := c.Any[i].Validate(); err != nil {
+			return err
+		}
+	}
+	return c.Not.Validate()
+}
+
+func (c *Condition) Eval(st *ConditionState) bool {
+	if c == nil {
+		return true
+	}
+
+	switch {
+	case c.MinHearts != nil:
+		return st.Hearts >= *c.MinHearts
+	case c.MaxHearts != nil:
+		return st.Hearts <= *c.MaxHearts
+	case c.Flag != "":
+		return truthy(st.lookup(c.Flag))
+	case c.VarAtLeast != nil:
+		n, _ := st.lookup(c.VarAtLeast.Var).(int)
+		return n >= c.VarAtLeast.Value
+	case c.VocabUnlocked != "":
+		return st.UnlockedWords[strings.ToLower(c.VocabUnlocked)]
+	case c.ChapterCompleted != nil:
+		return st.CompletedChapters[*c.ChapterCompleted]
+	case len(c.All) > 0:
+		for i := range c.All {
+			if !c.All[i].Eval(st) {
+				return false
+			}
+		}
+		return true
+	case len(c.Any) > 0:
+		for i := range c.Any {
+			if c.Any[i].Eval(st) {
+				return true
+			}
+		}
+		return false
+	case c.Not != nil:
+		return !c.Not.Eval(st)
+	}
+
+	return true
+}
+
+// Words returns the vocabulary words the condition depends on.
+func (c *Condition) Words() []string {
+	if c == nil {
+		return nil
+	}
+
+	var words []string
+	if c.VocabUnlocked != "" {
+		words = append(words, c.VocabUnlocked)
+	}
+	for i := range c.All {
+		words = append(words, c.All[i].Words()...)
+	}
+	for i := range c.Any {
+		words = append(words, c.Any[i].Words()...)
+	}
+	return append(words, c.Not.Words()...)
+}
+
+func (st *ConditionState) lookup(name string) any {
+	if v, ok := st.Variables[name]; ok {
+		return v
+	}
+	return st.UserVariables[name]
+}
+
+func truthy(v any) bool {
+	switch t := v.(type) {
+	case bool:
+		return t
+	case int:
+		return t != 0
+	case string:
+		return t != ""
+	}
+	return false
+}
+
+// conditional jump used by slides instead of a single next slide
+type Route struct {
+	If          Condition `json:"if"`
+	NextSlideID uuid.UUID `json:"next_slide_id"`
+}
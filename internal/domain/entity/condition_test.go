@@ -0,0 +1,99 @@
+package entity
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Ablebil/lathi-be/internal/domain/types"
+)
+
+func intPtr(n int) *int { return &n }
+
+func TestConditionEval(t *testing.T) {
+	st := &ConditionState{
+		Hearts:            2,
+		Variables:         types.Variables{"met_sekar": true, "gifts": 2, "name": "", "shared": 1},
+		UserVariables:     types.Variables{"shared": 5, "respect": 3},
+		UnlockedWords:     map[string]bool{"kula": true},
+		CompletedChapters: map[int]bool{1: true, 3: true},
+	}
+
+	tests := []struct {
+		name string
+		cond *Condition
+		want bool
+	}{
+		{"nil holds", nil, true},
+		{"min hearts met", &Condition{MinHearts: intPtr(2)}, true},
+		{"min hearts unmet", &Condition{MinHearts: intPtr(3)}, false},
+		{"max hearts met", &Condition{MaxHearts: intPtr(2)}, true},
+		{"max hearts unmet", &Condition{MaxHearts: intPtr(1)}, false},
+		{"flag true", &Condition{Flag: "met_sekar"}, true},
+		{"flag non zero", &Condition{Flag: "gifts"}, true},
+		{"flag empty string", &Condition{Flag: "name"}, false},
+		{"flag unset", &Condition{Flag: "missing"}, false},
+		{"flag from user variables", &Condition{Flag: "respect"}, true},
+		{"var at least met", &Condition{VarAtLeast: &VarThreshold{Var: "gifts", Value: 2}}, true},
+		{"var at least unmet", &Condition{VarAtLeast: &VarThreshold{Var: "gifts", Value: 3}}, false},
+		{"var at least unset counts as zero", &Condition{VarAtLeast: &VarThreshold{Var: "missing", Value: 0}}, true},
+		{"var at least on a string", &Condition{VarAtLeast: &VarThreshold{Var: "name", Value: 1}}, false},
+		{"session variable shadows user variable", &Condition{VarAtLeast: &VarThreshold{Var: "shared", Value: 2}}, false},
+		{"vocab unlocked any case", &Condition{VocabUnlocked: "Kula"}, true},
+		{"vocab locked", &Condition{VocabUnlocked: "badhe"}, false},
+		{"chapter completed", &Condition{ChapterCompleted: intPtr(3)}, true},
+		{"earlier chapter not completed", &Condition{ChapterCompleted: intPtr(2)}, false},
+		{"all holds", &Condition{All: []Condition{{Flag: "met_sekar"}, {MinHearts: intPtr(1)}}}, true},
+		{"all fails on one", &Condition{All: []Condition{{Flag: "met_sekar"}, {MinHearts: intPtr(3)}}}, false},
+		{"any holds on one", &Condition{Any: []Condition{{Flag: "missing"}, {MinHearts: intPtr(1)}}}, true},
+		{"any fails", &Condition{Any: []Condition{{Flag: "missing"}, {MinHearts: intPtr(3)}}}, false},
+		{"not", &Condition{Not: &Condition{Flag: "missing"}}, true},
+		{"nested", &Condition{Not: &Condition{Any: []Condition{{VocabUnlocked: "badhe"}, {All: []Condition{{Flag: "gifts"}, {MaxHearts: intPtr(1)}}}}}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cond.Eval(st); got != tt.want {
+				t.Errorf("Eval() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConditionValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cond    *Condition
+		wantErr bool
+	}{
+		{"nil", nil, false},
+		{"single rule", &Condition{Flag: "met_sekar"}, false},
+		{"no rule", &Condition{}, true},
+		{"two rules", &Condition{Flag: "met_sekar", MinHearts: intPtr(1)}, true},
+		{"var at least without name", &Condition{VarAtLeast: &VarThreshold{Var: " ", Value: 1}}, true},
+		{"invalid rule inside all", &Condition{All: []Condition{{Flag: "a"}, {}}}, true},
+		{"invalid rule inside any", &Condition{Any: []Condition{{}}}, true},
+		{"invalid rule inside not", &Condition{Not: &Condition{}}, true},
+		{"nested", &Condition{Not: &Condition{Any: []Condition{{Flag: "a"}, {VocabUnlocked: "kula"}}}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.cond.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConditionWords(t *testing.T) {
+	cond := &Condition{All: []Condition{
+		{VocabUnlocked: "kula"},
+		{Any: []Condition{{Flag: "a"}, {VocabUnlocked: "badhe"}}},
+		{Not: &Condition{VocabUnlocked: "tindak"}},
+	}}
+
+	want := []string{"kula", "badhe", "tindak"}
+	if got := cond.Words(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Words() = %v, want %v", got, want)
+	}
+}
@@ -25,6 +25,31 @@ func (d *Dictionary) BeforeCreate(tx *gorm.DB) error {
 	return nil
 }
 
+type WordForm string
+
+const (
+	FormKrama WordForm = "krama"
+	FormNgoko WordForm = "ngoko"
+	FormIndo  WordForm = "indo"
+)
+
+func (f WordForm) IsValid() bool {
+	return f == FormKrama || f == FormNgoko || f == FormIndo
+}
+
+// Form returns the word in the given form.
+func (d *Dictionary) Form(f WordForm) string {
+	switch f {
+	case FormKrama:
+		return d.WordKrama
+	case FormNgoko:
+		return d.WordNgoko
+	case FormIndo:
+		return d.WordIndo
+	}
+	return ""
+}
+
 type UserVocabulary struct {
 	UserID       uuid.UUID `gorm:"type:uuid;primaryKey;not null"`
 	DictionaryID uuid.UUID `gorm:"type:uuid;primaryKey;not null"`
@@ -33,3 +58,15 @@ type UserVocabulary struct {
 	User       User       `gorm:"foreignKey:UserID;references:ID;constraint:OnDelete:CASCADE"`
 	Dictionary Dictionary `gorm:"foreignKey:DictionaryID;references:ID;constraint:OnDelete:CASCADE"`
 }
+
+// quiz answers given by a user for one word
+type UserWordResult struct {
+	UserID         uuid.UUID `gorm:"type:char(36);primaryKey;not null"`
+	DictionaryID   uuid.UUID `gorm:"type:char(36);primaryKey;not null"`
+	CorrectCount   int       `gorm:"type:int;default:0;not null"`
+	WrongCount     int       `gorm:"type:int;default:0;not null"`
+	LastAnsweredAt time.Time `gorm:"not null"`
+
+	User       User       `gorm:"foreignKey:UserID;references:ID;constraint:OnDelete:CASCADE"`
+	Dictionary Dictionary `gorm:"foreignKey:DictionaryID;references:ID;constraint:OnDelete:CASCADE"`
+}
@@ -0,0 +1,41 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/Ablebil/lathi-be/internal/domain/types"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	OutboxTopicLeaderboardScore = "leaderboard.update_score"
+)
+
+// side effect outside postgres, written in the same transaction as the change
+// that caused it and delivered later by the outbox worker
+type OutboxEvent struct {
+	ID          uuid.UUID   `json:"id" gorm:"type:char(36);primaryKey;not null"`
+	Topic       string      `json:"topic" gorm:"type:varchar(100);not null"`
+	Payload     types.JSONB `json:"payload" gorm:"type:jsonb;default:'{}'::jsonb;not null"`
+	Attempts    int         `json:"attempts" gorm:"type:int;default:0;not null"`
+	LastError   string      `json:"last_error" gorm:"type:text;default:'';not null"`
+	AvailableAt time.Time   `json:"available_at" gorm:"type:timestamp;not null;index:idx_outbox_pending,where:processed_at IS NULL"`
+	ProcessedAt *time.Time  `json:"processed_at" gorm:"type:timestamp"`
+	CreatedAt   time.Time   `json:"created_at" gorm:"type:timestamp;autoCreateTime;not null"`
+}
+
+func (e *OutboxEvent) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == uuid.Nil {
+		id, err := uuid.NewV7()
+		if err != nil {
+			return err
+		}
+		e.ID = id
+	}
+	return nil
+}
+
+type LeaderboardScorePayload struct {
+	UserID uuid.UUID `json:"user_id"`
+}
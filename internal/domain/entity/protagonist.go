@@ -0,0 +1,46 @@
+package entity
+
+import "strings"
+
+const (
+	DefaultProtagonist = "Andi"
+	NarratorName       = "Narator"
+	UnknownSpeaker     = "Panjenenganipun" // used when a slide has no speaker to blame
+
+	// PlayerPlaceholder in slide text, speakers and character names is replaced
+	// by the protagonist's name when the slide is served.
+	PlayerPlaceholder = "{player}"
+)
+
+// Protagonist is the character the player plays. Sprites map a key such as
+// "happy" to an image, slides show them by naming a {player} character with
+// the key as its image.
+type Protagonist struct {
+	Name    string
+	Sprites map[string]string
+}
+
+// WithDisplayName lets the player's own display name override the protagonist's.
+func (p Protagonist) WithDisplayName(name string) Protagonist {
+	if name = strings.TrimSpace(name); name != "" {
+		p.Name = name
+	}
+	return p
+}
+
+func (p Protagonist) Fill(s string) string {
+	return strings.ReplaceAll(s, PlayerPlaceholder, p.Name)
+}
+
+// Character resolves a {player} character to the protagonist's name and sprite.
+// Other characters are returned as they are.
+func (p Protagonist) Character(c Character) Character {
+	if c.Name != PlayerPlaceholder {
+		return c
+	}
+	c.Name = p.Name
+	if img, ok := p.Sprites[c.ImageURL]; ok {
+		c.ImageURL = img
+	}
+	return c
+}
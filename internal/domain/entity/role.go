@@ -0,0 +1,40 @@
+package entity
+
+type Role string
+
+const (
+	RolePlayer  Role = "player"
+	RoleTeacher Role = "teacher"
+	RoleEditor  Role = "editor"
+	RoleAdmin   Role = "admin"
+)
+
+type Permission string
+
+const (
+	PermContentRead    Permission = "content.read"    // see chapters, slides and the dictionary as authored
+	PermContentWrite   Permission = "content.write"   // edit slides, choices, chapter fields and the dictionary
+	PermContentManage  Permission = "content.manage"  // create, delete and reorder chapters, which moves player progress
+	PermContentPublish Permission = "content.publish" // publish a chapter draft to players or roll back to an older version
+)
+
+var rolePermissions = map[Role][]Permission{
+	RolePlayer:  {},
+	RoleTeacher: {PermContentRead},
+	RoleEditor:  {PermContentRead, PermContentWrite, PermContentPublish},
+	RoleAdmin:   {PermContentRead, PermContentWrite, PermContentPublish, PermContentManage},
+}
+
+func (r Role) IsValid() bool {
+	_, ok := rolePermissions[r]
+	return ok
+}
+
+func (r Role) Can(perm Permission) bool {
+	for _, p := range rolePermissions[r] {
+		if p == perm {
+			return true
+		}
+	}
+	return false
+}
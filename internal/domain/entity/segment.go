@@ -0,0 +1,66 @@
+package entity
+
+import (
+	"fmt"
+	"strings"
+)
+
+type SegmentType string
+
+const (
+	SegmentText   SegmentType = "text"
+	SegmentVocab  SegmentType = "vocab"  // {word} marker, the word is a krama dictionary entry on the slide
+	SegmentPlayer SegmentType = "player" // {player} placeholder
+)
+
+// Segment is a piece of story text. Vocab segments hold the word as written
+// between the braces.
+type Segment struct {
+	Type SegmentType
+	Text string
+}
+
+// ParseSegments splits story text into plain text, {word} vocabulary markers
+// and {player} placeholders. Markers can't be empty or nested and every brace
+// has to be part of one.
+func ParseSegments(s string) ([]Segment, error) {
+	var segs []Segment
+	for s != "" {
+		open := strings.IndexAny(s, "{}")
+		if open < 0 {
+			segs = append(segs, Segment{Type: SegmentText, Text: s})
+			break
+		}
+		if s[open] == '}' {
+			return nil, fmt.Errorf("closing brace without marker at %q", excerpt(s[open:]))
+		}
+		if open > 0 {
+			segs = append(segs, Segment{Type: SegmentText, Text: s[:open]})
+		}
+
+		rest := s[open+1:]
+		end := strings.IndexAny(rest, "{}")
+		if end < 0 || rest[end] == '{' {
+			return nil, fmt.Errorf("marker is not closed at %q", excerpt(s[open:]))
+		}
+
+		word := rest[:end]
+		switch {
+		case strings.TrimSpace(word) == "":
+			return nil, fmt.Errorf("empty marker at %q", excerpt(s[open:]))
+		case "{"+word+"}" == PlayerPlaceholder:
+			segs = append(segs, Segment{Type: SegmentPlayer, Text: PlayerPlaceholder})
+		default:
+			segs = append(segs, Segment{Type: SegmentVocab, Text: word})
+		}
+		s = rest[end+1:]
+	}
+	return segs, nil
+}
+
+func excerpt(s string) string {
+	if r := []rune(s); len(r) > 20 {
+		return string(r[:20]) + "..."
+	}
+	return s
+}
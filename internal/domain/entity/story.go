@@ -1,6 +1,9 @@
 package entity
 
 import (
+	"encoding/json"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/Ablebil/lathi-be/internal/domain/types"
@@ -8,14 +11,69 @@ import (
 	"gorm.io/gorm"
 )
 
-type Chapter struct {
+// slug of the story every player starts with, chapters from before stories
+// existed belong to it
+const MainStorySlug = "main"
+
+// Story is a book of chapters. Chapter order, progress and titles are kept
+// per story, so side stories don't change the main story's progress.
+type Story struct {
 	ID            uuid.UUID `json:"id" gorm:"type:char(36);primaryKey;not null"`
+	Slug          string    `json:"slug" gorm:"type:varchar(50);unique;not null"`
 	Title         string    `json:"title" gorm:"type:varchar(100);not null"`
 	Description   string    `json:"description" gorm:"type:text;not null"`
 	CoverImageURL string    `json:"cover_image_url" gorm:"type:varchar(255);not null"`
 	OrderIndex    int       `json:"order_index" gorm:"type:int;not null"`
+	BadgeCode     string    `json:"badge_code" gorm:"type:varchar(50);default:'';not null"` // awarded once every chapter of the story is completed
+
+	Chapters []Chapter `json:"chapters" gorm:"foreignKey:StoryID;references:ID;constraint:OnDelete:CASCADE"`
+}
+
+func (s *Story) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == uuid.Nil {
+		id, err := uuid.NewV7()
+		if err != nil {
+			return err
+		}
+		s.ID = id
+	}
+	return nil
+}
+
+// IsMain reports whether this is the story every player starts with.
+func (s *Story) IsMain() bool {
+	return s.Slug == MainStorySlug
+}
+
+// the title a user earned in a story, created with their first completed
+// chapter in it. The chapters themselves are in UserChapterCompletion.
+type UserStoryProgress struct {
+	UserID    uuid.UUID `json:"user_id" gorm:"type:char(36);primaryKey;not null"`
+	StoryID   uuid.UUID `json:"story_id" gorm:"type:char(36);primaryKey;not null"`
+	Title     Title     `json:"title" gorm:"type:varchar(255);default:'Cantrik';not null"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"type:timestamp;autoUpdateTime;not null"`
+
+	User  User  `gorm:"foreignKey:UserID;references:ID;constraint:OnDelete:CASCADE"`
+	Story Story `gorm:"foreignKey:StoryID;references:ID;constraint:OnDelete:CASCADE"`
+}
+
+type Chapter struct {
+	ID                 uuid.UUID   `json:"id" gorm:"type:char(36);primaryKey;not null"`
+	StoryID            uuid.UUID   `json:"story_id" gorm:"type:char(36);not null;index"`
+	Title              string      `json:"title" gorm:"type:varchar(100);not null"`
+	Description        string      `json:"description" gorm:"type:text;not null"`
+	CoverImageURL      string      `json:"cover_image_url" gorm:"type:varchar(255);not null"`
+	OrderIndex         int         `json:"order_index" gorm:"type:int;not null"` // position within the story
+	StartSlideID       *uuid.UUID  `json:"start_slide_id" gorm:"type:char(36)"`
+	EntryPoints        types.JSONB `json:"entry_points" gorm:"type:jsonb;default:'{}'::jsonb;not null"` // named slides a session can be (re)started from
+	ProtagonistName    string      `json:"protagonist_name" gorm:"type:varchar(100);default:'Andi';not null"`
+	ProtagonistSprites types.JSONB `json:"protagonist_sprites" gorm:"type:jsonb;default:'{}'::jsonb;not null"` // sprite key to image path
+	PublishedVersionID *uuid.UUID  `json:"-" gorm:"type:char(36)"`                                             // the version players get, nil until first published
+	PublishAt          *time.Time  `json:"publish_at,omitempty" gorm:"type:timestamp"`                         // players can start it from then on, nil releases it with the first publish
+	Unlock             types.JSONB `json:"unlock,omitempty" gorm:"type:jsonb;default:'{}'::jsonb;not null"`    // UnlockRules, like the order not part of the versions
 
-	Slides []Slide `json:"slides" gorm:"foreignKey:ChapterID;references:ID;constraint:OnDelete:CASCADE"`
+	Slides  []Slide         `json:"slides" gorm:"foreignKey:ChapterID;references:ID;constraint:OnDelete:CASCADE"`
+	Endings []ChapterEnding `json:"endings" gorm:"foreignKey:ChapterID;references:ID;constraint:OnDelete:CASCADE"`
 }
 
 func (c *Chapter) BeforeCreate(tx *gorm.DB) error {
@@ -29,17 +87,127 @@ func (c *Chapter) BeforeCreate(tx *gorm.DB) error {
 	return nil
 }
 
+// GetEntryPoints returns the named entry points of the chapter, keyed by name.
+func (c *Chapter) GetEntryPoints() (map[string]uuid.UUID, error) {
+	entries := make(map[string]uuid.UUID)
+	if len(c.EntryPoints) == 0 {
+		return entries, nil
+	}
+	if err := json.Unmarshal(c.EntryPoints, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// GetUnlockRules returns what a player needs before the chapter opens.
+func (c *Chapter) GetUnlockRules() (*UnlockRules, error) {
+	var rules UnlockRules
+	if len(c.Unlock) == 0 {
+		return &rules, nil
+	}
+	if err := json.Unmarshal(c.Unlock, &rules); err != nil {
+		return nil, err
+	}
+	return &rules, nil
+}
+
+// IsReleased reports whether the chapter's publish time has passed.
+func (c *Chapter) IsReleased(now time.Time) bool {
+	return c.PublishAt == nil || !c.PublishAt.After(now)
+}
+
+// Protagonist returns who the player plays in this chapter.
+func (c *Chapter) Protagonist() (Protagonist, error) {
+	p := Protagonist{Name: c.ProtagonistName}
+	if p.Name == "" {
+		p.Name = DefaultProtagonist
+	}
+	if len(c.ProtagonistSprites) == 0 {
+		return p, nil
+	}
+	if err := json.Unmarshal(c.ProtagonistSprites, &p.Sprites); err != nil {
+		return Protagonist{}, err
+	}
+	return p, nil
+}
+
+type EndingKind string
+
+const (
+	EndingGood    EndingKind = "good"
+	EndingNeutral EndingKind = "neutral"
+	EndingBad     EndingKind = "bad"
+)
+
+func (k EndingKind) IsValid() bool {
+	return k == EndingGood || k == EndingNeutral || k == EndingBad
+}
+
+type ChapterEnding struct {
+	ID         uuid.UUID  `json:"id" gorm:"type:char(36);primaryKey;not null"`
+	ChapterID  uuid.UUID  `json:"chapter_id" gorm:"type:char(36);not null;uniqueIndex:idx_chapter_ending_key"`
+	Key        string     `json:"key" gorm:"type:varchar(50);not null;uniqueIndex:idx_chapter_ending_key"`
+	Title      string     `json:"title" gorm:"type:varchar(100);not null"`
+	Kind       EndingKind `json:"kind" gorm:"type:varchar(20);default:'neutral';not null"`
+	BadgeCode  string     `json:"badge_code" gorm:"type:varchar(50);default:'';not null"` // awarded the first time the ending is reached
+	ScoreBonus int        `json:"score_bonus" gorm:"type:int;default:0;not null"`         // added to the leaderboard score once
+}
+
+func (ce *ChapterEnding) BeforeCreate(tx *gorm.DB) error {
+	if ce.ID == uuid.Nil {
+		id, err := uuid.NewV7()
+		if err != nil {
+			return err
+		}
+		ce.ID = id
+	}
+	return nil
+}
+
+// UserEnding is an ending the user has reached. Reached endings cannot be
+// deleted with their ending, whoever drops the ending drops them first and
+// takes the bonus they gave into account.
+type UserEnding struct {
+	UserID    uuid.UUID `gorm:"type:char(36);primaryKey;not null"`
+	EndingID  uuid.UUID `gorm:"type:char(36);primaryKey;not null"`
+	ReachedAt time.Time `gorm:"autoCreateTime;not null"`
+
+	User   User          `gorm:"foreignKey:UserID;references:ID;constraint:OnDelete:CASCADE"`
+	Ending ChapterEnding `gorm:"foreignKey:EndingID;references:ID;constraint:OnDelete:RESTRICT"`
+}
+
+// UserChapterCompletion is a chapter the user has finished. Chapters can be
+// finished in any order their unlock rules allow, so each one is recorded
+// rather than the furthest one reached.
+type UserChapterCompletion struct {
+	UserID      uuid.UUID `gorm:"type:char(36);primaryKey;not null"`
+	ChapterID   uuid.UUID `gorm:"type:char(36);primaryKey;not null"`
+	CompletedAt time.Time `gorm:"autoCreateTime;not null"`
+
+	User    User    `gorm:"foreignKey:UserID;references:ID;constraint:OnDelete:CASCADE"`
+	Chapter Chapter `gorm:"foreignKey:ChapterID;references:ID;constraint:OnDelete:CASCADE"`
+}
+
 type Slide struct {
 	ID                 uuid.UUID   `json:"id" gorm:"type:char(36);primaryKey;not null"`
-	ChapterID          uuid.UUID   `json:"chapter_id" gorm:"type:char(36);not null"`
+	ChapterID          uuid.UUID   `json:"chapter_id" gorm:"type:char(36);not null;uniqueIndex:idx_chapter_slide_key,where:key <> ''"`
+	Key                string      `json:"key" gorm:"type:varchar(50);default:'';not null;uniqueIndex:idx_chapter_slide_key"` // stable key used by chapter bundles
+	Type               SlideType   `json:"type" gorm:"type:varchar(20);default:'story';not null"`
+	IsCheckpoint       bool        `json:"is_checkpoint" gorm:"type:boolean;default:false;not null"` // game over resumes from the last checkpoint passed
 	BackgroundImageURL string      `json:"background_image_url" gorm:"type:varchar(255);not null"`
 	Characters         types.JSONB `json:"characters" gorm:"type:jsonb;default:'[]'::jsonb;not null"`
 	SpeakerName        string      `json:"speaker_name" gorm:"type:varchar(100);not null"`
 	Content            string      `json:"content" gorm:"type:text;not null"`
+	Subtitles          types.JSONB `json:"subtitles" gorm:"type:jsonb;default:'{}'::jsonb;not null"` // Subtitles of the content
 	NextSlideID        *uuid.UUID  `json:"next_slide_id" gorm:"type:char(36)"`
 	Choices            types.JSONB `json:"choices" gorm:"type:jsonb;default:'[]'::jsonb"`
+	Routes             types.JSONB `json:"routes" gorm:"type:jsonb;default:'[]'::jsonb;not null"` // conditional next slides, checked before NextSlideID
+	EndingID           *uuid.UUID  `json:"ending_id" gorm:"type:char(36)"`                        // reached when the story stops on this slide
+	Quiz               types.JSONB `json:"quiz" gorm:"type:jsonb;default:'{}'::jsonb;not null"`   // only used by quiz slides
+	Translation        types.JSONB `json:"translation" gorm:"type:jsonb;default:'{}'::jsonb;not null"`
 
-	Vocabularies []Dictionary `json:"vocabularies" gorm:"many2many:slide_vocabularies;constraint:OnDelete:CASCADE"`
+	Vocabularies []Dictionary   `json:"vocabularies" gorm:"many2many:slide_vocabularies;constraint:OnDelete:CASCADE"`
+	Ending       *ChapterEnding `json:"-" gorm:"foreignKey:EndingID;references:ID;constraint:OnDelete:SET NULL"`
 }
 
 func (s *Slide) BeforeCreate(tx *gorm.DB) error {
@@ -53,20 +221,240 @@ func (s *Slide) BeforeCreate(tx *gorm.DB) error {
 	return nil
 }
 
+func (s *Slide) GetCharacters() ([]Character, error) {
+	var chars []Character
+	if len(s.Characters) == 0 {
+		return chars, nil
+	}
+	if err := json.Unmarshal(s.Characters, &chars); err != nil {
+		return nil, err
+	}
+	return chars, nil
+}
+
+func (s *Slide) GetChoices() ([]Choice, error) {
+	var choices []Choice
+	if len(s.Choices) == 0 {
+		return choices, nil
+	}
+	if err := json.Unmarshal(s.Choices, &choices); err != nil {
+		return nil, err
+	}
+	return choices, nil
+}
+
+func (s *Slide) GetRoutes() ([]Route, error) {
+	var routes []Route
+	if len(s.Routes) == 0 {
+		return routes, nil
+	}
+	if err := json.Unmarshal(s.Routes, &routes); err != nil {
+		return nil, err
+	}
+	return routes, nil
+}
+
+// GetQuiz returns the quiz of a quiz slide, or nil for any other slide type.
+func (s *Slide) GetQuiz() (*Quiz, error) {
+	if s.Type != SlideQuiz {
+		return nil, nil
+	}
+	var quiz Quiz
+	if err := json.Unmarshal(s.Quiz, &quiz); err != nil {
+		return nil, err
+	}
+	return &quiz, nil
+}
+
+func (s *Slide) GetSubtitles() (Subtitles, error) {
+	var subs Subtitles
+	if len(s.Subtitles) == 0 {
+		return subs, nil
+	}
+	if err := json.Unmarshal(s.Subtitles, &subs); err != nil {
+		return nil, err
+	}
+	return subs, nil
+}
+
+// GetTranslation returns the exercise of a translation slide, or nil for any
+// other slide type.
+func (s *Slide) GetTranslation() (*Translation, error) {
+	if s.Type != SlideTranslate {
+		return nil, nil
+	}
+	var t Translation
+	if err := json.Unmarshal(s.Translation, &t); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+type SlideType string
+
+const (
+	SlideStory     SlideType = "story"
+	SlideQuiz      SlideType = "quiz"      // player picks the right translation of a dictionary word
+	SlideTranslate SlideType = "translate" // player types the krama translation of a prompt
+)
+
+// shape of Slide.Quiz
+type Quiz struct {
+	WordID        uuid.UUID   `json:"word_id"`
+	Ask           WordForm    `json:"ask"`    // form of the word shown to the player
+	Answer        WordForm    `json:"answer"` // form the player has to pick
+	DistractorIDs []uuid.UUID `json:"distractor_ids"`
+	HeartPenalty  int         `json:"heart_penalty"` // hearts lost on a wrong answer
+}
+
+func (q *Quiz) Validate() error {
+	if !q.Ask.IsValid() || !q.Answer.IsValid() {
+		return fmt.Errorf("quiz forms must be krama, ngoko or indo")
+	}
+	if q.Ask == q.Answer {
+		return fmt.Errorf("quiz asks and answers with the same form %q", q.Ask)
+	}
+	if q.HeartPenalty < 0 {
+		return fmt.Errorf("quiz heart_penalty must be >= 0")
+	}
+	return nil
+}
+
+// shape of Slide.Translation
+type Translation struct {
+	Prompt       string   `json:"prompt"`   // text to translate
+	Accepted     []string `json:"accepted"` // every answer graded as correct
+	HeartPenalty int      `json:"heart_penalty"`
+}
+
+// shape of each element in Slide.Characters
+type Character struct {
+	Name     string `json:"name"`
+	ImageURL string `json:"image_url"`
+	IsActive bool   `json:"is_active"`
+}
+
+// shape of each element in Slide.Choices
+type Choice struct {
+	Text        string     `json:"text"`
+	Subtitles   Subtitles  `json:"subtitles,omitempty"`
+	NextSlideID uuid.UUID  `json:"next_slide_id"`
+	MoodImpact  int        `json:"mood_impact"`
+	Effects     []Effect   `json:"effects,omitempty"`
+	ShowIf      *Condition `json:"show_if,omitempty"`   // hidden choices are never sent to the client
+	EnableIf    *Condition `json:"enable_if,omitempty"` // shown but not selectable when false
+}
+
+const (
+	EffectSet = "set"
+	EffectAdd = "add"
+
+	ScopeSession = "session" // lives on the chapter session
+	ScopeUser    = "user"    // carried across chapters
+)
+
+// variable change applied when a choice is picked, e.g. brought_gift=true or politeness+=2
+type Effect struct {
+	Var   string `json:"var"`
+	Op    string `json:"op"`
+	Value any    `json:"value"`
+	Scope string `json:"scope,omitempty"` // defaults to session
+}
+
+func (e Effect) IsUserScope() bool {
+	return e.Scope == ScopeUser
+}
+
+func (e Effect) Validate() error {
+	if strings.TrimSpace(e.Var) == "" {
+		return fmt.Errorf("effect without variable name")
+	}
+	if e.Scope != "" && e.Scope != ScopeSession && e.Scope != ScopeUser {
+		return fmt.Errorf("effect on %q has unknown scope %q", e.Var, e.Scope)
+	}
+
+	val, err := types.NormalizeValue(e.Value)
+	if err != nil {
+		return fmt.Errorf("effect on %q: %w", e.Var, err)
+	}
+
+	switch e.Op {
+	case EffectSet:
+	case EffectAdd:
+		if _, ok := val.(int); !ok {
+			return fmt.Errorf("effect on %q adds a non integer value", e.Var)
+		}
+	default:
+		return fmt.Errorf("effect on %q has unknown op %q", e.Var, e.Op)
+	}
+
+	return nil
+}
+
+func (e Effect) Apply(vars types.Variables) error {
+	if err := e.Validate(); err != nil {
+		return err
+	}
+	val, _ := types.NormalizeValue(e.Value)
+
+	if e.Op == EffectSet {
+		vars[e.Var] = val
+		return nil
+	}
+
+	cur, ok := vars[e.Var]
+	if !ok {
+		cur = 0
+	}
+	n, ok := cur.(int)
+	if !ok {
+		return fmt.Errorf("cannot add to non integer variable %q", e.Var)
+	}
+	vars[e.Var] = n + val.(int)
+	return nil
+}
+
+const (
+	DefaultSaveSlot = 1
+	MaxSaveSlots    = 3
+)
+
 type UserStorySession struct {
-	ID             uuid.UUID   `json:"id" gorm:"type:char(36);primaryKey;not null"`
-	UserID         uuid.UUID   `json:"user_id" gorm:"type:char(36);not null;uniqueIndex:idx_user_chapter"`
-	ChapterID      uuid.UUID   `json:"chapter_id" gorm:"type:char(36);not null;uniqueIndex:idx_user_chapter"`
-	CurrentSlideID uuid.UUID   `json:"current_slide_id" gorm:"type:char(36);not null"`
-	CurrentHearts  int         `json:"current_hearts" gorm:"type:int;default:3;not null"`
-	IsGameOver     bool        `json:"is_game_over" gorm:"type:boolean;default:false;not null"`
-	IsCompleted    bool        `json:"is_completed" gorm:"type:boolean;default:false;not null"`
-	HistoryLog     types.JSONB `json:"history_log" gorm:"type:jsonb;default:'[]'::jsonb;not null"`
-	CreatedAt      time.Time   `json:"created_at" gorm:"type:timestamp;autoCreateTime;not null"`
-	UpdatedAt      time.Time   `json:"updated_at" gorm:"type:timestamp;autoUpdateTime;not null"`
+	ID             uuid.UUID       `json:"id" gorm:"type:char(36);primaryKey;not null"`
+	UserID         uuid.UUID       `json:"user_id" gorm:"type:char(36);not null;uniqueIndex:idx_user_chapter_slot"`
+	ChapterID      uuid.UUID       `json:"chapter_id" gorm:"type:char(36);not null;uniqueIndex:idx_user_chapter_slot"`
+	Slot           int             `json:"slot" gorm:"type:int;default:1;not null;uniqueIndex:idx_user_chapter_slot"` // save slot, 1 is the main run
+	Name           string          `json:"name" gorm:"type:varchar(50);default:'';not null"`
+	CurrentSlideID uuid.UUID       `json:"current_slide_id" gorm:"type:char(36);not null"`
+	CurrentHearts  int             `json:"current_hearts" gorm:"type:int;default:3;not null"`
+	IsGameOver     bool            `json:"is_game_over" gorm:"type:boolean;default:false;not null"`
+	IsCompleted    bool            `json:"is_completed" gorm:"type:boolean;default:false;not null"`
+	HistoryFrom    int             `json:"history_from" gorm:"type:int;default:0;not null"` // events up to this sequence belong to earlier runs of the slot
+	Variables      types.Variables `json:"variables" gorm:"type:jsonb;default:'{}'::jsonb;not null"`
+	Version        int             `json:"version" gorm:"type:int;default:0;not null"` // bumped on every applied action
+	EndingID       *uuid.UUID      `json:"ending_id" gorm:"type:char(36)"`
 
-	User    User    `gorm:"foreignKey:UserID;references:ID;constraint:OnDelete:CASCADE"`
-	Chapter Chapter `gorm:"foreignKey:ChapterID;references:ID;constraint:OnDelete:CASCADE"`
+	// the chapter version the run plays, kept until the slot is restarted
+	ChapterVersionID *uuid.UUID `json:"chapter_version_id" gorm:"type:char(36)"`
+
+	// state saved when the session last arrived at a checkpoint slide, restored after a game over
+	CheckpointSlideID   *uuid.UUID      `json:"checkpoint_slide_id" gorm:"type:char(36)"`
+	CheckpointHearts    int             `json:"checkpoint_hearts" gorm:"type:int;default:0;not null"`
+	CheckpointVariables types.Variables `json:"checkpoint_variables" gorm:"type:jsonb;default:'{}'::jsonb;not null"`
+
+	CreatedAt time.Time `json:"created_at" gorm:"type:timestamp;autoCreateTime;not null"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"type:timestamp;autoUpdateTime;not null"`
+
+	User           User            `gorm:"foreignKey:UserID;references:ID;constraint:OnDelete:CASCADE"`
+	Chapter        Chapter         `gorm:"foreignKey:ChapterID;references:ID;constraint:OnDelete:CASCADE"`
+	ChapterVersion *ChapterVersion `gorm:"foreignKey:ChapterVersionID;references:ID;constraint:OnDelete:SET NULL"`
+}
+
+// SaveCheckpoint snapshots the session state at the given checkpoint slide.
+func (uss *UserStorySession) SaveCheckpoint(slideID uuid.UUID) {
+	uss.CheckpointSlideID = &slideID
+	uss.CheckpointHearts = uss.CurrentHearts
+	uss.CheckpointVariables = uss.Variables.Clone()
 }
 
 func (uss *UserStorySession) BeforeCreate(tx *gorm.DB) error {
@@ -79,3 +467,80 @@ func (uss *UserStorySession) BeforeCreate(tx *gorm.DB) error {
 	}
 	return nil
 }
+
+// result of an action applied to a session, kept so retries get the same answer
+type UserStoryAction struct {
+	ID          uuid.UUID   `json:"id" gorm:"type:char(36);primaryKey;not null"`
+	SessionID   uuid.UUID   `json:"session_id" gorm:"type:char(36);not null;uniqueIndex:idx_session_version"`
+	Version     int         `json:"version" gorm:"type:int;not null;uniqueIndex:idx_session_version"` // session version the action was applied to
+	SlideID     uuid.UUID   `json:"slide_id" gorm:"type:char(36);not null"`
+	ChoiceIndex *int        `json:"choice_index" gorm:"type:int"`
+	Answer      *string     `json:"answer" gorm:"type:text"` // typed answer on translation slides
+	Response    types.JSONB `json:"response" gorm:"type:jsonb;not null"`
+	CreatedAt   time.Time   `json:"created_at" gorm:"type:timestamp;autoCreateTime;not null"`
+
+	Session UserStorySession `gorm:"foreignKey:SessionID;references:ID;constraint:OnDelete:CASCADE"`
+}
+
+func (usa *UserStoryAction) BeforeCreate(tx *gorm.DB) error {
+	if usa.ID == uuid.Nil {
+		id, err := uuid.NewV7()
+		if err != nil {
+			return err
+		}
+		usa.ID = id
+	}
+	return nil
+}
+
+// Matches reports whether a request describes the same action, a retried
+// answer that differs from the graded one is a different action.
+func (usa *UserStoryAction) Matches(slideID uuid.UUID, choiceIndex *int, answer *string) bool {
+	if usa.SlideID != slideID {
+		return false
+	}
+	return samePtr(usa.ChoiceIndex, choiceIndex) && samePtr(usa.Answer, answer)
+}
+
+func samePtr[T comparable](a, b *T) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+// StoryEvent is one line of a session's dialogue history. Events are only ever
+// appended, a restarted run starts reading after the session's HistoryFrom.
+type StoryEvent struct {
+	ID          uuid.UUID  `json:"id" gorm:"type:char(36);primaryKey;not null"`
+	SessionID   uuid.UUID  `json:"session_id" gorm:"type:char(36);not null;uniqueIndex:idx_session_sequence"`
+	Sequence    int        `json:"sequence" gorm:"type:int;not null;uniqueIndex:idx_session_sequence"`
+	Speaker     string     `json:"speaker" gorm:"type:varchar(100);not null"`
+	Text        string     `json:"text" gorm:"type:text;not null"`
+	IsUser      bool       `json:"is_user" gorm:"type:boolean;default:false;not null"`
+	ChoiceIndex *int       `json:"choice_index" gorm:"type:int"`
+	SlideID     *uuid.UUID `json:"slide_id" gorm:"type:char(36)"`
+	CreatedAt   time.Time  `json:"created_at" gorm:"type:timestamp;autoCreateTime;not null"`
+
+	Session UserStorySession `gorm:"foreignKey:SessionID;references:ID;constraint:OnDelete:CASCADE"`
+}
+
+func (se *StoryEvent) BeforeCreate(tx *gorm.DB) error {
+	if se.ID == uuid.Nil {
+		id, err := uuid.NewV7()
+		if err != nil {
+			return err
+		}
+		se.ID = id
+	}
+	return nil
+}
+
+// per-user variables carried across chapters, set by effects with the user scope
+type UserStoryVariables struct {
+	UserID    uuid.UUID       `json:"user_id" gorm:"type:char(36);primaryKey;not null"`
+	Variables types.Variables `json:"variables" gorm:"type:jsonb;default:'{}'::jsonb;not null"`
+	UpdatedAt time.Time       `json:"updated_at" gorm:"type:timestamp;autoUpdateTime;not null"`
+
+	User User `gorm:"foreignKey:UserID;references:ID;constraint:OnDelete:CASCADE"`
+}
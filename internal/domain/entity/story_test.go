@@ -0,0 +1,146 @@
+package entity
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/Ablebil/lathi-be/internal/domain/types"
+	"github.com/google/uuid"
+)
+
+func TestEffectValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		effect  Effect
+		wantErr bool
+	}{
+		{"set bool", Effect{Var: "met_sekar", Op: EffectSet, Value: true}, false},
+		{"set string", Effect{Var: "route", Op: EffectSet, Value: "pasar"}, false},
+		{"add int", Effect{Var: "gifts", Op: EffectAdd, Value: 1}, false},
+		{"add whole float", Effect{Var: "gifts", Op: EffectAdd, Value: 2.0}, false},
+		{"user scope", Effect{Var: "respect", Op: EffectAdd, Value: 1, Scope: ScopeUser}, false},
+		{"session scope", Effect{Var: "gifts", Op: EffectAdd, Value: 1, Scope: ScopeSession}, false},
+		{"no variable", Effect{Var: " ", Op: EffectSet, Value: true}, true},
+		{"unknown scope", Effect{Var: "gifts", Op: EffectSet, Value: 1, Scope: "global"}, true},
+		{"unknown op", Effect{Var: "gifts", Op: "mul", Value: 2}, true},
+		{"add string", Effect{Var: "gifts", Op: EffectAdd, Value: "1"}, true},
+		{"fractional number", Effect{Var: "gifts", Op: EffectSet, Value: 1.5}, true},
+		{"unsupported value", Effect{Var: "gifts", Op: EffectSet, Value: []int{1}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.effect.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEffectApply(t *testing.T) {
+	tests := []struct {
+		name    string
+		vars    types.Variables
+		effects []Effect
+		want    types.Variables
+		wantErr bool
+	}{
+		{
+			name:    "set",
+			vars:    types.Variables{},
+			effects: []Effect{{Var: "met_sekar", Op: EffectSet, Value: true}},
+			want:    types.Variables{"met_sekar": true},
+		},
+		{
+			name:    "set overwrites",
+			vars:    types.Variables{"route": "pasar"},
+			effects: []Effect{{Var: "route", Op: EffectSet, Value: 3}},
+			want:    types.Variables{"route": 3},
+		},
+		{
+			name:    "add starts from zero",
+			vars:    types.Variables{},
+			effects: []Effect{{Var: "gifts", Op: EffectAdd, Value: 2}},
+			want:    types.Variables{"gifts": 2},
+		},
+		{
+			name:    "add accumulates",
+			vars:    types.Variables{"gifts": 2},
+			effects: []Effect{{Var: "gifts", Op: EffectAdd, Value: -1}, {Var: "gifts", Op: EffectAdd, Value: 3.0}},
+			want:    types.Variables{"gifts": 4},
+		},
+		{
+			name:    "add json number",
+			vars:    types.Variables{"gifts": 1},
+			effects: []Effect{{Var: "gifts", Op: EffectAdd, Value: json.Number("2")}},
+			want:    types.Variables{"gifts": 3},
+		},
+		{
+			name:    "add to a non integer",
+			vars:    types.Variables{"gifts": "many"},
+			effects: []Effect{{Var: "gifts", Op: EffectAdd, Value: 1}},
+			want:    types.Variables{"gifts": "many"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid effect leaves variables alone",
+			vars:    types.Variables{"gifts": 1},
+			effects: []Effect{{Var: "gifts", Op: "mul", Value: 2}},
+			want:    types.Variables{"gifts": 1},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var err error
+			for _, e := range tt.effects {
+				if err = e.Apply(tt.vars); err != nil {
+					break
+				}
+			}
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Apply() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(tt.vars, tt.want) {
+				t.Errorf("variables = %v, want %v", tt.vars, tt.want)
+			}
+		})
+	}
+}
+
+func TestUserStoryActionMatches(t *testing.T) {
+	slide, other := uuid.New(), uuid.New()
+	one, two := 1, 2
+	kula, aku := "kula", "aku"
+
+	choice := &UserStoryAction{SlideID: slide, ChoiceIndex: &one}
+	answer := &UserStoryAction{SlideID: slide, Answer: &kula}
+
+	tests := []struct {
+		name   string
+		action *UserStoryAction
+		slide  uuid.UUID
+		choice *int
+		answer *string
+		want   bool
+	}{
+		{"same choice", choice, slide, &one, nil, true},
+		{"other choice", choice, slide, &two, nil, false},
+		{"no choice", choice, slide, nil, nil, false},
+		{"other slide", choice, other, &one, nil, false},
+		{"same answer", answer, slide, nil, &kula, true},
+		{"other answer", answer, slide, nil, &aku, false},
+		{"no answer", answer, slide, nil, nil, false},
+		{"plain slide", &UserStoryAction{SlideID: slide}, slide, nil, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.action.Matches(tt.slide, tt.choice, tt.answer); got != tt.want {
+				t.Errorf("Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
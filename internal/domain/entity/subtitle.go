@@ -0,0 +1,15 @@
+package entity
+
+// Story text is written in Javanese, subtitles translate it for players who
+// are just starting out.
+const (
+	SubtitleIndonesian = "id"
+	SubtitleEnglish    = "en"
+)
+
+func IsSubtitleLanguage(lang string) bool {
+	return lang == SubtitleIndonesian || lang == SubtitleEnglish
+}
+
+// Subtitles map a subtitle language to the translated text.
+type Subtitles map[string]string
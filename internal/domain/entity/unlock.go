@@ -0,0 +1,49 @@
+package entity
+
+import (
+	"github.com/google/uuid"
+)
+
+// UnlockRules are what a player needs before a chapter opens. Every rule that
+// is set has to hold. A chapter without rules opens once the chapter before it
+// in its story is completed.
+type UnlockRules struct {
+	ChaptersCompleted []uuid.UUID `json:"chapters_completed,omitempty" yaml:"chapters_completed,omitempty"`
+	MinVocab          int         `json:"min_vocab,omitempty" yaml:"min_vocab,omitempty"` // dictionary words collected
+	EndingsReached    []uuid.UUID `json:"endings_reached,omitempty" yaml:"endings_reached,omitempty"`
+}
+
+// player progress unlock rules are checked against
+type UnlockState struct {
+	CompletedChapters map[uuid.UUID]bool
+	WordsCollected    int
+	ReachedEndings    map[uuid.UUID]bool
+}
+
+func (u *UnlockRules) IsEmpty() bool {
+	return u == nil || (len(u.ChaptersCompleted) == 0 && u.MinVocab <= 0 && len(u.EndingsReached) == 0)
+}
+
+// Unmet keeps the rules the player doesn't meet yet, the chapter is unlocked
+// when the result is empty.
+func (u *UnlockRules) Unmet(st *UnlockState) UnlockRules {
+	var unmet UnlockRules
+	if u == nil {
+		return unmet
+	}
+
+	for _, id := range u.ChaptersCompleted {
+		if !st.CompletedChapters[id] {
+			unmet.ChaptersCompleted = append(unmet.ChaptersCompleted, id)
+		}
+	}
+	if st.WordsCollected < u.MinVocab {
+		unmet.MinVocab = u.MinVocab
+	}
+	for _, id := range u.EndingsReached {
+		if !st.ReachedEndings[id] {
+			unmet.EndingsReached = append(unmet.EndingsReached, id)
+		}
+	}
+	return unmet
+}
@@ -15,18 +15,39 @@ const (
 	Priyayi Title = "Priyayi"
 )
 
+// TitleFor gives the title earned by completing the given number of chapters
+// out of total.
+func TitleFor(completed, total int) Title {
+	if total <= 0 {
+		return Cantrik
+	}
+
+	progress := (float64(completed) / float64(total)) * 100
+	if progress <= 30 {
+		return Cantrik
+	} else if progress <= 70 {
+		return Abdi
+	}
+	return Priyayi
+}
+
 type User struct {
-	ID                   uuid.UUID `json:"id" gorm:"type:char(36);primaryKey;not null"`
-	Username             string    `json:"username" gorm:"type:varchar(50);unique;not null"`
-	Email                string    `json:"email" gorm:"type:varchar(255);unique;not null"`
-	Password             string    `json:"password" gorm:"type:varchar(255);not null"`
-	AvatarURL            string    `json:"avatar_url" gorm:"type:varchar(255);not null"`
-	CurrentTitle         Title     `json:"current_title" gorm:"type:varchar(255);default:'Cantrik';not null"`
-	LastChapterCompleted int       `json:"last_chapter_completed" gorm:"type:int;default:0;not null"`
-	TotalWordsCollected  int       `json:"total_words_collected" gorm:"type:int;default:0;not null"`
-	IsVerified           bool      `json:"is_verified" gorm:"type:boolean;default:false;not null"`
-	CreatedAt            time.Time `json:"created_at" gorm:"type:timestamp;autoCreateTime;not null"`
-	UpdatedAt            time.Time `json:"updated_at" gorm:"type:timestamp;autoUpdateTime;not null"`
+	ID                  uuid.UUID `json:"id" gorm:"type:char(36);primaryKey;not null"`
+	Username            string    `json:"username" gorm:"type:varchar(50);unique;not null"`
+	DisplayName         string    `json:"display_name" gorm:"type:varchar(50);default:'';not null"`     // replaces the protagonist's name in stories when set
+	Language            string    `json:"language" gorm:"type:varchar(5);default:'';not null"`          // preferred language for messages, empty follows Accept-Language
+	SubtitleLanguage    string    `json:"subtitle_language" gorm:"type:varchar(5);default:'';not null"` // story subtitles, empty shows none
+	Email               string    `json:"email" gorm:"type:varchar(255);unique;not null"`
+	Password            string    `json:"password" gorm:"type:varchar(255);not null"`
+	Role                Role      `json:"role" gorm:"type:varchar(20);default:'player';not null"`
+	AvatarURL           string    `json:"avatar_url" gorm:"type:varchar(255);not null"`
+	CurrentTitle        Title     `json:"current_title" gorm:"type:varchar(255);default:'Cantrik';not null"` // earned over every story
+	ChaptersCompleted   int       `json:"chapters_completed" gorm:"type:int;default:0;not null"`             // chapters completed over every story
+	TotalWordsCollected int       `json:"total_words_collected" gorm:"type:int;default:0;not null"`
+	BonusScore          int       `json:"bonus_score" gorm:"type:int;default:0;not null"` // earned from endings
+	IsVerified          bool      `json:"is_verified" gorm:"type:boolean;default:false;not null"`
+	CreatedAt           time.Time `json:"created_at" gorm:"type:timestamp;autoCreateTime;not null"`
+	UpdatedAt           time.Time `json:"updated_at" gorm:"type:timestamp;autoUpdateTime;not null"`
 
 	Badges         []Badge            `json:"badges" gorm:"many2many:user_badges;constraint:OnDelete:CASCADE"`
 	UserBadges     []UserBadge        `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
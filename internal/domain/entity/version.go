@@ -0,0 +1,100 @@
+package entity
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+
+	"github.com/Ablebil/lathi-be/internal/domain/types"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ChapterVersion is a published snapshot of a chapter. The chapter, slide and
+// ending rows are the draft editors work on, players only ever see a version.
+type ChapterVersion struct {
+	ID            uuid.UUID   `json:"id" gorm:"type:char(36);primaryKey;not null"`
+	ChapterID     uuid.UUID   `json:"chapter_id" gorm:"type:char(36);not null;uniqueIndex:idx_chapter_version_number"`
+	Number        int         `json:"number" gorm:"type:int;not null;uniqueIndex:idx_chapter_version_number"` // 1, 2, 3... per chapter
+	Title         string      `json:"title" gorm:"type:varchar(100);not null"`
+	Description   string      `json:"description" gorm:"type:text;not null"`
+	CoverImageURL string      `json:"cover_image_url" gorm:"type:varchar(255);not null"`
+	Content       types.JSONB `json:"-" gorm:"type:jsonb;not null"` // the chapter with its slides and endings as published
+	PublishedAt   time.Time   `json:"published_at" gorm:"type:timestamp;autoCreateTime;not null"`
+
+	Chapter Chapter `json:"-" gorm:"foreignKey:ChapterID;references:ID;constraint:OnDelete:CASCADE"`
+}
+
+func (cv *ChapterVersion) BeforeCreate(tx *gorm.DB) error {
+	if cv.ID == uuid.Nil {
+		id, err := uuid.NewV7()
+		if err != nil {
+			return err
+		}
+		cv.ID = id
+	}
+	return nil
+}
+
+// NewChapterVersion snapshots the chapter loaded with its slides, their
+// vocabularies and its endings.
+func NewChapterVersion(chapter *Chapter, number int) (*ChapterVersion, error) {
+	content, err := json.Marshal(snapshotOf(chapter))
+	if err != nil {
+		return nil, err
+	}
+
+	return &ChapterVersion{
+		ChapterID:     chapter.ID,
+		Number:        number,
+		Title:         chapter.Title,
+		Description:   chapter.Description,
+		CoverImageURL: chapter.CoverImageURL,
+		Content:       types.JSONB(content),
+	}, nil
+}
+
+// GetChapter decodes the snapshot. The story, order index, publish time and
+// unlock rules are not part of it, they are not versioned.
+func (cv *ChapterVersion) GetChapter() (*Chapter, error) {
+	var chapter Chapter
+	if err := json.Unmarshal(cv.Content, &chapter); err != nil {
+		return nil, err
+	}
+	return &chapter, nil
+}
+
+// SameAs reports whether publishing the chapter would give the same snapshot.
+func (cv *ChapterVersion) SameAs(chapter *Chapter) (bool, error) {
+	published, err := cv.GetChapter()
+	if err != nil {
+		return false, err
+	}
+
+	// both sides are encoded again, jsonb doesn't keep the stored formatting
+	a, err := json.Marshal(snapshotOf(published))
+	if err != nil {
+		return false, err
+	}
+	b, err := json.Marshal(snapshotOf(chapter))
+	if err != nil {
+		return false, err
+	}
+	return bytes.Equal(a, b), nil
+}
+
+func snapshotOf(chapter *Chapter) Chapter {
+	snap := *chapter
+	snap.StoryID = uuid.Nil
+	snap.OrderIndex = 0
+	snap.PublishedVersionID = nil
+	snap.PublishAt = nil
+	snap.Unlock = nil
+	if snap.Slides == nil {
+		snap.Slides = []Slide{}
+	}
+	if snap.Endings == nil {
+		snap.Endings = []ChapterEnding{}
+	}
+	return snap
+}
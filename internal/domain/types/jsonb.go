@@ -23,3 +23,20 @@ func (j JSONB) Value() (driver.Value, error) {
 	}
 	return json.RawMessage(j).MarshalJSON()
 }
+
+// MarshalJSON keeps the value as raw JSON when a struct holding it is encoded,
+// e.g. in a chapter snapshot, instead of base64 encoding the bytes.
+func (j JSONB) MarshalJSON() ([]byte, error) {
+	if len(j) == 0 {
+		return []byte("null"), nil
+	}
+	return json.RawMessage(j).MarshalJSON()
+}
+
+func (j *JSONB) UnmarshalJSON(data []byte) error {
+	if j == nil {
+		return errors.New("types.JSONB: UnmarshalJSON on nil pointer")
+	}
+	*j = append((*j)[0:0], data...)
+	return nil
+}
@@ -0,0 +1,91 @@
+package types
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Variables holds story flags and counters. Values are always bool, int or string.
+type Variables map[string]any
+
+func (v *Variables) Scan(value any) error {
+	data, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+	return v.UnmarshalJSON(data)
+}
+
+func (v Variables) Value() (driver.Value, error) {
+	if v == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(map[string]any(v))
+}
+
+func (v *Variables) UnmarshalJSON(data []byte) error {
+	var raw map[string]any
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&raw); err != nil {
+		return err
+	}
+
+	vars := make(Variables, len(raw))
+	for name, val := range raw {
+		norm, err := NormalizeValue(val)
+		if err != nil {
+			return fmt.Errorf("variable %q: %w", name, err)
+		}
+		vars[name] = norm
+	}
+
+	*v = vars
+	return nil
+}
+
+func (v Variables) Bool(name string) bool {
+	b, _ := v[name].(bool)
+	return b
+}
+
+func (v Variables) Int(name string) int {
+	n, _ := v[name].(int)
+	return n
+}
+
+func (v Variables) Clone() Variables {
+	c := make(Variables, len(v))
+	for name, val := range v {
+		c[name] = val
+	}
+	return c
+}
+
+// NormalizeValue converts a decoded json or yaml value into one of the
+// supported variable types.
+func NormalizeValue(val any) (any, error) {
+	switch n := val.(type) {
+	case bool, string, int:
+		return n, nil
+	case int64:
+		return int(n), nil
+	case float64:
+		if n != math.Trunc(n) {
+			return nil, fmt.Errorf("number %v is not an integer", n)
+		}
+		return int(n), nil
+	case json.Number:
+		i, err := n.Int64()
+		if err != nil {
+			return nil, fmt.Errorf("number %s is not an integer", n)
+		}
+		return int(i), nil
+	default:
+		return nil, fmt.Errorf("unsupported value type %T", val)
+	}
+}
@@ -15,16 +15,20 @@ type CronJobItf interface {
 }
 
 type cronJob struct {
-	userRepo contract.UserRepositoryItf
-	lbRepo   contract.LeaderboardRepositoryItf
-	cron     *cron.Cron
+	userRepo   contract.UserRepositoryItf
+	lbRepo     contract.LeaderboardRepositoryItf
+	outboxRepo contract.OutboxRepositoryItf
+	outboxUc   contract.OutboxUsecaseItf
+	cron       *cron.Cron
 }
 
-func NewCronJob(userRepo contract.UserRepositoryItf, lbRepo contract.LeaderboardRepositoryItf) CronJobItf {
+func NewCronJob(userRepo contract.UserRepositoryItf, lbRepo contract.LeaderboardRepositoryItf, outboxRepo contract.OutboxRepositoryItf, outboxUc contract.OutboxUsecaseItf) CronJobItf {
 	return &cronJob{
-		userRepo: userRepo,
-		lbRepo:   lbRepo,
-		cron:     cron.New(),
+		userRepo:   userRepo,
+		lbRepo:     lbRepo,
+		outboxRepo: outboxRepo,
+		outboxUc:   outboxUc,
+		cron:       cron.New(),
 	}
 }
 
@@ -33,6 +37,8 @@ func (c *cronJob) Start() {
 
 	c.deleteUnverifiedUsersJob()
 	c.rebuildLeaderboardJob()
+	c.dispatchOutboxJob()
+	c.cleanupOutboxJob()
 
 	c.cron.Start()
 	slog.Info("all cron jobs started successfully")
@@ -88,3 +94,52 @@ func (c *cronJob) rebuildLeaderboardJob() {
 
 	slog.Info("rebuild leaderboard job registered", "schedule", "every 6 hours")
 }
+
+func (c *cronJob) dispatchOutboxJob() {
+	// run every 10 seconds, skipped while the previous run is still going
+	job := cron.NewChain(cron.SkipIfStillRunning(cron.DiscardLogger)).Then(cron.FuncJob(func() {
+		ctx := context.Background()
+
+		delivered, err := c.outboxUc.Dispatch(ctx)
+		if err != nil {
+			slog.Error("failed to dispatch outbox events", "error", err)
+			return
+		}
+
+		if delivered > 0 {
+			slog.Info("outbox events delivered", "count", delivered)
+		}
+	}))
+
+	if _, err := c.cron.AddJob("@every 10s", job); err != nil {
+		slog.Error("failed to register dispatch outbox job", "error", err)
+		return
+	}
+
+	slog.Info("dispatch outbox job registered", "schedule", "every 10 seconds")
+}
+
+func (c *cronJob) cleanupOutboxJob() {
+	// run every day at 03:00
+	_, err := c.cron.AddFunc("0 3 * * *", func() {
+		ctx := context.Background()
+		threshold := time.Now().Add(-7 * 24 * time.Hour)
+
+		deleted, err := c.outboxRepo.DeleteProcessed(ctx, threshold)
+		if err != nil {
+			slog.Error("failed to clean up outbox events", "error", err)
+			return
+		}
+
+		if deleted > 0 {
+			slog.Info("deleted processed outbox events", "count", deleted, "threshold", threshold)
+		}
+	})
+
+	if err != nil {
+		slog.Error("failed to register clean up outbox job", "error", err)
+		return
+	}
+
+	slog.Info("clean up outbox job registered", "schedule", "every day")
+}
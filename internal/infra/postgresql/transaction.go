@@ -0,0 +1,44 @@
+package postgresql
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+type TransactorItf interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type txKey struct{}
+
+type transactor struct {
+	db *gorm.DB
+}
+
+func NewTransactor(db *gorm.DB) TransactorItf {
+	return &transactor{
+		db: db,
+	}
+}
+
+// WithinTransaction runs fn in a transaction carried by the context, so every
+// repository call made with that context joins it. Nested calls reuse the
+// outer transaction.
+func (t *transactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return fn(ctx)
+	}
+
+	return t.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	})
+}
+
+// Conn returns the transaction stored in ctx, or db bound to ctx when there is none.
+func Conn(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return tx
+	}
+	return db.WithContext(ctx)
+}
@@ -3,6 +3,7 @@ package middleware
 import (
 	"strings"
 
+	"github.com/Ablebil/lathi-be/pkg/i18n"
 	"github.com/Ablebil/lathi-be/pkg/response"
 	"github.com/gofiber/fiber/v2"
 )
@@ -10,24 +11,28 @@ import (
 func (m *middleware) Authenticate(ctx *fiber.Ctx) error {
 	header := ctx.Get("Authorization")
 	if header == "" {
-		return response.Error(ctx, response.ErrUnauthorized("Kamu belum login, yuk login dulu"), nil)
+		return response.Error(ctx, response.ErrUnauthorized(i18n.AuthNotLoggedIn), nil)
 	}
 
 	parts := strings.Fields(header)
 	if len(parts) != 2 || strings.ToLower(parts[0]) != "bearer" {
-		return response.Error(ctx, response.ErrUnauthorized("Kamu belum login, yuk login dulu"), nil)
+		return response.Error(ctx, response.ErrUnauthorized(i18n.AuthNotLoggedIn), nil)
 	}
 
 	token := parts[1]
 
 	validate, err := m.jwt.ParseAccessToken(token)
 	if err != nil {
-		return response.Error(ctx, response.ErrUnauthorized("Sesi kamu udah habis, coba login lagi ya"), nil)
+		return response.Error(ctx, response.ErrUnauthorized(i18n.AuthSessionExpired), nil)
 	}
 
 	ctx.Locals("user_id", validate.Subject)
 	ctx.Locals("username", validate.Username)
 	ctx.Locals("email", validate.Email)
+	ctx.Locals("role", validate.Role)
+	if lang := i18n.Lang(validate.Lang); lang.IsValid() {
+		ctx.Locals(i18n.ContextKey, lang)
+	}
 
 	return ctx.Next()
 }
@@ -0,0 +1,14 @@
+package middleware
+
+import (
+	"github.com/Ablebil/lathi-be/pkg/i18n"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Localize picks the response language from Accept-Language. Authenticate
+// overrides it with the user's preference when they have one.
+func (m *middleware) Localize(ctx *fiber.Ctx) error {
+	ctx.Locals(i18n.ContextKey, i18n.Parse(ctx.Get(fiber.HeaderAcceptLanguage)))
+	ctx.Vary(fiber.HeaderAcceptLanguage)
+	return ctx.Next()
+}
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/Ablebil/lathi-be/internal/config"
+	"github.com/Ablebil/lathi-be/internal/domain/entity"
 	"github.com/Ablebil/lathi-be/internal/infra/redis"
 	"github.com/Ablebil/lathi-be/pkg/jwt"
 	"github.com/gofiber/fiber/v2"
@@ -17,6 +18,9 @@ type middleware struct {
 
 type MiddlewareItf interface {
 	Authenticate(ctx *fiber.Ctx) error
+	Localize(ctx *fiber.Ctx) error
+	RequireRole(roles ...entity.Role) fiber.Handler
+	RequirePermission(perm entity.Permission) fiber.Handler
 	RateLimit(limit int, window time.Duration, keyPrefix string) fiber.Handler
 }
 
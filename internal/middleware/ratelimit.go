@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/Ablebil/lathi-be/internal/config"
+	"github.com/Ablebil/lathi-be/pkg/i18n"
 	"github.com/Ablebil/lathi-be/pkg/response"
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
@@ -21,7 +22,7 @@ func (m *middleware) RateLimit(limit int, window time.Duration, keyPrefix string
 		key := fmt.Sprintf("rl:%s:%s", keyPrefix, identifier)
 		count, err := m.cache.Incr(ctx.Context(), key, window)
 		if err != nil {
-			return response.Error(ctx, response.ErrInternal("Coba lagi nanti ya!"), err)
+			return response.Error(ctx, response.ErrInternal(i18n.CommonTryAgain), err)
 		}
 
 		ctx.Set("X-RateLimit-Limit", fmt.Sprintf("%d", limit))
@@ -37,7 +38,7 @@ func (m *middleware) RateLimit(limit int, window time.Duration, keyPrefix string
 			}
 
 			ctx.Set("Retry-After", fmt.Sprintf("%d", int(window.Seconds())))
-			return response.Error(ctx, response.ErrTooManyRequests("Terlalu banyak permintaan, coba lagi nanti ya"), nil)
+			return response.Error(ctx, response.ErrTooManyRequests(i18n.CommonTooManyRequests), nil)
 		}
 		return ctx.Next()
 	}
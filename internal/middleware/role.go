@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"github.com/Ablebil/lathi-be/internal/domain/entity"
+	"github.com/Ablebil/lathi-be/pkg/i18n"
+	"github.com/Ablebil/lathi-be/pkg/response"
+	"github.com/gofiber/fiber/v2"
+)
+
+// RequireRole lets through users with one of the roles. It runs after
+// Authenticate, the role comes from the access token so a promotion applies
+// from the next login or token refresh.
+func (m *middleware) RequireRole(roles ...entity.Role) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		role, ok := userRole(ctx)
+		if !ok {
+			return response.Error(ctx, response.ErrUnauthorized(i18n.AuthNotLoggedIn), nil)
+		}
+
+		for _, r := range roles {
+			if r == role {
+				return ctx.Next()
+			}
+		}
+
+		return response.Error(ctx, response.ErrForbidden(i18n.AuthForbidden), nil)
+	}
+}
+
+// RequirePermission lets through users whose role grants the permission. It
+// runs after Authenticate.
+func (m *middleware) RequirePermission(perm entity.Permission) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		role, ok := userRole(ctx)
+		if !ok {
+			return response.Error(ctx, response.ErrUnauthorized(i18n.AuthNotLoggedIn), nil)
+		}
+
+		if !role.Can(perm) {
+			return response.Error(ctx, response.ErrForbidden(i18n.AuthForbidden), nil)
+		}
+
+		return ctx.Next()
+	}
+}
+
+// tokens issued before roles existed carry none, they are players
+func userRole(ctx *fiber.Ctx) (entity.Role, bool) {
+	role, ok := ctx.Locals("role").(string)
+	if !ok {
+		return "", false
+	}
+	if role == "" {
+		return entity.RolePlayer, true
+	}
+	return entity.Role(role), true
+}
@@ -0,0 +1,94 @@
+package i18n
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type Lang string
+
+const (
+	Indonesian Lang = "id"
+	Javanese   Lang = "jv"
+	English    Lang = "en"
+
+	Default = Indonesian
+)
+
+// ContextKey is where the request language is kept. Fiber locals are exposed
+// through the request context, so usecases can read it with FromContext.
+const ContextKey = "lang"
+
+func (l Lang) IsValid() bool {
+	switch l {
+	case Indonesian, Javanese, English:
+		return true
+	}
+	return false
+}
+
+// Parse picks the supported language with the highest weight from an
+// Accept-Language header. Regions are ignored, so en-US counts as en.
+func Parse(header string) Lang {
+	type candidate struct {
+		lang Lang
+		q    float64
+	}
+
+	var candidates []candidate
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		base, _, _ := strings.Cut(strings.TrimSpace(tag), "-")
+		lang := Lang(strings.ToLower(base))
+		if !lang.IsValid() {
+			continue
+		}
+
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q > 0 {
+			candidates = append(candidates, candidate{lang, q})
+		}
+	}
+
+	if len(candidates) == 0 {
+		return Default
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].q > candidates[j].q })
+	return candidates[0].lang
+}
+
+func FromContext(ctx context.Context) Lang {
+	if lang, ok := ctx.Value(ContextKey).(Lang); ok && lang.IsValid() {
+		return lang
+	}
+	return Default
+}
+
+// T returns the message for the code in the language, falling back to
+// Indonesian when it has no translation and to the code itself when the
+// code is unknown.
+func T(lang Lang, code string, args ...any) string {
+	texts, ok := catalog[code]
+	if !ok {
+		return code
+	}
+
+	text, ok := texts[lang]
+	if !ok {
+		text = texts[Default]
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(text, args...)
+	}
+	return text
+}
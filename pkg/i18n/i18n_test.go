@@ -0,0 +1,45 @@
+package i18n
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   Lang
+	}{
+		{"empty", "", Default},
+		{"single", "en", English},
+		{"region ignored", "en-US", English},
+		{"upper case", "JV", Javanese},
+		{"unsupported falls back", "fr, de", Default},
+		{"skips unsupported", "fr, jv", Javanese},
+		{"highest weight wins", "id;q=0.5, en;q=0.9", English},
+		{"no weight means one", "en;q=0.9, jv", Javanese},
+		{"ties keep header order", "jv;q=0.8, en;q=0.8", Javanese},
+		{"zero weight excluded", "en;q=0, jv;q=0.1", Javanese},
+		{"malformed weight skipped", "en;q=abc, jv;q=0.2", Javanese},
+		{"wildcard ignored", "*, en;q=0.5", English},
+		{"spaces", "  en-GB ; q=0.7 ,id;q=0.3", English},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Parse(tt.header); got != tt.want {
+				t.Errorf("Parse(%q) = %q, want %q", tt.header, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestT(t *testing.T) {
+	if got := T(English, StoryBookNotFound); got != "Story not found" {
+		t.Errorf("T(en) = %q", got)
+	}
+	if got := T(Indonesian, StoryBookNotFound); got != "Cerita ini ga ketemu" {
+		t.Errorf("T(id) = %q", got)
+	}
+	if got := T(English, "unknown.code"); got != "unknown.code" {
+		t.Errorf("T(unknown) = %q, want the code", got)
+	}
+}
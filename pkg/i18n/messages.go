@@ -0,0 +1,715 @@
+package i18n
+
+// Message codes are part of the API, clients may switch on them. Never rename
+// one, add a new code instead.
+const (
+	// error types, used as the message of every error of that type
+	ErrorInternal        = "error.internal"
+	ErrorNotFound        = "error.not_found"
+	ErrorUnauthorized    = "error.unauthorized"
+	ErrorBadRequest      = "error.bad_request"
+	ErrorForbidden       = "error.forbidden"
+	ErrorConflict        = "error.conflict"
+	ErrorOutOfSync       = "error.out_of_sync"
+	ErrorTooManyRequests = "error.too_many_requests"
+	ErrorValidation      = "error.validation"
+	ErrorUnprocessable   = "error.unprocessable"
+
+	CommonTryAgain          = "common.try_again"
+	CommonInvalidBody       = "common.invalid_body"
+	CommonInvalidFields     = "common.invalid_fields"
+	CommonInvalidQuery      = "common.invalid_query"
+	CommonUnknownQueryParam = "common.unknown_query_param" // query parameter name
+	CommonEmptyQueryParam   = "common.empty_query_param"   // query parameter name
+	CommonInvalidPage       = "common.invalid_page"
+	CommonInvalidLimit      = "common.invalid_limit"
+	CommonTooManyRequests   = "common.too_many_requests"
+
+	AuthNotLoggedIn          = "auth.not_logged_in"
+	AuthSessionExpired       = "auth.session_expired"
+	AuthInvalidCredentials   = "auth.invalid_credentials"
+	AuthNotVerified          = "auth.not_verified"
+	AuthForbidden            = "auth.forbidden"
+	AuthAlreadyVerified      = "auth.already_verified"
+	AuthInvalidVerification  = "auth.invalid_verification"
+	AuthEmailTaken           = "auth.email_taken"
+	AuthRegistered           = "auth.registered"
+	AuthVerified             = "auth.verified"
+	AuthLoggedIn             = "auth.logged_in"
+	AuthRefreshed            = "auth.refreshed"
+	AuthLoggedOut            = "auth.logged_out"
+	UserNotFound             = "user.not_found"
+	UserUsernameTaken        = "user.username_taken"
+	UserSameUsername         = "user.same_username"
+	UserNothingToUpdate      = "user.nothing_to_update"
+	UserProfileLoaded        = "user.profile_loaded"
+	UserProfileUpdated       = "user.profile_updated"
+	DictionaryLoaded         = "dictionary.loaded"
+	LeaderboardLoaded        = "leaderboard.loaded"
+	StoryBooksLoaded         = "story.books_loaded"
+	StoryBookLoaded          = "story.book_loaded"
+	StoryBookNotFound        = "story.book_not_found"
+	StoryChaptersLoaded      = "story.chapters_loaded"
+	StoryContentLoaded       = "story.content_loaded"
+	StoryChapterNotFound     = "story.chapter_not_found"
+	StoryChapterNotReady     = "story.chapter_not_ready"
+	StoryChapterEmpty        = "story.chapter_empty"
+	StoryChapterLocked       = "story.chapter_locked"
+	StoryChapterNotReleased  = "story.chapter_not_released"
+	StoryProgressLoaded      = "story.progress_loaded"
+	StoryStarted             = "story.started"
+	StoryResumed             = "story.resumed"
+	StoryNotStarted          = "story.not_started"
+	StoryNotFinished         = "story.not_finished"
+	StoryFinished            = "story.finished"
+	StoryNoCheckpoint        = "story.no_checkpoint"
+	StorySlideNotFound       = "story.slide_not_found"
+	StorySlideNotInChapter   = "story.slide_not_in_chapter"
+	StoryOutOfSync           = "story.out_of_sync"
+	StoryNoChoices           = "story.no_choices"
+	StoryChoiceRequired      = "story.choice_required"
+	StoryInvalidChoice       = "story.invalid_choice"
+	StoryQuizRequired        = "story.quiz_required"
+	StoryInvalidAnswer       = "story.invalid_answer"
+	StoryTranslationRequired = "story.translation_required"
+	StoryNoTypedAnswer       = "story.no_typed_answer"
+	StoryInvalidDepth        = "story.invalid_depth"
+	StoryUpcomingLoaded      = "story.upcoming_loaded"
+	StoryActionProcessed     = "story.action_processed"
+	StoryGameOver            = "story.game_over" // speaker, player name
+	StoryCompleted           = "story.completed"
+	StoryHistoryLoaded       = "story.history_loaded"
+	StoryAttemptsLoaded      = "story.attempts_loaded"
+	SaveListLoaded           = "save.list_loaded"
+	SaveCreated              = "save.created"
+	SaveLoaded               = "save.loaded"
+	SaveDeleted              = "save.deleted"
+	SaveNotFound             = "save.not_found"
+	SaveSourceNotFound       = "save.source_not_found"
+	SaveSlotTaken            = "save.slot_taken"
+	SaveSlotsFull            = "save.slots_full"
+	AdminInvalidStory        = "admin.invalid_story"
+	AdminStoriesLoaded       = "admin.stories_loaded"
+	AdminStoryCreated        = "admin.story_created"
+	AdminStoryUpdated        = "admin.story_updated"
+	AdminStorySlugTaken      = "admin.story_slug_taken"
+	AdminChaptersLoaded      = "admin.chapters_loaded"
+	AdminChapterLoaded       = "admin.chapter_loaded"
+	AdminChapterCreated      = "admin.chapter_created"
+	AdminChapterUpdated      = "admin.chapter_updated"
+	AdminChapterDeleted      = "admin.chapter_deleted"
+	AdminChaptersReordered   = "admin.chapters_reordered"
+	AdminInvalidOrder        = "admin.invalid_order"
+	AdminSlideLoaded         = "admin.slide_loaded"
+	AdminSlideCreated        = "admin.slide_created"
+	AdminSlideUpdated        = "admin.slide_updated"
+	AdminSlideDeleted        = "admin.slide_deleted"
+	AdminSlideKeyTaken       = "admin.slide_key_taken"
+	AdminChoiceSaved         = "admin.choice_saved"
+	AdminChoiceDeleted       = "admin.choice_deleted"
+	AdminChoiceNotFound      = "admin.choice_not_found"
+	AdminChoicesNotAllowed   = "admin.choices_not_allowed"
+	AdminWordsLoaded         = "admin.words_loaded"
+	AdminWordCreated         = "admin.word_created"
+	AdminWordUpdated         = "admin.word_updated"
+	AdminWordDeleted         = "admin.word_deleted"
+	AdminWordNotFound        = "admin.word_not_found"
+	AdminWordTaken           = "admin.word_taken"
+	AdminWordInUse           = "admin.word_in_use"
+	AdminChapterPublished    = "admin.chapter_published"
+	AdminNothingToPublish    = "admin.nothing_to_publish"
+	AdminVersionsLoaded      = "admin.versions_loaded"
+	AdminVersionNotFound     = "admin.version_not_found"
+	AdminVersionIsPublished  = "admin.version_is_published"
+	AdminChapterRolledBack   = "admin.chapter_rolled_back"
+)
+
+var catalog = map[string]map[Lang]string{
+	ErrorInternal: {
+		Indonesian: "Coba lagi nanti ya!",
+		Javanese:   "Cobanen maneh mengko ya!",
+		English:    "Please try again later!",
+	},
+	ErrorNotFound: {
+		Indonesian: "Data ga ditemukan",
+		Javanese:   "Data ora ketemu",
+		English:    "Data not found",
+	},
+	ErrorUnauthorized: {
+		Indonesian: "Kamu belum login, yuk login dulu",
+		Javanese:   "Sampeyan durung mlebu, ayo mlebu dhisik",
+		English:    "You're not logged in yet, log in first",
+	},
+	ErrorBadRequest: {
+		Indonesian: "Data yang dikirimkan salah",
+		Javanese:   "Data sing dikirim salah",
+		English:    "The data you sent is invalid",
+	},
+	ErrorForbidden: {
+		Indonesian: "Kamu ga punya akses ke sini",
+		Javanese:   "Sampeyan ora duwe akses mrene",
+		English:    "You don't have access to this",
+	},
+	ErrorConflict: {
+		Indonesian: "Data udah ada sebelumnya",
+		Javanese:   "Data wis ana sadurunge",
+		English:    "This data already exists",
+	},
+	ErrorOutOfSync: {
+		Indonesian: "Progressmu ga sinkron, yuk lanjut dari posisi terakhir",
+		Javanese:   "Progresmu ora cocog, ayo nerusake saka posisi pungkasan",
+		English:    "Your progress is out of sync, continue from your last position",
+	},
+	ErrorTooManyRequests: {
+		Indonesian: "Terlalu banyak permintaan, coba lagi nanti ya",
+		Javanese:   "Kakehan panjaluk, cobanen maneh mengko ya",
+		English:    "Too many requests, please try again later",
+	},
+	ErrorValidation: {
+		Indonesian: "Ups, ada data yang ga sesuai nih",
+		Javanese:   "Waduh, ana data sing ora cocog",
+		English:    "Oops, some of the data doesn't look right",
+	},
+	ErrorUnprocessable: {
+		Indonesian: "Perubahan ini ga bisa disimpan",
+		Javanese:   "Owah-owahan iki ora bisa disimpen",
+		English:    "This change can't be saved",
+	},
+
+	CommonTryAgain: {
+		Indonesian: "Coba lagi nanti ya!",
+		Javanese:   "Cobanen maneh mengko ya!",
+		English:    "Please try again later!",
+	},
+	CommonInvalidBody: {
+		Indonesian: "Data yang kamu kirim belum pas, coba cek lagi ya",
+		Javanese:   "Data sing mbok kirim durung pas, priksa maneh ya",
+		English:    "The data you sent isn't quite right, please check it again",
+	},
+	CommonInvalidFields: {
+		Indonesian: "Cek lagi isian yang ditandai ya",
+		Javanese:   "Priksa maneh isian sing ditandhani ya",
+		English:    "Please check the highlighted fields",
+	},
+	CommonInvalidQuery: {
+		Indonesian: "Format query ga valid",
+		Javanese:   "Format query ora bener",
+		English:    "Invalid query format",
+	},
+	CommonUnknownQueryParam: {
+		Indonesian: "Query parameter '%s' ga dikenali",
+		Javanese:   "Query parameter '%s' ora dikenal",
+		English:    "Unknown query parameter '%s'",
+	},
+	CommonEmptyQueryParam: {
+		Indonesian: "Query parameter '%s' ga boleh kosong",
+		Javanese:   "Query parameter '%s' ora kena kosong",
+		English:    "Query parameter '%s' must not be empty",
+	},
+	CommonInvalidPage: {
+		Indonesian: "Halaman ga valid",
+		Javanese:   "Kaca ora bener",
+		English:    "Invalid page",
+	},
+	CommonInvalidLimit: {
+		Indonesian: "Jumlah data per halaman ga valid",
+		Javanese:   "Cacahe data saben kaca ora bener",
+		English:    "Invalid number of items per page",
+	},
+	CommonTooManyRequests: {
+		Indonesian: "Terlalu banyak permintaan, coba lagi nanti ya",
+		Javanese:   "Kakehan panjaluk, cobanen maneh mengko ya",
+		English:    "Too many requests, please try again later",
+	},
+
+	AuthNotLoggedIn: {
+		Indonesian: "Kamu belum login, yuk login dulu",
+		Javanese:   "Sampeyan durung mlebu, ayo mlebu dhisik",
+		English:    "You're not logged in yet, log in first",
+	},
+	AuthSessionExpired: {
+		Indonesian: "Sesi kamu udah habis, coba login lagi ya",
+		Javanese:   "Sesimu wis entek, cobanen mlebu maneh ya",
+		English:    "Your session has expired, please log in again",
+	},
+	AuthInvalidCredentials: {
+		Indonesian: "Email atau password kamu salah",
+		Javanese:   "Email utawa passwordmu salah",
+		English:    "Wrong email or password",
+	},
+	AuthNotVerified: {
+		Indonesian: "Akunmu belum terverifikasi, cek email kamu ya",
+		Javanese:   "Akunmu durung diverifikasi, priksa emailmu ya",
+		English:    "Your account isn't verified yet, check your email",
+	},
+	AuthForbidden: {
+		Indonesian: "Peranmu belum punya izin buat fitur ini",
+		Javanese:   "Peranmu durung duwe idin kanggo fitur iki",
+		English:    "Your role doesn't allow this feature",
+	},
+	AuthAlreadyVerified: {
+		Indonesian: "Akunmu udah terverifikasi sebelumnya",
+		Javanese:   "Akunmu wis diverifikasi sadurunge",
+		English:    "Your account is already verified",
+	},
+	AuthInvalidVerification: {
+		Indonesian: "Token verifikasi ga valid atau udah kadaluarsa, coba daftar lagi ya",
+		Javanese:   "Token verifikasi ora bener utawa wis kadaluwarsa, cobanen ndaftar maneh ya",
+		English:    "The verification token is invalid or expired, please register again",
+	},
+	AuthEmailTaken: {
+		Indonesian: "Email ini udah pernah didaftarin, coba email lain ya",
+		Javanese:   "Email iki wis tau didaftarke, cobanen email liyane ya",
+		English:    "This email is already registered, try another one",
+	},
+	AuthRegistered: {
+		Indonesian: "Pendaftaran berhasil, cek email kamu buat verifikasi, ya",
+		Javanese:   "Pendaftaran kasil, priksa emailmu kanggo verifikasi ya",
+		English:    "Registration successful, check your email to verify your account",
+	},
+	AuthVerified: {
+		Indonesian: "Email kamu udah diverifikasi, yuk login sekarang!",
+		Javanese:   "Emailmu wis diverifikasi, ayo mlebu saiki!",
+		English:    "Your email is verified, log in now!",
+	},
+	AuthLoggedIn: {
+		Indonesian: "Login sukses! Yuk mulai eksplorasi!",
+		Javanese:   "Kasil mlebu! Ayo miwiti njelajah!",
+		English:    "Logged in! Let's start exploring!",
+	},
+	AuthRefreshed: {
+		Indonesian: "Sesi kamu udah diperbarui, yuk lanjut eksplorasi!",
+		Javanese:   "Sesimu wis dianyari, ayo nerusake njelajah!",
+		English:    "Your session is refreshed, keep exploring!",
+	},
+	AuthLoggedOut: {
+		Indonesian: "Logout berhasil, sampai jumpa lagi!",
+		Javanese:   "Kasil metu, sampai ketemu maneh!",
+		English:    "Logged out, see you again!",
+	},
+
+	UserNotFound: {
+		Indonesian: "Akun ga ditemukan, coba daftar dulu ya",
+		Javanese:   "Akun ora ketemu, cobanen ndaftar dhisik ya",
+		English:    "Account not found, please register first",
+	},
+	UserUsernameTaken: {
+		Indonesian: "Username ini udah dipake, coba yang lain ya",
+		Javanese:   "Username iki wis dienggo, cobanen liyane ya",
+		English:    "This username is taken, try another one",
+	},
+	UserSameUsername: {
+		Indonesian: "Username baru sama dengan yang lama",
+		Javanese:   "Username anyar padha karo sing lawas",
+		English:    "The new username is the same as the old one",
+	},
+	UserNothingToUpdate: {
+		Indonesian: "Ga ada data yang diubah",
+		Javanese:   "Ora ana data sing diganti",
+		English:    "Nothing to update",
+	},
+	UserProfileLoaded: {
+		Indonesian: "Profilmu berhasil dimuat",
+		Javanese:   "Profilmu kasil dimuat",
+		English:    "Profile loaded",
+	},
+	UserProfileUpdated: {
+		Indonesian: "Profilmu berhasil diperbarui",
+		Javanese:   "Profilmu kasil dianyari",
+		English:    "Profile updated",
+	},
+
+	DictionaryLoaded: {
+		Indonesian: "Kamus berhasil dimuat",
+		Javanese:   "Kamus kasil dimuat",
+		English:    "Dictionary loaded",
+	},
+	LeaderboardLoaded: {
+		Indonesian: "Leaderboard berhasil dimuat",
+		Javanese:   "Papan peringkat kasil dimuat",
+		English:    "Leaderboard loaded",
+	},
+
+	StoryBooksLoaded: {
+		Indonesian: "Daftar cerita berhasil dimuat",
+		Javanese:   "Dhaptar crita kasil dimuat",
+		English:    "Stories loaded",
+	},
+	StoryBookLoaded: {
+		Indonesian: "Cerita berhasil dimuat",
+		Javanese:   "Crita kasil dimuat",
+		English:    "Story loaded",
+	},
+	StoryBookNotFound: {
+		Indonesian: "Cerita ini ga ketemu",
+		Javanese:   "Crita iki ora ketemu",
+		English:    "Story not found",
+	},
+	StoryChaptersLoaded: {
+		Indonesian: "Daftar chapter berhasil dimuat",
+		Javanese:   "Dhaptar chapter kasil dimuat",
+		English:    "Chapters loaded",
+	},
+	StoryContentLoaded: {
+		Indonesian: "Konten chapter berhasil dimuat",
+		Javanese:   "Isi chapter kasil dimuat",
+		English:    "Chapter content loaded",
+	},
+	StoryChapterNotFound: {
+		Indonesian: "Chapter ini ga ketemu",
+		Javanese:   "Chapter iki ora ketemu",
+		English:    "Chapter not found",
+	},
+	StoryChapterNotReady: {
+		Indonesian: "Chapter ini belum siap dimainkan",
+		Javanese:   "Chapter iki durung siap dimainake",
+		English:    "This chapter isn't ready to play yet",
+	},
+	StoryChapterLocked: {
+		Indonesian: "Chapter ini masih terkunci, penuhi dulu syaratnya ya",
+		Javanese:   "Chapter iki isih kakunci, rampungna dhisik syarate ya",
+		English:    "This chapter is still locked, meet its requirements first",
+	},
+	StoryChapterNotReleased: {
+		Indonesian: "Chapter ini belum rilis, tunggu sebentar lagi ya",
+		Javanese:   "Chapter iki durung dirilis, entenana sedhela maneh ya",
+		English:    "This chapter isn't out yet, hang on a little longer",
+	},
+	StoryChapterEmpty: {
+		Indonesian: "Chapter ini belum punya konten",
+		Javanese:   "Chapter iki durung ana isine",
+		English:    "This chapter has no content yet",
+	},
+	StoryProgressLoaded: {
+		Indonesian: "Progressmu berhasil dipulihkan",
+		Javanese:   "Progresmu kasil dibalekake",
+		English:    "Progress restored",
+	},
+	StoryStarted: {
+		Indonesian: "Permainan dimulai, semangat ya!",
+		Javanese:   "Dolanan diwiwiti, semangat ya!",
+		English:    "Game started, good luck!",
+	},
+	StoryResumed: {
+		Indonesian: "Lanjut dari checkpoint terakhir, semangat!",
+		Javanese:   "Nerusake saka checkpoint pungkasan, semangat!",
+		English:    "Continuing from the last checkpoint, good luck!",
+	},
+	StoryNotStarted: {
+		Indonesian: "Kamu belum memulai chapter ini, yuk mulai dulu ya!",
+		Javanese:   "Sampeyan durung miwiti chapter iki, ayo diwiwiti dhisik!",
+		English:    "You haven't started this chapter yet, start it first!",
+	},
+	StoryNotFinished: {
+		Indonesian: "Permainanmu belum selesai, lanjutin aja ya!",
+		Javanese:   "Dolananmu durung rampung, terusna wae ya!",
+		English:    "Your game isn't over yet, just keep going!",
+	},
+	StoryFinished: {
+		Indonesian: "Permainan udah selesai, coba mulai lagi ya!",
+		Javanese:   "Dolanan wis rampung, cobanen miwiti maneh ya!",
+		English:    "The game is over, try starting again!",
+	},
+	StoryNoCheckpoint: {
+		Indonesian: "Kamu belum lewat checkpoint, coba mulai lagi dari awal ya",
+		Javanese:   "Sampeyan durung liwat checkpoint, cobanen miwiti maneh saka wiwitan ya",
+		English:    "You haven't reached a checkpoint yet, start again from the beginning",
+	},
+	StorySlideNotFound: {
+		Indonesian: "Slide ga ketemu",
+		Javanese:   "Slide ora ketemu",
+		English:    "Slide not found",
+	},
+	StorySlideNotInChapter: {
+		Indonesian: "Slide ini bukan bagian dari chapter ini",
+		Javanese:   "Slide iki dudu bageyan saka chapter iki",
+		English:    "This slide isn't part of this chapter",
+	},
+	StoryOutOfSync: {
+		Indonesian: "Posisimu di cerita udah berubah, lanjut dari slide terakhir ya",
+		Javanese:   "Posisimu ing crita wis owah, terusna saka slide pungkasan ya",
+		English:    "Your position in the story has changed, continue from the latest slide",
+	},
+	StoryNoChoices: {
+		Indonesian: "Slide ini ga punya pilihan buat dipilih",
+		Javanese:   "Slide iki ora duwe pilihan sing bisa dipilih",
+		English:    "This slide has no choices to pick",
+	},
+	StoryChoiceRequired: {
+		Indonesian: "Kamu harus milih salah satu pilihan yang ada",
+		Javanese:   "Sampeyan kudu milih salah siji pilihan sing ana",
+		English:    "You have to pick one of the choices",
+	},
+	StoryInvalidChoice: {
+		Indonesian: "Pilihanmu ga valid",
+		Javanese:   "Pilihanmu ora bener",
+		English:    "Invalid choice",
+	},
+	StoryQuizRequired: {
+		Indonesian: "Jawab kuisnya dulu ya!",
+		Javanese:   "Jawaben kuise dhisik ya!",
+		English:    "Answer the quiz first!",
+	},
+	StoryInvalidAnswer: {
+		Indonesian: "Jawabanmu ga valid",
+		Javanese:   "Wangsulanmu ora bener",
+		English:    "Invalid answer",
+	},
+	StoryTranslationRequired: {
+		Indonesian: "Ketik terjemahanmu dulu ya!",
+		Javanese:   "Ketiken terjemahanmu dhisik ya!",
+		English:    "Type your translation first!",
+	},
+	StoryNoTypedAnswer: {
+		Indonesian: "Slide ini ga butuh jawaban ketikan",
+		Javanese:   "Slide iki ora butuh wangsulan ketikan",
+		English:    "This slide doesn't take a typed answer",
+	},
+	StoryInvalidDepth: {
+		Indonesian: "Kedalaman cabang ga valid",
+		Javanese:   "Ambane cabang ora bener",
+		English:    "Invalid branch depth",
+	},
+	StoryUpcomingLoaded: {
+		Indonesian: "Slide berikutnya berhasil dimuat",
+		Javanese:   "Slide sabanjure kasil dimuat",
+		English:    "Upcoming slides loaded",
+	},
+	StoryActionProcessed: {
+		Indonesian: "Aksimu berhasil diproses!",
+		Javanese:   "Aksimu kasil diproses!",
+		English:    "Action processed!",
+	},
+	StoryGameOver: {
+		Indonesian: "%s kecewa sama omonganmu, %s. Coba lagi ya!",
+		Javanese:   "%s kuciwo karo omonganmu, %s. Coba maneh ya!",
+		English:    "%s is disappointed by what you said, %s. Try again!",
+	},
+	StoryCompleted: {
+		Indonesian: "Selamat! Kamu udah menamatkan cerita ini.",
+		Javanese:   "Sugeng! Sampeyan wis rampung crita iki.",
+		English:    "Congratulations! You've finished this story.",
+	},
+	StoryHistoryLoaded: {
+		Indonesian: "Riwayat obrolan berhasil dimuat",
+		Javanese:   "Riwayat obrolan kasil dimuat",
+		English:    "Conversation history loaded",
+	},
+	StoryAttemptsLoaded: {
+		Indonesian: "Riwayat permainan berhasil dimuat",
+		Javanese:   "Riwayat dolanan kasil dimuat",
+		English:    "Play history loaded",
+	},
+
+	SaveListLoaded: {
+		Indonesian: "Daftar simpanan berhasil dimuat",
+		Javanese:   "Dhaptar simpenan kasil dimuat",
+		English:    "Saves loaded",
+	},
+	SaveCreated: {
+		Indonesian: "Simpanan baru berhasil dibuat",
+		Javanese:   "Simpenan anyar kasil digawe",
+		English:    "Save created",
+	},
+	SaveLoaded: {
+		Indonesian: "Simpanan berhasil dimuat",
+		Javanese:   "Simpenan kasil dimuat",
+		English:    "Save loaded",
+	},
+	SaveDeleted: {
+		Indonesian: "Simpanan berhasil dihapus",
+		Javanese:   "Simpenan kasil dibusak",
+		English:    "Save deleted",
+	},
+	SaveNotFound: {
+		Indonesian: "Slot simpanan ini ga ketemu",
+		Javanese:   "Slot simpenan iki ora ketemu",
+		English:    "Save slot not found",
+	},
+	SaveSourceNotFound: {
+		Indonesian: "Slot yang mau disalin ga ketemu",
+		Javanese:   "Slot sing arep disalin ora ketemu",
+		English:    "The slot to copy from was not found",
+	},
+	SaveSlotTaken: {
+		Indonesian: "Slot ini udah kepake, pilih slot lain ya",
+		Javanese:   "Slot iki wis dienggo, pilihen slot liyane ya",
+		English:    "This slot is already in use, pick another one",
+	},
+	SaveSlotsFull: {
+		Indonesian: "Slot simpananmu udah penuh, hapus salah satu dulu ya",
+		Javanese:   "Slot simpenanmu wis kebak, busaken salah siji dhisik ya",
+		English:    "Your save slots are full, delete one first",
+	},
+
+	AdminInvalidStory: {
+		Indonesian: "Perubahan ini bikin alur cerita rusak, cek daftar masalahnya ya",
+		Javanese:   "Owah-owahan iki gawe alur crita rusak, priksa dhaptar masalahe ya",
+		English:    "This change breaks the story graph, check the listed issues",
+	},
+	AdminStoriesLoaded: {
+		Indonesian: "Daftar cerita berhasil dimuat",
+		Javanese:   "Dhaptar crita kasil dimuat",
+		English:    "Stories loaded",
+	},
+	AdminStoryCreated: {
+		Indonesian: "Cerita berhasil dibuat",
+		Javanese:   "Crita kasil digawe",
+		English:    "Story created",
+	},
+	AdminStoryUpdated: {
+		Indonesian: "Cerita berhasil diperbarui",
+		Javanese:   "Crita kasil dianyari",
+		English:    "Story updated",
+	},
+	AdminStorySlugTaken: {
+		Indonesian: "Slug ini udah dipakai cerita lain",
+		Javanese:   "Slug iki wis dienggo crita liya",
+		English:    "This slug is already used by another story",
+	},
+	AdminChaptersLoaded: {
+		Indonesian: "Daftar chapter berhasil dimuat",
+		Javanese:   "Dhaptar chapter kasil dimuat",
+		English:    "Chapters loaded",
+	},
+	AdminChapterLoaded: {
+		Indonesian: "Chapter berhasil dimuat",
+		Javanese:   "Chapter kasil dimuat",
+		English:    "Chapter loaded",
+	},
+	AdminChapterCreated: {
+		Indonesian: "Chapter berhasil dibuat",
+		Javanese:   "Chapter kasil digawe",
+		English:    "Chapter created",
+	},
+	AdminChapterUpdated: {
+		Indonesian: "Chapter berhasil diperbarui",
+		Javanese:   "Chapter kasil dianyari",
+		English:    "Chapter updated",
+	},
+	AdminChapterDeleted: {
+		Indonesian: "Chapter berhasil dihapus",
+		Javanese:   "Chapter kasil dibusak",
+		English:    "Chapter deleted",
+	},
+	AdminChaptersReordered: {
+		Indonesian: "Urutan chapter berhasil diperbarui",
+		Javanese:   "Urutan chapter kasil dianyari",
+		English:    "Chapter order updated",
+	},
+	AdminInvalidOrder: {
+		Indonesian: "Urutan harus memuat semua chapter di cerita ini tepat satu kali",
+		Javanese:   "Urutan kudu ngemot kabeh chapter ing crita iki pas sepisan",
+		English:    "The order must list every chapter of the story exactly once",
+	},
+	AdminSlideLoaded: {
+		Indonesian: "Slide berhasil dimuat",
+		Javanese:   "Slide kasil dimuat",
+		English:    "Slide loaded",
+	},
+	AdminSlideCreated: {
+		Indonesian: "Slide berhasil dibuat",
+		Javanese:   "Slide kasil digawe",
+		English:    "Slide created",
+	},
+	AdminSlideUpdated: {
+		Indonesian: "Slide berhasil diperbarui",
+		Javanese:   "Slide kasil dianyari",
+		English:    "Slide updated",
+	},
+	AdminSlideDeleted: {
+		Indonesian: "Slide berhasil dihapus",
+		Javanese:   "Slide kasil dibusak",
+		English:    "Slide deleted",
+	},
+	AdminSlideKeyTaken: {
+		Indonesian: "Key slide ini udah dipakai di chapter yang sama",
+		Javanese:   "Key slide iki wis dienggo ing chapter sing padha",
+		English:    "This slide key is already used in the chapter",
+	},
+	AdminChoiceSaved: {
+		Indonesian: "Pilihan berhasil disimpan",
+		Javanese:   "Pilihan kasil disimpen",
+		English:    "Choice saved",
+	},
+	AdminChoiceDeleted: {
+		Indonesian: "Pilihan berhasil dihapus",
+		Javanese:   "Pilihan kasil dibusak",
+		English:    "Choice deleted",
+	},
+	AdminChoiceNotFound: {
+		Indonesian: "Pilihan ini ga ketemu",
+		Javanese:   "Pilihan iki ora ketemu",
+		English:    "Choice not found",
+	},
+	AdminChoicesNotAllowed: {
+		Indonesian: "Slide kuis dan terjemahan ga bisa punya pilihan",
+		Javanese:   "Slide kuis lan terjemahan ora bisa duwe pilihan",
+		English:    "Quiz and translation slides can't have choices",
+	},
+	AdminWordsLoaded: {
+		Indonesian: "Kosakata berhasil dimuat",
+		Javanese:   "Tembung kasil dimuat",
+		English:    "Words loaded",
+	},
+	AdminWordCreated: {
+		Indonesian: "Kosakata berhasil ditambahkan",
+		Javanese:   "Tembung kasil ditambahake",
+		English:    "Word added",
+	},
+	AdminWordUpdated: {
+		Indonesian: "Kosakata berhasil diperbarui",
+		Javanese:   "Tembung kasil dianyari",
+		English:    "Word updated",
+	},
+	AdminWordDeleted: {
+		Indonesian: "Kosakata berhasil dihapus",
+		Javanese:   "Tembung kasil dibusak",
+		English:    "Word deleted",
+	},
+	AdminWordNotFound: {
+		Indonesian: "Kosakata ini ga ketemu",
+		Javanese:   "Tembung iki ora ketemu",
+		English:    "Word not found",
+	},
+	AdminWordTaken: {
+		Indonesian: "Kata krama ini udah ada di kamus",
+		Javanese:   "Tembung krama iki wis ana ing kamus",
+		English:    "This krama word is already in the dictionary",
+	},
+	AdminWordInUse: {
+		Indonesian: "Kosakata ini masih dipakai slide atau versi chapter yang udah terbit",
+		Javanese:   "Tembung iki isih dienggo slide utawa versi chapter sing wis terbit",
+		English:    "Slides or published chapter versions still use this word",
+	},
+	AdminChapterPublished: {
+		Indonesian: "Chapter berhasil diterbitkan",
+		Javanese:   "Chapter kasil diterbitake",
+		English:    "Chapter published",
+	},
+	AdminNothingToPublish: {
+		Indonesian: "Draft chapter ini sama dengan versi yang lagi terbit",
+		Javanese:   "Draft chapter iki padha karo versi sing lagi terbit",
+		English:    "The chapter draft is the same as the published version",
+	},
+	AdminVersionsLoaded: {
+		Indonesian: "Daftar versi chapter berhasil dimuat",
+		Javanese:   "Dhaptar versi chapter kasil dimuat",
+		English:    "Chapter versions loaded",
+	},
+	AdminVersionNotFound: {
+		Indonesian: "Versi chapter ini ga ketemu",
+		Javanese:   "Versi chapter iki ora ketemu",
+		English:    "Chapter version not found",
+	},
+	AdminVersionIsPublished: {
+		Indonesian: "Versi ini udah jadi versi yang terbit",
+		Javanese:   "Versi iki wis dadi versi sing terbit",
+		English:    "This version is already the published one",
+	},
+	AdminChapterRolledBack: {
+		Indonesian: "Chapter berhasil dikembalikan ke versi sebelumnya",
+		Javanese:   "Chapter kasil dibalekake menyang versi sadurunge",
+		English:    "Chapter rolled back",
+	},
+}
@@ -9,7 +9,7 @@ import (
 )
 
 type JWTItf interface {
-	CreateAccessToken(userID uuid.UUID, username, email string, exp time.Duration) (string, error)
+	CreateAccessToken(userID uuid.UUID, username, email, role, lang string, exp time.Duration) (string, error)
 	CreateRefreshToken(userID uuid.UUID, exp time.Duration) (string, error)
 	ParseAccessToken(tokenStr string) (*AccessClaims, error)
 }
@@ -18,6 +18,8 @@ type AccessClaims struct {
 	j.RegisteredClaims
 	Username string `json:"username"`
 	Email    string `json:"email"`
+	Role     string `json:"role"`
+	Lang     string `json:"lang,omitempty"` // preferred language, empty follows Accept-Language
 }
 
 type RefreshClaims struct {
@@ -36,7 +38,7 @@ func NewJWT(env *config.Env) JWTItf {
 	}
 }
 
-func (jw *jwt) CreateAccessToken(userID uuid.UUID, username, email string, exp time.Duration) (string, error) {
+func (jw *jwt) CreateAccessToken(userID uuid.UUID, username, email, role, lang string, exp time.Duration) (string, error) {
 	claims := &AccessClaims{
 		RegisteredClaims: j.RegisteredClaims{
 			Subject:   userID.String(),
@@ -45,6 +47,8 @@ func (jw *jwt) CreateAccessToken(userID uuid.UUID, username, email string, exp t
 		},
 		Username: username,
 		Email:    email,
+		Role:     role,
+		Lang:     lang,
 	}
 
 	token := j.NewWithClaims(j.SigningMethodHS256, claims)
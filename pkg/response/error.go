@@ -1,21 +1,34 @@
 package response
 
-import "github.com/go-playground/validator/v10"
+import (
+	"github.com/Ablebil/lathi-be/pkg/i18n"
+	"github.com/go-playground/validator/v10"
+)
 
+// APIError carries message codes from the i18n catalog. Message and Detail
+// hold the Indonesian text until Error localizes them for the request.
 type APIError struct {
 	Type    string            `json:"type"`
+	Code    string            `json:"code"` // stable, safe for clients to switch on
 	Message string            `json:"message"`
 	Detail  string            `json:"detail,omitempty"`
 	Status  int               `json:"status"`
 	Fields  map[string]string `json:"fields,omitempty"` // validation errors
+	Data    any               `json:"data,omitempty"`   // authoritative state the client should resync to
+
+	messageCode string
+	args        []any
 }
 
-func NewAPIError(status int, errType, message, detail string) *APIError {
+func NewAPIError(status int, errType, messageCode, code string, args ...any) *APIError {
 	return &APIError{
-		Type:    errType,
-		Message: message,
-		Detail:  detail,
-		Status:  status,
+		Type:        errType,
+		Code:        code,
+		Message:     i18n.T(i18n.Default, messageCode),
+		Detail:      i18n.T(i18n.Default, code, args...),
+		Status:      status,
+		messageCode: messageCode,
+		args:        args,
 	}
 }
 
@@ -26,41 +39,64 @@ func NewValidationError(err error) *APIError {
 			fields[fe.Field()] = fe.Tag()
 		}
 	}
-	return &APIError{
-		Type:    "validation_error",
-		Message: "Ups, ada data yang ga sesuai nih",
-		Status:  422,
-		Fields:  fields,
+	apiErr := NewAPIError(422, "validation_error", i18n.ErrorValidation, i18n.CommonInvalidFields)
+	apiErr.Fields = fields
+	return apiErr
+}
+
+// NewFieldValidationError reports a body field that passed the struct tags but
+// still can't be accepted.
+func NewFieldValidationError(field, issue string) *APIError {
+	apiErr := NewAPIError(422, "validation_error", i18n.ErrorValidation, i18n.CommonInvalidFields)
+	apiErr.Fields = map[string]string{
+		field: issue,
 	}
+	return apiErr
 }
 
 func NewParamValidationError(field, issue string) *APIError {
-	return &APIError{
-		Type:    "validation_error",
-		Message: "Ups, ada data yang ga sesuai nih",
-		Status:  400,
-		Fields: map[string]string{
-			field: issue,
-		},
+	apiErr := NewAPIError(400, "validation_error", i18n.ErrorValidation, i18n.CommonInvalidFields)
+	apiErr.Fields = map[string]string{
+		field: issue,
 	}
+	return apiErr
 }
 
-// error helpers
-func ErrInternal(detail string) *APIError {
-	return NewAPIError(500, "internal_error", "Coba lagi nanti ya!", detail)
+// Localize rewrites the message and detail in the language.
+func (e *APIError) Localize(lang i18n.Lang) {
+	e.Message = i18n.T(lang, e.messageCode)
+	e.Detail = i18n.T(lang, e.Code, e.args...)
+}
+
+// error helpers, code is the i18n message code of the detail
+func ErrInternal(code string, args ...any) *APIError {
+	return NewAPIError(500, "internal_error", i18n.ErrorInternal, code, args...)
+}
+func ErrNotFound(code string, args ...any) *APIError {
+	return NewAPIError(404, "not_found", i18n.ErrorNotFound, code, args...)
+}
+func ErrUnauthorized(code string, args ...any) *APIError {
+	return NewAPIError(401, "unauthorized", i18n.ErrorUnauthorized, code, args...)
+}
+func ErrBadRequest(code string, args ...any) *APIError {
+	return NewAPIError(400, "bad_request", i18n.ErrorBadRequest, code, args...)
 }
-func ErrNotFound(detail string) *APIError {
-	return NewAPIError(404, "not_found", "Data ga ditemukan", detail)
+func ErrForbidden(code string, args ...any) *APIError {
+	return NewAPIError(403, "forbidden", i18n.ErrorForbidden, code, args...)
 }
-func ErrUnauthorized(detail string) *APIError {
-	return NewAPIError(401, "unauthorized", "Kamu belum login, yuk login dulu", detail)
+func ErrConflict(code string, args ...any) *APIError {
+	return NewAPIError(409, "conflict", i18n.ErrorConflict, code, args...)
 }
-func ErrBadRequest(detail string) *APIError {
-	return NewAPIError(400, "bad_request", "Data yang dikirimkan salah", detail)
+func ErrOutOfSync(code string, data any) *APIError {
+	apiErr := NewAPIError(409, "out_of_sync", i18n.ErrorOutOfSync, code)
+	apiErr.Data = data
+	return apiErr
 }
-func ErrConflict(detail string) *APIError {
-	return NewAPIError(409, "conflict", "Data udah ada sebelumnya", detail)
+func ErrUnprocessable(code string, data any) *APIError {
+	apiErr := NewAPIError(422, "unprocessable", i18n.ErrorUnprocessable, code)
+	apiErr.Data = data
+	return apiErr
 }
-func ErrTooManyRequests(detail string) *APIError {
-	return NewAPIError(429, "too_many_requests", "Terlalu banyak permintaan, coba lagi nanti ya", detail)
+func ErrTooManyRequests(code string, args ...any) *APIError {
+	return NewAPIError(429, "too_many_requests", i18n.ErrorTooManyRequests, code, args...)
 }
@@ -3,13 +3,23 @@ package response
 import (
 	"log/slog"
 
+	"github.com/Ablebil/lathi-be/pkg/i18n"
 	"github.com/gofiber/fiber/v2"
 )
 
-func Success(ctx *fiber.Ctx, status int, message string, data any) error {
+// Lang is the language responses to the request are written in.
+func Lang(ctx *fiber.Ctx) i18n.Lang {
+	if lang, ok := ctx.Locals(i18n.ContextKey).(i18n.Lang); ok && lang.IsValid() {
+		return lang
+	}
+	return i18n.Parse(ctx.Get(fiber.HeaderAcceptLanguage))
+}
+
+// Success writes the message for the i18n code in the request language.
+func Success(ctx *fiber.Ctx, status int, code string, data any) error {
 	return ctx.Status(status).JSON(fiber.Map{
 		"success": true,
-		"message": message,
+		"message": i18n.T(Lang(ctx), code),
 		"data":    data,
 	})
 }
@@ -18,6 +28,7 @@ func Error(ctx *fiber.Ctx, apiErr *APIError, err error) error {
 	slog.Error("API error",
 		"status", apiErr.Status,
 		"type", apiErr.Type,
+		"code", apiErr.Code,
 		"message", apiErr.Message,
 		"detail", apiErr.Detail,
 		"fields", apiErr.Fields,
@@ -25,10 +36,13 @@ func Error(ctx *fiber.Ctx, apiErr *APIError, err error) error {
 		"path", ctx.Path(),
 	)
 
+	apiErr.Localize(Lang(ctx))
+
 	resp := fiber.Map{
 		"success": false,
 		"error": fiber.Map{
 			"type":    apiErr.Type,
+			"code":    apiErr.Code,
 			"message": apiErr.Message,
 			"detail":  apiErr.Detail,
 		},
@@ -38,5 +52,9 @@ func Error(ctx *fiber.Ctx, apiErr *APIError, err error) error {
 		resp["error"].(fiber.Map)["fields"] = apiErr.Fields
 	}
 
+	if apiErr.Data != nil {
+		resp["error"].(fiber.Map)["data"] = apiErr.Data
+	}
+
 	return ctx.Status(apiErr.Status).JSON(resp)
 }